@@ -35,6 +35,22 @@ type CLIInput struct {
 	SmtpUser string `env:"SMTP_USER" name:"smtp-user" help:"User to authenticate against the SMTP server"`
 	// SmtpUser holds the password to authenticate against the SMTP server
 	SmtpPassword string `env:"SMTP_PASSWORD" name:"smtp-password" help:"Password to authenticate against the SMTP server"`
+	// MailWorkers is the number of emails which can be sent at the same time. Defaults to 2.
+	MailWorkers int `env:"MAIL_WORKERS" default:"2" name:"mail-workers" help:"Number of emails which can be sent at the same time"`
+	// MailMaxAttempts is the number of times an email is tried to be sent before giving up. Defaults to 8.
+	MailMaxAttempts int `env:"MAIL_MAX_ATTEMPTS" default:"8" name:"mail-max-attempts" help:"Number of times an email is tried to be sent before giving up"`
+	// MailTransport chooses how emails are sent: through an SMTP server, a local sendmail binary, stored in a local spool folder or posted to an HTTP mail API. Defaults to smtp.
+	MailTransport string `env:"MAIL_TRANSPORT" default:"smtp" enum:"smtp,sendmail,spool,http" name:"mail-transport" help:"How emails are sent: smtp, sendmail, spool or http"`
+	// MailFrom is the address emails are sent from. Required by all transports but smtp, which defaults to the SMTP user.
+	MailFrom string `env:"MAIL_FROM" name:"mail-from" help:"Address emails are sent from. Required by all transports but smtp, which defaults to the SMTP user"`
+	// SendmailPath is the location of the sendmail binary used by the sendmail transport
+	SendmailPath string `env:"SENDMAIL_PATH" default:"/usr/sbin/sendmail" name:"sendmail-path" help:"Location of the sendmail binary used by the sendmail transport"`
+	// MailSpoolDir is the maildir folder where the spool transport stores emails instead of sending them
+	MailSpoolDir string `env:"MAIL_SPOOL_DIR" name:"mail-spool-dir" help:"Maildir folder where the spool transport stores emails instead of sending them" type:"path"`
+	// MailAPIURL is the endpoint emails are posted to as JSON by the http transport
+	MailAPIURL string `env:"MAIL_API_URL" name:"mail-api-url" help:"Endpoint emails are posted to as JSON by the http transport"`
+	// MailAPIKey is the bearer token used to authenticate against the mail API
+	MailAPIKey string `env:"MAIL_API_KEY" name:"mail-api-key" help:"Bearer token used to authenticate against the mail API"`
 	// JwtSecret stores the string to use to sign JWTs
 	JwtSecret string `env:"JWT_SECRET" short:"s" name:"jwt-secret" help:"String to use to sign JWTs"`
 	// RequireAuth is a switch to enable the application to require authentication to access any route if true
@@ -68,4 +84,44 @@ type CLIInput struct {
 	InviteEmailListMaxLength int `env:"INVITE_EMAIL_LIST_MAX_LENGTH" default:"2000" name:"invite-email-list-max-length" help:"Maximum length in bytes of the invitation email list field. Defaults to 2000."`
 	// InviteMaxRecipients is the maximum number of distinct addresses per invitation submit. Defaults to 50.
 	InviteMaxRecipients int `env:"INVITE_MAX_RECIPIENTS" default:"50" name:"invite-max-recipients" help:"Maximum number of distinct email addresses per invitation form submit. Defaults to 50."`
+	// ConversionCacheMaxSize is the maximum size of the document conversions kept in the cache directory, in megabytes. Defaults to 1024.
+	ConversionCacheMaxSize int `env:"CONVERSION_CACHE_MAX_SIZE" default:"1024" name:"conversion-cache-max-size" help:"Maximum size in megabytes of the document conversions kept in the cache directory, removing the least recently used ones when exceeded. Set this to 0 to unlimit the cache size."`
+	// ConversionWorkers is the number of document conversions which can run at the same time. Defaults to 2.
+	ConversionWorkers int `env:"CONVERSION_WORKERS" default:"2" name:"conversion-workers" help:"Number of document conversions which can run at the same time"`
+	// KepubPregenerate enables converting EPUB files to KEPUB in the background for users who prefer that format
+	KepubPregenerate bool `env:"KEPUB_PREGENERATE" default:"false" name:"kepub-pregenerate" help:"Convert uploaded EPUB files and the ones in the reading queues of users who prefer KEPUB in the background, so they do not wait for the conversion when downloading them"`
+	// LDAPURL points to the LDAP server used to authenticate users, e. g. ldap://ldap.example.com:389 or ldaps://ldap.example.com:636
+	LDAPURL string `env:"LDAP_URL" name:"ldap-url" help:"URL of the LDAP server used to authenticate users, e. g. ldaps://ldap.example.com:636. LDAP authentication is disabled if empty"`
+	// LDAPStartTLS upgrades plain LDAP connections to TLS
+	LDAPStartTLS bool `env:"LDAP_START_TLS" default:"false" name:"ldap-start-tls" help:"Upgrade plain LDAP connections to TLS using StartTLS"`
+	// LDAPBindDN is the distinguished name of the service account used to look up users
+	LDAPBindDN string `env:"LDAP_BIND_DN" name:"ldap-bind-dn" help:"Distinguished name of the service account used to look up users. Anonymous bind is used if empty"`
+	// LDAPBindPassword is the password of the service account used to look up users
+	LDAPBindPassword string `env:"LDAP_BIND_PASSWORD" name:"ldap-bind-password" help:"Password of the service account used to look up users"`
+	// LDAPBaseDN is the distinguished name where user searches start
+	LDAPBaseDN string `env:"LDAP_BASE_DN" name:"ldap-base-dn" help:"Distinguished name where user searches start, e. g. ou=people,dc=example,dc=com"`
+	// LDAPUserFilter is the filter used to find the user entry, where {login} is replaced by the email entered in the login form
+	LDAPUserFilter string `env:"LDAP_USER_FILTER" default:"(&(objectClass=person)(mail={login}))" name:"ldap-user-filter" help:"Filter used to find users, {login} is replaced by the email entered in the login form"`
+	// LDAPUsernameAttribute is the attribute holding the username
+	LDAPUsernameAttribute string `env:"LDAP_USERNAME_ATTRIBUTE" default:"uid" name:"ldap-username-attribute" help:"LDAP attribute holding the username"`
+	// LDAPNameAttribute is the attribute holding the user's full name
+	LDAPNameAttribute string `env:"LDAP_NAME_ATTRIBUTE" default:"cn" name:"ldap-name-attribute" help:"LDAP attribute holding the user's full name"`
+	// LDAPEmailAttribute is the attribute holding the user's email
+	LDAPEmailAttribute string `env:"LDAP_EMAIL_ATTRIBUTE" default:"mail" name:"ldap-email-attribute" help:"LDAP attribute holding the user's email"`
+	// LDAPGroupAttribute is the user attribute listing the groups the user belongs to
+	LDAPGroupAttribute string `env:"LDAP_GROUP_ATTRIBUTE" default:"memberOf" name:"ldap-group-attribute" help:"LDAP user attribute listing the groups the user belongs to"`
+	// LDAPAdminGroup is the distinguished name of the group whose members are given the admin role
+	LDAPAdminGroup string `env:"LDAP_ADMIN_GROUP" name:"ldap-admin-group" help:"Distinguished name of the LDAP group whose members are given the admin role"`
+	// LDAPUserGroup is the distinguished name of the group whose members are allowed to log in
+	LDAPUserGroup string `env:"LDAP_USER_GROUP" name:"ldap-user-group" help:"Distinguished name of the LDAP group whose members are allowed to log in. All directory users are allowed if empty"`
+	// ProxyAuth enables authenticating users through the headers injected by a reverse proxy such as Authelia or oauth2-proxy
+	ProxyAuth bool `env:"PROXY_AUTH" default:"false" name:"proxy-auth" help:"Authenticate users through the headers injected by a trusted reverse proxy"`
+	// ProxyAuthTrustedCIDRs lists the networks from which proxy authentication headers are trusted
+	ProxyAuthTrustedCIDRs []string `env:"PROXY_AUTH_TRUSTED_CIDRS" name:"proxy-auth-trusted-cidrs" help:"Comma-separated list of CIDRs of the reverse proxies whose authentication headers are trusted"`
+	// ProxyAuthUserHeader is the header holding the username of the authenticated user
+	ProxyAuthUserHeader string `env:"PROXY_AUTH_USER_HEADER" default:"Remote-User" name:"proxy-auth-user-header" help:"Header holding the username of the user authenticated by the reverse proxy"`
+	// ProxyAuthEmailHeader is the header holding the email of the authenticated user, used to match it with a Coreander user
+	ProxyAuthEmailHeader string `env:"PROXY_AUTH_EMAIL_HEADER" default:"Remote-Email" name:"proxy-auth-email-header" help:"Header holding the email of the user authenticated by the reverse proxy"`
+	// ProxyAuthNameHeader is the header holding the display name of the authenticated user
+	ProxyAuthNameHeader string `env:"PROXY_AUTH_NAME_HEADER" default:"Remote-Name" name:"proxy-auth-name-header" help:"Header holding the display name of the user authenticated by the reverse proxy"`
 }
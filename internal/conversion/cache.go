@@ -0,0 +1,216 @@
+package conversion
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// ConversionTimeout is the maximum time a conversion may take
+const ConversionTimeout = 10 * time.Minute
+
+const temporaryExtension = ".tmp"
+
+// Cache converts documents using the converters of a registry and stores the results in the cache directory,
+// so each document is converted only once to each format. Conversions are identified by the ID of the document
+// and the modification time of its file, so new ones are generated when the file changes. When the cache grows
+// beyond its maximum size, the least recently used conversions are removed.
+// Conversions are run by a limited number of workers, so converting many documents at once does not exhaust
+// the resources of the server.
+type Cache struct {
+	fs         afero.Fs
+	dir        string
+	maxSize    int64
+	registry   *Registry
+	workers    chan struct{}
+	mu         sync.Mutex
+	converting map[string]chan struct{}
+}
+
+// NewCache returns a cache storing conversions in the conversions folder of cacheDir. A maxSize of 0 or less,
+// in bytes, means the cache is not limited. At least one worker is used.
+func NewCache(fs afero.Fs, cacheDir string, maxSize int64, registry *Registry, workers int) *Cache {
+	return &Cache{
+		fs:         fs,
+		dir:        filepath.Join(cacheDir, "conversions"),
+		maxSize:    maxSize,
+		registry:   registry,
+		workers:    make(chan struct{}, max(workers, 1)),
+		converting: map[string]chan struct{}{},
+	}
+}
+
+// Targets returns the formats a document can be converted to
+func (c *Cache) Targets(from string) []string {
+	return c.registry.Targets(from)
+}
+
+// Convert returns the conversion of a document file to another format, converting it first if it is not cached.
+// ErrUnsupportedConversion is returned if there is no converter between both formats. Callers must close
+// the returned file.
+func (c *Cache) Convert(documentID string, modTime time.Time, source io.ReaderAt, size int64, from, to string) (afero.File, fs.FileInfo, error) {
+	converter, ok := c.registry.Converter(from, to)
+	if !ok {
+		return nil, nil, ErrUnsupportedConversion
+	}
+
+	name := filepath.Join(c.dir, c.prefix(documentID)+strconv.FormatInt(modTime.UnixNano(), 36)+"."+to)
+
+	for {
+		if file, info, err := c.open(name); err == nil {
+			return file, info, nil
+		}
+
+		c.mu.Lock()
+		done, ok := c.converting[name]
+		if !ok {
+			done = make(chan struct{})
+			c.converting[name] = done
+		}
+		c.mu.Unlock()
+
+		// Another request is converting the same file, so wait for it and use its result
+		if ok {
+			<-done
+			continue
+		}
+
+		c.workers <- struct{}{}
+		err := c.convert(converter, name, source, size)
+		<-c.workers
+
+		c.mu.Lock()
+		delete(c.converting, name)
+		close(done)
+		c.mu.Unlock()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.removeOutdated(documentID, to, name)
+		c.evict(name)
+	}
+}
+
+// Remove deletes all conversions of a document
+func (c *Cache) Remove(documentID string) {
+	c.removeOutdated(documentID, "", "")
+}
+
+// open returns a cached conversion, marking it as recently used
+func (c *Cache) open(name string) (afero.File, fs.FileInfo, error) {
+	file, err := c.fs.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if err := c.fs.Chtimes(name, now, now); err != nil {
+		log.Printf("error updating access time of %s: %s\n", name, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+// convert writes the conversion to a temporary file first, so incomplete conversions are never served
+func (c *Cache) convert(converter Converter, name string, source io.ReaderAt, size int64) error {
+	if err := c.fs.MkdirAll(c.dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	tmp := name + temporaryExtension
+	file, err := c.fs.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ConversionTimeout)
+	defer cancel()
+	if err := converter.Convert(ctx, source, size, file); err != nil {
+		file.Close()
+		c.fs.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		c.fs.Remove(tmp)
+		return err
+	}
+	return c.fs.Rename(tmp, name)
+}
+
+// removeOutdated deletes the conversions of a document to a format, or to any format if it is empty,
+// except the one with the passed name
+func (c *Cache) removeOutdated(documentID, format, keep string) {
+	entries, err := afero.ReadDir(c.fs, c.dir)
+	if err != nil {
+		return
+	}
+	prefix := c.prefix(documentID)
+	for _, entry := range entries {
+		name := filepath.Join(c.dir, entry.Name())
+		if !strings.HasPrefix(entry.Name(), prefix) || strings.HasSuffix(entry.Name(), temporaryExtension) || name == keep {
+			continue
+		}
+		if format != "" && filepath.Ext(entry.Name()) != "."+format {
+			continue
+		}
+		if err := c.fs.Remove(name); err != nil {
+			log.Printf("error removing cached conversion %s: %s\n", name, err)
+		}
+	}
+}
+
+// evict removes the least recently used conversions until the cache fits its maximum size.
+// The conversion with the passed name is kept, as it is about to be served.
+func (c *Cache) evict(keep string) {
+	if c.maxSize <= 0 {
+		return
+	}
+	entries, err := afero.ReadDir(c.fs, c.dir)
+	if err != nil {
+		log.Printf("error reading conversions cache: %s\n", err)
+		return
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.Size()
+	}
+	slices.SortFunc(entries, func(a, b fs.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			return
+		}
+		name := filepath.Join(c.dir, entry.Name())
+		if name == keep || strings.HasSuffix(entry.Name(), temporaryExtension) {
+			continue
+		}
+		if err := c.fs.Remove(name); err != nil {
+			log.Printf("error removing cached conversion %s: %s\n", name, err)
+			continue
+		}
+		total -= entry.Size()
+	}
+}
+
+// prefix identifies the conversions of a document. Document IDs are paths, so they are hashed to be used as file names.
+func (c *Cache) prefix(documentID string) string {
+	return fmt.Sprintf("%x-", sha1.Sum([]byte(documentID)))
+}
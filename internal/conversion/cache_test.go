@@ -0,0 +1,126 @@
+package conversion_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/conversion"
+)
+
+type upperCase struct {
+	calls atomic.Int32
+}
+
+func (u *upperCase) Convert(ctx context.Context, source io.ReaderAt, size int64, destination io.Writer) error {
+	u.calls.Add(1)
+	contents, err := io.ReadAll(io.NewSectionReader(source, 0, size))
+	if err != nil {
+		return err
+	}
+	_, err = destination.Write([]byte(strings.ToUpper(string(contents))))
+	return err
+}
+
+func TestRegistryTargets(t *testing.T) {
+	registry := conversion.NewRegistry()
+	registry.Register(conversion.EPUB, conversion.MOBI, &upperCase{})
+	registry.Register(conversion.EPUB, conversion.AZW3, &upperCase{})
+
+	if targets := registry.Targets(conversion.EPUB); !slices.Equal(targets, []string{conversion.AZW3, conversion.MOBI}) {
+		t.Errorf("Expected azw3 and mobi targets, got %v", targets)
+	}
+	if targets := registry.Targets(conversion.PDF); len(targets) != 0 {
+		t.Errorf("Expected no targets, got %v", targets)
+	}
+}
+
+func TestCache(t *testing.T) {
+	source := strings.NewReader("contents")
+	modTime := time.Now()
+
+	newCache := func(maxSize int64) (*conversion.Cache, *upperCase, afero.Fs) {
+		converter := &upperCase{}
+		registry := conversion.NewRegistry()
+		registry.Register(conversion.EPUB, conversion.MOBI, converter)
+		registry.Register(conversion.EPUB, conversion.AZW3, converter)
+		fs := afero.NewMemMapFs()
+		return conversion.NewCache(fs, "cache", maxSize, registry, 1), converter, fs
+	}
+
+	convert := func(t *testing.T, cache *conversion.Cache, documentID string, modTime time.Time, to string) string {
+		t.Helper()
+		file, _, err := cache.Convert(documentID, modTime, source, source.Size(), conversion.EPUB, to)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer file.Close()
+		contents, err := io.ReadAll(file)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return string(contents)
+	}
+
+	cachedFiles := func(fs afero.Fs) int {
+		entries, _ := afero.ReadDir(fs, "cache/conversions")
+		return len(entries)
+	}
+
+	t.Run("Documents are converted only once", func(t *testing.T) {
+		cache, converter, _ := newCache(0)
+		for range 2 {
+			if contents := convert(t, cache, "doc.epub", modTime, conversion.MOBI); contents != "CONTENTS" {
+				t.Errorf("Expected converted contents, got '%s'", contents)
+			}
+		}
+		if calls := converter.calls.Load(); calls != 1 {
+			t.Errorf("Expected 1 conversion, got %d", calls)
+		}
+	})
+
+	t.Run("Documents are converted again when their files change", func(t *testing.T) {
+		cache, converter, fs := newCache(0)
+		convert(t, cache, "doc.epub", modTime, conversion.MOBI)
+		convert(t, cache, "doc.epub", modTime.Add(time.Second), conversion.MOBI)
+		if calls := converter.calls.Load(); calls != 2 {
+			t.Errorf("Expected 2 conversions, got %d", calls)
+		}
+		if files := cachedFiles(fs); files != 1 {
+			t.Errorf("Expected outdated conversions to be removed, got %d cached files", files)
+		}
+	})
+
+	t.Run("Conversions are removed with their documents", func(t *testing.T) {
+		cache, _, fs := newCache(0)
+		convert(t, cache, "doc.epub", modTime, conversion.MOBI)
+		convert(t, cache, "doc.epub", modTime, conversion.AZW3)
+		convert(t, cache, "other.epub", modTime, conversion.MOBI)
+		cache.Remove("doc.epub")
+		if files := cachedFiles(fs); files != 1 {
+			t.Errorf("Expected 1 cached file, got %d", files)
+		}
+	})
+
+	t.Run("Least recently used conversions are evicted when the cache is full", func(t *testing.T) {
+		cache, _, fs := newCache(source.Size())
+		convert(t, cache, "doc.epub", modTime, conversion.MOBI)
+		convert(t, cache, "other.epub", modTime, conversion.MOBI)
+		if files := cachedFiles(fs); files != 1 {
+			t.Errorf("Expected 1 cached file, got %d", files)
+		}
+	})
+
+	t.Run("Conversions without converter fail", func(t *testing.T) {
+		cache, _, _ := newCache(0)
+		if _, _, err := cache.Convert("doc.epub", modTime, source, source.Size(), conversion.PDF, conversion.EPUB); !errors.Is(err, conversion.ErrUnsupportedConversion) {
+			t.Errorf("Expected unsupported conversion error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// Package conversion converts documents between the formats supported by e-readers.
+package conversion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+)
+
+// Formats documents can be converted from or to
+const (
+	EPUB  = "epub"
+	KEPUB = "kepub"
+	PDF   = "pdf"
+	AZW3  = "azw3"
+	MOBI  = "mobi"
+)
+
+// ErrUnsupportedConversion is returned when there is no converter available between two formats
+var ErrUnsupportedConversion = errors.New("conversion not available")
+
+// Converter converts a document to another format, writing the result to destination
+type Converter interface {
+	Convert(ctx context.Context, source io.ReaderAt, size int64, destination io.Writer) error
+}
+
+// Registry keeps the converters available between each pair of formats
+type Registry struct {
+	converters map[string]map[string]Converter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{converters: map[string]map[string]Converter{}}
+}
+
+// DefaultRegistry returns a registry with the converters written in Go, plus the ones provided by Calibre's
+// ebook-convert if it is found in the PATH
+func DefaultRegistry() *Registry {
+	registry := NewRegistry()
+	if command, err := exec.LookPath("ebook-convert"); err == nil {
+		for _, pair := range [][2]string{{EPUB, AZW3}, {EPUB, MOBI}, {EPUB, PDF}, {PDF, EPUB}} {
+			registry.Register(pair[0], pair[1], External{Command: command, From: pair[0], To: pair[1]})
+		}
+	}
+	registry.Register(EPUB, KEPUB, Kepub{})
+	return registry
+}
+
+// Register makes a converter available between two formats, replacing any previous one
+func (r *Registry) Register(from, to string, converter Converter) {
+	if r.converters[from] == nil {
+		r.converters[from] = map[string]Converter{}
+	}
+	r.converters[from][to] = converter
+}
+
+// Converter returns the converter between two formats, if any
+func (r *Registry) Converter(from, to string) (Converter, bool) {
+	converter, ok := r.converters[from][to]
+	return converter, ok
+}
+
+// Targets returns the formats a document can be converted to, sorted alphabetically
+func (r *Registry) Targets(from string) []string {
+	targets := make([]string, 0, len(r.converters[from]))
+	for to := range r.converters[from] {
+		targets = append(targets, to)
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	switch format {
+	case EPUB, KEPUB:
+		return "application/epub+zip"
+	case PDF:
+		return "application/pdf"
+	case AZW3:
+		return "application/vnd.amazon.ebook"
+	case MOBI:
+		return "application/x-mobipocket-ebook"
+	}
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension used for a format, including the leading dot
+func Extension(format string) string {
+	if format == KEPUB {
+		return ".kepub.epub"
+	}
+	return "." + format
+}
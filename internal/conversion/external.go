@@ -0,0 +1,64 @@
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// External converts documents running an external program, such as Calibre's ebook-convert, which receives
+// the paths of the source and destination files and guesses their formats from their extensions
+type External struct {
+	Command string
+	From    string
+	To      string
+}
+
+func (e External) Convert(ctx context.Context, source io.ReaderAt, size int64, destination io.Writer) error {
+	dir, err := os.MkdirTemp("", "coreander-conversion-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	sourcePath := filepath.Join(dir, "source"+Extension(e.From))
+	destinationPath := filepath.Join(dir, "converted"+Extension(e.To))
+
+	if err := writeFile(sourcePath, io.NewSectionReader(source, 0, size)); err != nil {
+		return err
+	}
+
+	if output, err := exec.CommandContext(ctx, e.Command, sourcePath, destinationPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("error running %s: %w: %s", filepath.Base(e.Command), err, strings.TrimSpace(lastLine(string(output))))
+	}
+
+	converted, err := os.Open(destinationPath)
+	if err != nil {
+		return err
+	}
+	defer converted.Close()
+	_, err = io.Copy(destination, converted)
+	return err
+}
+
+func writeFile(path string, contents io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, contents); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// lastLine returns the last line of a program output, which is where errors are usually reported
+func lastLine(output string) string {
+	output = strings.TrimSpace(output)
+	return output[strings.LastIndex(output, "\n")+1:]
+}
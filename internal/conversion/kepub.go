@@ -0,0 +1,20 @@
+package conversion
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+
+	"github.com/pgaskin/kepubify/v4/kepub"
+)
+
+// Kepub converts EPUB files to the KEPUB format used by Kobo e-readers
+type Kepub struct{}
+
+func (Kepub) Convert(ctx context.Context, source io.ReaderAt, size int64, destination io.Writer) error {
+	z, err := zip.NewReader(source, size)
+	if err != nil {
+		return err
+	}
+	return kepub.NewConverter().Convert(ctx, destination, z)
+}
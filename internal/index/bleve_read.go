@@ -187,6 +187,28 @@ func (b *BleveIndexer) addFilters(searchFields SearchFields, filtersQuery *query
 		q.SetField("Words")
 		filtersQuery.AddQuery(q)
 	}
+	if searchFields.Slugs != nil {
+		slugsQuery := bleve.NewDisjunctionQuery()
+		for _, slug := range searchFields.Slugs {
+			q := bleve.NewTermQuery(slug)
+			q.SetField("Slug")
+			slugsQuery.AddQuery(q)
+		}
+		if len(slugsQuery.Disjuncts) > 0 {
+			filtersQuery.AddQuery(slugsQuery)
+		} else {
+			filtersQuery.AddQuery(bleve.NewMatchNoneQuery())
+		}
+	}
+	if len(searchFields.ExcludeSlugs) > 0 {
+		excludeQuery := bleve.NewBooleanQuery()
+		for _, slug := range searchFields.ExcludeSlugs {
+			q := bleve.NewTermQuery(slug)
+			q.SetField("Slug")
+			excludeQuery.AddMustNot(q)
+		}
+		filtersQuery.AddQuery(excludeQuery)
+	}
 	if searchFields.IllustratedOnly && b.illustratedMinAmount > 0 {
 		minIllustrations := float64(b.illustratedMinAmount)
 		q := bleve.NewNumericRangeQuery(&minIllustrations, nil)
@@ -322,35 +344,39 @@ func (b *BleveIndexer) Document(slug string) (Document, error) {
 	return hydrateDocument(searchResult.Hits[0]), nil
 }
 
-// IndexedFile holds the bytes and metadata for a document download.
+// IndexedFile holds an open document file and the metadata needed to serve it. Callers must close Content.
 type IndexedFile struct {
 	Document    Document
-	Data        []byte
+	Content     afero.File
 	FileName    string
 	ContentType string
+	Size        int64
+	ModTime     time.Time
 }
 
-// File returns the raw document payload and metadata for the given slug.
+// File opens the document file for the given slug, so it can be streamed without loading it in memory.
 func (b *BleveIndexer) File(slug string) (*IndexedFile, error) {
 	doc, err := b.Document(slug)
 	if err != nil || doc.ID == "" {
 		return nil, ErrDocumentNotFound
 	}
 	fullPath := filepath.Join(b.libraryPath, doc.ID)
-	exists, err := afero.Exists(b.fs, fullPath)
-	if err != nil || !exists {
+	info, err := b.fs.Stat(fullPath)
+	if err != nil {
 		return nil, errors.New("document file not found")
 	}
-	data, err := afero.ReadFile(b.fs, fullPath)
+	content, err := b.fs.Open(fullPath)
 	if err != nil {
 		return nil, err
 	}
 	ext := strings.ToLower(filepath.Ext(doc.ID))
 	result := &IndexedFile{
 		Document:    doc,
-		Data:        data,
+		Content:     content,
 		FileName:    filepath.Base(doc.ID),
 		ContentType: "application/pdf",
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
 	}
 	if ext == ".epub" {
 		result.ContentType = "application/epub+zip"
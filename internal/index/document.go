@@ -18,6 +18,10 @@ type SearchFields struct {
 	WordsPerMinute  float64
 	IllustratedOnly bool
 	SortBy          []string
+	// Slugs restricts results to the documents with these slugs when not nil. An empty, non-nil slice matches no documents.
+	Slugs []string
+	// ExcludeSlugs leaves out of the results the documents with these slugs
+	ExcludeSlugs []string
 }
 
 type Document struct {
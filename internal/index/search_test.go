@@ -124,6 +124,55 @@ func TestLanguageFilter(t *testing.T) {
 	}
 }
 
+func TestSearchBySlugs(t *testing.T) {
+	indexMem, err := bleve.NewMemOnly(index.CreateDocumentsMapping())
+	if err != nil {
+		t.Fatalf("Error initialising index: %v", err)
+	}
+
+	mockMetadataReaders := map[string]metadata.Reader{
+		".epub": epubTestReader{info: languageFilterLibrary()},
+	}
+
+	appFS := afero.NewMemMapFs()
+	appFS.MkdirAll("lib", 0755)
+	afero.WriteFile(appFS, "lib/english_book.epub", []byte(""), 0644)
+	afero.WriteFile(appFS, "lib/spanish_book.epub", []byte(""), 0644)
+	afero.WriteFile(appFS, "lib/french_book.epub", []byte(""), 0644)
+
+	authorsIndexMem, _ := bleve.NewMemOnly(index.CreateAuthorsMapping())
+	idx := index.NewBleve(indexMem, authorsIndexMem, appFS, "lib", mockMetadataReaders, index.Config{})
+
+	if err = idx.AddLibrary(1, true, 0); err != nil {
+		t.Fatalf("Error indexing: %s", err.Error())
+	}
+
+	all, err := idx.Search(index.SearchFields{}, 1, 10)
+	if err != nil {
+		t.Fatalf("Error searching: %s", err.Error())
+	}
+	if all.TotalHits() != 3 {
+		t.Fatalf("Expected 3 documents, got %d", all.TotalHits())
+	}
+
+	slugs := []string{all.Hits()[0].Slug, all.Hits()[2].Slug}
+	res, err := idx.Search(index.SearchFields{Slugs: slugs}, 1, 10)
+	if err != nil {
+		t.Fatalf("Error searching: %s", err.Error())
+	}
+	if res.TotalHits() != 2 {
+		t.Errorf("Expected 2 documents, got %d", res.TotalHits())
+	}
+
+	res, err = idx.Search(index.SearchFields{Keywords: "book", Slugs: []string{}}, 1, 10)
+	if err != nil {
+		t.Fatalf("Error searching: %s", err.Error())
+	}
+	if res.TotalHits() != 0 {
+		t.Errorf("Expected no documents when filtering by an empty set of slugs, got %d", res.TotalHits())
+	}
+}
+
 // illustratedMockReader returns metadata with configurable Illustrations per file (for testing IllustratedOnly filter).
 type illustratedMockReader struct {
 	illustrationsByFile map[string]int
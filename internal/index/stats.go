@@ -0,0 +1,93 @@
+package index
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/gosimple/slug"
+)
+
+// Facet holds the number of documents sharing the same value in a field
+type Facet struct {
+	Term  string
+	Count int
+}
+
+// Stats summarises the contents of the library
+type Stats struct {
+	Documents uint64
+	Formats   []Facet
+	Languages []Facet
+	Subjects  []Facet
+}
+
+// Stats returns the number of indexed documents, grouped by format, language and subject.
+// Only the subjectsLimit subjects with more documents are returned.
+func (b *BleveIndexer) Stats(subjectsLimit int) (Stats, error) {
+	searchRequest := bleve.NewSearchRequest(bleve.NewMatchAllQuery())
+	searchRequest.Size = 0
+	searchRequest.AddFacet("formats", bleve.NewFacetRequest("Format", 100))
+	searchRequest.AddFacet("languages", bleve.NewFacetRequest("Language", 10000))
+	searchRequest.AddFacet("subjects", bleve.NewFacetRequest("Subjects", 10000))
+
+	searchResult, err := b.documentsIdx.Search(searchRequest)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	// Format is not a keyword field, so its terms are lowercased by the analyzer
+	formats := groupFacet(searchResult.Facets["formats"], strings.ToUpper, strings.ToUpper)
+	// Languages are grouped by their two-letter base code, as in Languages()
+	languages := groupFacet(searchResult.Facets["languages"], func(term string) string {
+		if term == "default_analyzer" || len(term) < 2 {
+			return ""
+		}
+		return term[:2]
+	}, func(term string) string { return term[:2] })
+	// Subjects variants like "cronica" and "Crónica" are grouped by slug, as in Subjects()
+	subjects := groupFacet(searchResult.Facets["subjects"], slug.Make, normalizeSubjectName)
+	if len(subjects) > subjectsLimit {
+		subjects = subjects[:subjectsLimit]
+	}
+
+	return Stats{
+		Documents: searchResult.Total,
+		Formats:   formats,
+		Languages: languages,
+		Subjects:  subjects,
+	}, nil
+}
+
+// groupFacet adds up the counts of the facet terms sharing the same key, naming each group after
+// the first term found for it, and returns the groups sorted by count in descending order
+func groupFacet(facet *search.FacetResult, key func(string) string, name func(string) string) []Facet {
+	facets := []Facet{}
+	if facet == nil || facet.Terms == nil {
+		return facets
+	}
+
+	positions := make(map[string]int)
+	for _, term := range facet.Terms.Terms() {
+		k := ""
+		if term.Term != "" {
+			k = key(term.Term)
+		}
+		if k == "" {
+			continue
+		}
+		if i, ok := positions[k]; ok {
+			facets[i].Count += term.Count
+			continue
+		}
+		positions[k] = len(facets)
+		facets = append(facets, Facet{Term: name(term.Term), Count: term.Count})
+	}
+
+	slices.SortStableFunc(facets, func(a, b Facet) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return facets
+}
@@ -0,0 +1,169 @@
+package webserver_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestActivityFeed(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	follow := func(t *testing.T, cookie *http.Cookie, username string, expectedStatus int) {
+		t.Helper()
+
+		response, err := postRequest(url.Values{}, cookie, app, "/users/"+username+"/followers", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	review := func(t *testing.T, cookie *http.Cookie, slug string) {
+		t.Helper()
+
+		response, err := putRequest(url.Values{"rating": {"4"}, "text": {"Nice"}}, cookie, app, "/documents/"+slug+"/review", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+	}
+
+	page := func(t *testing.T, cookie *http.Cookie, URL string, expectedStatus int) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	activities := func(t *testing.T) int64 {
+		t.Helper()
+
+		var count int64
+		db.Model(&model.Activity{}).Count(&count)
+		return count
+	}
+
+	t.Run("Nothing is recorded for users not sharing their activity", func(t *testing.T) {
+		review(t, regularCookie, testDocSlug)
+		if count := activities(t); count != 0 {
+			t.Errorf("Expected no activity to be recorded, got %d", count)
+		}
+	})
+
+	t.Run("Users cannot follow themselves", func(t *testing.T) {
+		follow(t, adminCookie, "admin", http.StatusBadRequest)
+		follow(t, adminCookie, "unknown", http.StatusNotFound)
+	})
+
+	t.Run("Followed users' activity appears in the feed", func(t *testing.T) {
+		db.Model(&model.User{}).Where("username = ?", "regular").Update("share_activity", true)
+
+		follow(t, adminCookie, "regular", http.StatusNoContent)
+		// Following twice is harmless
+		follow(t, adminCookie, "regular", http.StatusNoContent)
+
+		review(t, regularCookie, testDocSlug)
+		// Repeated actions on the same document are recorded only once
+		review(t, regularCookie, testDocSlug)
+		if count := activities(t); count != 1 {
+			t.Errorf("Expected 1 activity to be recorded, got %d", count)
+		}
+
+		doc := page(t, adminCookie, "/feed", http.StatusOK)
+		if got := doc.Find("#activity-list li.activity").Length(); got != 1 {
+			t.Fatalf("Expected 1 activity in the feed, got %d", got)
+		}
+		if got, _ := doc.Find("#activity-list li.activity").Attr("data-type"); got != model.ActivityReviewed {
+			t.Errorf("Expected a review activity, got '%s'", got)
+		}
+		if got := doc.Find("#following li").Length(); got != 1 {
+			t.Errorf("Expected 1 followed user, got %d", got)
+		}
+
+		doc = page(t, adminCookie, "/users/regular/profile", http.StatusOK)
+		if got := doc.Find("#activity-list li.activity").Length(); got != 1 {
+			t.Errorf("Expected 1 activity in the profile, got %d", got)
+		}
+	})
+
+	t.Run("Setting a completion date is shown as finishing the document", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/documents/john-doe-test-epub/complete", strings.NewReader(`{"completed_on":"2024-03-15"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(regularCookie)
+		response, err := app.Test(req)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		doc := page(t, adminCookie, "/feed", http.StatusOK)
+		if got := doc.Find(`#activity-list li.activity[data-type="` + model.ActivityFinished + `"]`).Length(); got != 1 {
+			t.Errorf("Expected the finished document to appear in the feed, got %d", got)
+		}
+	})
+
+	t.Run("Activity is hidden when the user stops sharing it", func(t *testing.T) {
+		db.Model(&model.User{}).Where("username = ?", "regular").Update("share_activity", false)
+
+		doc := page(t, adminCookie, "/feed", http.StatusOK)
+		if got := doc.Find("#activity-list li.activity").Length(); got != 0 {
+			t.Errorf("Expected no activity in the feed, got %d", got)
+		}
+	})
+
+	t.Run("Unfollowed users' activity does not appear in the feed", func(t *testing.T) {
+		db.Model(&model.User{}).Where("username = ?", "regular").Update("share_activity", true)
+
+		response, err := deleteRequest(url.Values{}, adminCookie, app, "/users/regular/followers", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		doc := page(t, adminCookie, "/feed", http.StatusOK)
+		if got := doc.Find("#activity-list li.activity").Length(); got != 0 {
+			t.Errorf("Expected no activity in the feed, got %d", got)
+		}
+	})
+
+	t.Run("Private profiles cannot be seen nor followed", func(t *testing.T) {
+		db.Model(&model.User{}).Where("username = ?", "admin").Update("private_profile", 1)
+
+		page(t, regularCookie, "/users/admin/profile", http.StatusNotFound)
+		follow(t, regularCookie, "admin", http.StatusNotFound)
+		page(t, adminCookie, "/users/admin/profile", http.StatusOK)
+	})
+
+	t.Run("Feed is not available to anonymous users", func(t *testing.T) {
+		response, err := getRequest(&http.Cookie{}, app, "/feed", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+}
@@ -0,0 +1,99 @@
+package webserver
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type auditRecorder interface {
+	Create(entry *model.AuditEntry) error
+}
+
+// Audit records action in the audit log once the route handler finishes successfully. target extracts
+// from the request the object the action is performed on, and optionally some details about it.
+func Audit(recorder auditRecorder, action string, target func(c fiber.Ctx) (string, string)) func(fiber.Ctx) error {
+	return func(c fiber.Ctx) error {
+		// The actor is taken before running the handler, as it may refresh the session
+		var actor string
+		if session, ok := c.Locals("Session").(model.Session); ok {
+			actor = session.Username
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() >= fiber.StatusBadRequest {
+			return nil
+		}
+
+		name, details := target(c)
+		record(c, recorder, actor, action, name, details)
+		return nil
+	}
+}
+
+// AuditLogin records both successful and failed logins. Successful ones are identified by the session
+// set by the login handler, while failed ones record the email used in the attempt, if any.
+func AuditLogin(recorder auditRecorder, method string) func(fiber.Ctx) error {
+	return func(c fiber.Ctx) error {
+		err := c.Next()
+
+		if session, ok := c.Locals("Session").(model.Session); ok {
+			record(c, recorder, session.Username, model.AuditLogin, session.Email, method)
+			return err
+		}
+		if err == nil && c.Response().StatusCode() == fiber.StatusUnauthorized {
+			record(c, recorder, "", model.AuditLoginFailed, c.FormValue("email"), method)
+		}
+		return err
+	}
+}
+
+func record(c fiber.Ctx, recorder auditRecorder, actor, action, target, details string) {
+	// Failing to record an entry must not prevent the action from being performed,
+	// the repository already logs the error
+	recorder.Create(&model.AuditEntry{
+		Actor:   actor,
+		Action:  action,
+		Target:  target,
+		Details: details,
+		IP:      c.IP(),
+	})
+}
+
+func auditParam(name string) func(c fiber.Ctx) (string, string) {
+	return func(c fiber.Ctx) (string, string) {
+		return c.Params(name), ""
+	}
+}
+
+func auditFormValue(name string) func(c fiber.Ctx) (string, string) {
+	return func(c fiber.Ctx) (string, string) {
+		return c.FormValue(name), ""
+	}
+}
+
+func auditUploadedFile(c fiber.Ctx) (string, string) {
+	file, err := c.FormFile("filename")
+	if err != nil {
+		return "", ""
+	}
+	return file.Filename, fmt.Sprintf("%d bytes", file.Size)
+}
+
+// auditUserCreate also records the role given to the new user
+func auditUserCreate(c fiber.Ctx) (string, string) {
+	role := "regular"
+	if c.FormValue("role") == strconv.Itoa(model.RoleAdmin) {
+		role = "admin"
+	}
+	return c.FormValue("username"), "role: " + role
+}
+
+// auditUserUpdate also records which tab of the user edit page was submitted
+func auditUserUpdate(c fiber.Ctx) (string, string) {
+	return c.Params("username"), "tab: " + c.FormValue("tab", "options")
+}
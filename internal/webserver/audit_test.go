@@ -0,0 +1,175 @@
+package webserver_test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"gorm.io/gorm"
+)
+
+func TestAuditLog(t *testing.T) {
+	reset := func(t *testing.T) (*gorm.DB, *fiber.App, *http.Cookie) {
+		t.Helper()
+
+		db := infrastructure.Connect(":memory:", 250)
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), defaultTestConfig())
+
+		adminCookie, err := login(app, "admin@example.com", "admin", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return db, app, adminCookie
+	}
+
+	exportEntries := func(t *testing.T, app *fiber.App, cookie *http.Cookie, query string) []model.AuditEntry {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, "/audit?format=json&"+query, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		if !strings.HasPrefix(response.Header.Get("Content-Disposition"), "attachment") {
+			t.Errorf("Expected export to be sent as an attachment")
+		}
+
+		var entries []model.AuditEntry
+		if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return entries
+	}
+
+	t.Run("Successful and failed logins are recorded", func(t *testing.T) {
+		_, app, adminCookie := reset(t)
+
+		if _, err := login(app, "admin@example.com", "wrong", t); err == nil {
+			t.Fatal("Expected login with wrong password to fail")
+		}
+
+		entries := exportEntries(t, app, adminCookie, "")
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries, got %d", len(entries))
+		}
+		if entries[0].Action != model.AuditLoginFailed || entries[0].Target != "admin@example.com" || entries[0].Actor != "" {
+			t.Errorf("Expected failed login for admin@example.com, got %+v", entries[0])
+		}
+		if entries[1].Action != model.AuditLogin || entries[1].Actor != "admin" || entries[1].IP != "0.0.0.0" {
+			t.Errorf("Expected login by admin from 0.0.0.0, got %+v", entries[1])
+		}
+	})
+
+	t.Run("The client address forwarded by a trusted proxy is recorded", func(t *testing.T) {
+		for _, tc := range []struct {
+			name       string
+			cidr       string
+			expectedIP string
+		}{
+			{"Trusted proxy", "0.0.0.0/32", "203.0.113.7"},
+			{"Untrusted proxy", "10.0.0.0/8", "0.0.0.0"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				_, network, err := net.ParseCIDR(tc.cidr)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err.Error())
+				}
+				webserverConfig := defaultTestConfig()
+				webserverConfig.ProxyAuth = webserver.ProxyAuth{
+					Enabled:        true,
+					TrustedProxies: []*net.IPNet{network},
+					UserHeader:     "Remote-User",
+					EmailHeader:    "Remote-Email",
+					NameHeader:     "Remote-Name",
+				}
+				db := infrastructure.Connect(":memory:", 250)
+				app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), webserverConfig)
+
+				req, err := http.NewRequest(http.MethodPost, "/sessions", strings.NewReader(url.Values{"email": {"admin@example.com"}, "password": {"wrong"}}.Encode()))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err.Error())
+				}
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.Header.Set("X-Forwarded-For", "203.0.113.7")
+				if _, err := app.Test(req); err != nil {
+					t.Fatalf("Unexpected error: %v", err.Error())
+				}
+
+				var entry model.AuditEntry
+				db.Where("action = ?", model.AuditLoginFailed).First(&entry)
+				if entry.IP != tc.expectedIP {
+					t.Errorf("Expected IP %s, got '%s'", tc.expectedIP, entry.IP)
+				}
+			})
+		}
+	})
+
+	t.Run("Administrative actions are recorded along with their actor and target", func(t *testing.T) {
+		_, app, adminCookie := reset(t)
+
+		addRegularUser(t, app, adminCookie)
+		response, err := deleteRequest(nil, adminCookie, app, "/users/regular", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		entries := exportEntries(t, app, adminCookie, "actor=admin&action="+model.AuditUserCreate)
+		if len(entries) != 1 || entries[0].Target != "regular" || entries[0].Details != "role: regular" {
+			t.Errorf("Expected a user creation entry for regular, got %+v", entries)
+		}
+		entries = exportEntries(t, app, adminCookie, "action="+model.AuditUserDelete)
+		if len(entries) != 1 || entries[0].Actor != "admin" || entries[0].Target != "regular" {
+			t.Errorf("Expected a user deletion entry for regular, got %+v", entries)
+		}
+	})
+
+	t.Run("Failed actions are not recorded", func(t *testing.T) {
+		_, app, adminCookie := reset(t)
+
+		response, err := deleteRequest(nil, adminCookie, app, "/users/nonexistent", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNotFound, t)
+
+		if entries := exportEntries(t, app, adminCookie, "action="+model.AuditUserDelete); len(entries) != 0 {
+			t.Errorf("Expected no entries, got %+v", entries)
+		}
+	})
+
+	t.Run("The audit log page can be filtered and is only available to admins", func(t *testing.T) {
+		_, app, adminCookie := reset(t)
+
+		response, err := getRequest(adminCookie, app, "/audit?action="+model.AuditLogin+"&from=2000-01-01&to=2100-01-01", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		response, err = getRequest(adminCookie, app, "/audit?action=unknown", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusBadRequest, t)
+
+		addRegularUser(t, app, adminCookie)
+		regularCookie, err := login(app, "regular@example.com", "regular", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		response, err = getRequest(regularCookie, app, "/audit", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+}
@@ -0,0 +1,171 @@
+package webserver_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestComments(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+	notificationsRepository := &model.NotificationRepository{DB: db}
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	var regular model.User
+	db.Where("username = ?", "regular").First(&regular)
+
+	comment := func(t *testing.T, cookie *http.Cookie, slug string, values url.Values, expectedStatus int) {
+		t.Helper()
+
+		response, err := postRequest(values, cookie, app, "/documents/"+slug+"/comments", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	discussion := func(t *testing.T, cookie *http.Cookie) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, "/documents/"+testDocSlug+"/comments", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	latest := func(t *testing.T) model.Comment {
+		t.Helper()
+
+		var c model.Comment
+		db.Order("id DESC").First(&c)
+		return c
+	}
+
+	t.Run("Invalid comments are rejected", func(t *testing.T) {
+		comment(t, regularCookie, "non-existing-document", url.Values{"text": {"Hello"}}, http.StatusNotFound)
+		comment(t, regularCookie, testDocSlug, url.Values{"text": {" "}}, http.StatusBadRequest)
+		comment(t, regularCookie, testDocSlug, url.Values{"text": {"Hello"}, "parent": {"999"}}, http.StatusBadRequest)
+		comment(t, &http.Cookie{}, testDocSlug, url.Values{"text": {"Hello"}}, http.StatusForbidden)
+	})
+
+	t.Run("Replies to replies are added to the thread", func(t *testing.T) {
+		comment(t, adminCookie, testDocSlug, url.Values{"text": {"What did you think?"}}, http.StatusNoContent)
+		thread := latest(t)
+
+		comment(t, regularCookie, testDocSlug, url.Values{"text": {"Loved it"}, "parent": {fmt.Sprint(thread.ID)}}, http.StatusNoContent)
+		reply := latest(t)
+		comment(t, adminCookie, testDocSlug, url.Values{"text": {"Me too"}, "parent": {fmt.Sprint(reply.ID)}}, http.StatusNoContent)
+		if replyToReply := latest(t); replyToReply.ParentID == nil || *replyToReply.ParentID != thread.ID {
+			t.Errorf("Expected reply to be added to comment %d, got %v", thread.ID, replyToReply.ParentID)
+		}
+
+		doc := discussion(t, regularCookie)
+		if got := doc.Find("#comments > li.comment").Length(); got != 1 {
+			t.Errorf("Expected 1 thread, got %d", got)
+		}
+		if got := doc.Find(fmt.Sprintf("#comment-%d li.comment", thread.ID)).Length(); got != 2 {
+			t.Errorf("Expected 2 replies in the thread, got %d", got)
+		}
+	})
+
+	t.Run("Mentioned users are notified", func(t *testing.T) {
+		comment(t, adminCookie, testDocSlug, url.Values{"text": {"@regular have a look, @admin @unknown"}}, http.StatusNoContent)
+
+		notifications, _ := notificationsRepository.Notifications(int(regular.ID), 1, model.ResultsPerPage)
+		if notifications.TotalHits() != 1 {
+			t.Fatalf("Expected regular user to have 1 notification, got %d", notifications.TotalHits())
+		}
+		expectedLink := fmt.Sprintf("/documents/%s#comment-%d", testDocSlug, latest(t).ID)
+		if got := notifications.Hits()[0]; got.Type != model.NotificationMentioned || got.Link != expectedLink {
+			t.Errorf("Expected a mention notification linking to '%s', got %s '%s'", expectedLink, got.Type, got.Link)
+		}
+
+		var count int64
+		db.Model(&model.Notification{}).Where("type = ?", model.NotificationMentioned).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected authors not to be notified about mentioning themselves, got %d notifications", count)
+		}
+	})
+
+	t.Run("Administrators can hide comments", func(t *testing.T) {
+		mention := latest(t)
+		hideURL := fmt.Sprintf("/comments/%d/hidden", mention.ID)
+
+		response, err := postRequest(url.Values{}, regularCookie, app, hideURL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		response, err = postRequest(url.Values{}, adminCookie, app, hideURL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		selector := fmt.Sprintf("#comment-%d", mention.ID)
+		doc := discussion(t, regularCookie)
+		if doc.Find(selector+" .comment-text").Length() != 0 || doc.Find(selector+" .comment-hidden").Length() != 1 {
+			t.Error("Expected hidden comment text not to be shown to regular users")
+		}
+		doc = discussion(t, adminCookie)
+		if doc.Find(selector+" .comment-text").Length() != 1 {
+			t.Error("Expected hidden comment text to be shown to administrators")
+		}
+
+		response, err = deleteRequest(url.Values{}, adminCookie, app, hideURL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		doc = discussion(t, regularCookie)
+		if doc.Find(selector+" .comment-text").Length() != 1 {
+			t.Error("Expected comment text to be shown again")
+		}
+	})
+
+	t.Run("Only authors and administrators can delete comments", func(t *testing.T) {
+		var thread model.Comment
+		db.Where("parent_id IS NULL").Order("id").First(&thread)
+
+		response, err := deleteRequest(url.Values{}, regularCookie, app, fmt.Sprintf("/comments/%d", thread.ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		response, err = deleteRequest(url.Values{}, adminCookie, app, fmt.Sprintf("/comments/%d", thread.ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		var count int64
+		db.Model(&model.Comment{}).Where("id = ? OR parent_id = ?", thread.ID, thread.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected comment and its replies to be deleted, got %d left", count)
+		}
+	})
+}
@@ -1,30 +1,68 @@
 package webserver
 
 import (
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/conversion"
 	"github.com/svera/coreander/v4/internal/index"
 	"github.com/svera/coreander/v4/internal/metadata"
+	"github.com/svera/coreander/v4/internal/webserver/controller/activity"
+	"github.com/svera/coreander/v4/internal/webserver/controller/audit"
 	"github.com/svera/coreander/v4/internal/webserver/controller/auth"
 	"github.com/svera/coreander/v4/internal/webserver/controller/author"
+	"github.com/svera/coreander/v4/internal/webserver/controller/comment"
 	"github.com/svera/coreander/v4/internal/webserver/controller/completed"
+	"github.com/svera/coreander/v4/internal/webserver/controller/dashboard"
+	"github.com/svera/coreander/v4/internal/webserver/controller/device"
 	"github.com/svera/coreander/v4/internal/webserver/controller/document"
+	"github.com/svera/coreander/v4/internal/webserver/controller/email"
+	"github.com/svera/coreander/v4/internal/webserver/controller/goal"
 	"github.com/svera/coreander/v4/internal/webserver/controller/highlight"
+	"github.com/svera/coreander/v4/internal/webserver/controller/history"
 	"github.com/svera/coreander/v4/internal/webserver/controller/home"
+	"github.com/svera/coreander/v4/internal/webserver/controller/notification"
+	"github.com/svera/coreander/v4/internal/webserver/controller/passkey"
+	"github.com/svera/coreander/v4/internal/webserver/controller/queue"
+	"github.com/svera/coreander/v4/internal/webserver/controller/review"
 	"github.com/svera/coreander/v4/internal/webserver/controller/series"
+	"github.com/svera/coreander/v4/internal/webserver/controller/shelf"
+	"github.com/svera/coreander/v4/internal/webserver/controller/stats"
 	"github.com/svera/coreander/v4/internal/webserver/controller/user"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 	"gorm.io/gorm"
 )
 
 type Controllers struct {
-	Auth       *auth.Controller
-	Users      *user.Controller
-	Completed  *completed.Controller
-	Highlights *highlight.Controller
-	Documents  *document.Controller
-	Home       *home.Controller
-	Authors    *author.Controller
-	Series     *series.Controller
+	Auth          *auth.Controller
+	Users         *user.Controller
+	Completed     *completed.Controller
+	Highlights    *highlight.Controller
+	Documents     *document.Controller
+	Home          *home.Controller
+	Authors       *author.Controller
+	Series        *series.Controller
+	Passkeys      *passkey.Controller
+	Audit         *audit.Controller
+	Dashboard     *dashboard.Controller
+	Stats         *stats.Controller
+	Goals         *goal.Controller
+	Devices       *device.Controller
+	Emails        *email.Controller
+	History       *history.Controller
+	Reviews       *review.Controller
+	Shelves       *shelf.Controller
+	Queue         *queue.Controller
+	Activity      *activity.Controller
+	Notifications *notification.Controller
+	Comments      *comment.Controller
+
+	// auditRepository records the administrative actions and logins handled by the routes
+	auditRepository *model.AuditRepository
 }
 
 func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metadata.Reader, idx *index.BleveIndexer, sender Sender, appFs afero.Fs, dataSource author.DataSource) Controllers {
@@ -32,6 +70,23 @@ func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metada
 	invitationsRepository := &model.InvitationRepository{DB: db}
 	highlightsRepository := &model.HighlightRepository{DB: db, Idx: idx}
 	readingRepository := &model.ReadingRepository{DB: db, Idx: idx}
+	passkeysRepository := &model.PasskeyRepository{DB: db}
+	auditRepository := &model.AuditRepository{DB: db}
+	goalsRepository := &model.GoalRepository{DB: db, Idx: idx}
+	reviewsRepository := &model.ReviewRepository{DB: db, Idx: idx}
+	shelvesRepository := &model.ShelfRepository{DB: db, Idx: idx}
+	queueRepository := &model.QueueRepository{DB: db, Idx: idx}
+	activityRepository := &model.ActivityRepository{DB: db, Idx: idx}
+	notificationsRepository := &model.NotificationRepository{DB: db}
+	commentsRepository := &model.CommentRepository{DB: db}
+	devicesRepository := &model.DeviceRepository{DB: db}
+	readerPreferencesRepository := &model.ReaderPreferencesRepository{DB: db}
+	emailRepository := &model.EmailRepository{DB: db}
+	conversions := conversion.NewCache(appFs, cfg.CacheDir, int64(cfg.ConversionCacheMaxSize)*1024*1024, conversion.DefaultRegistry(), cfg.ConversionWorkers)
+
+	if cfg.KepubPregenerate {
+		go pregenerateKepubs(conversions, queueRepository, idx)
+	}
 
 	authCfg := auth.Config{
 		MinPasswordLength: cfg.MinPasswordLength,
@@ -40,6 +95,7 @@ func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metada
 		Port:              cfg.Port,
 		SessionTimeout:    cfg.SessionTimeout,
 		RecoveryTimeout:   cfg.RecoveryTimeout,
+		WordsPerMinute:    cfg.WordsPerMinute,
 	}
 
 	inviteListMax := cfg.InviteEmailListMaxLength
@@ -71,6 +127,7 @@ func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metada
 		ServerImageCacheTTL:   cfg.ServerDynamicImageCacheTTL,
 		ShareCommentMaxSize:   cfg.ShareCommentMaxSize,
 		ShareMaxRecipients:    cfg.ShareMaxRecipients,
+		KepubPregenerate:      cfg.KepubPregenerate,
 	}
 
 	authorsCfg := author.Config{
@@ -85,6 +142,22 @@ func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metada
 		WordsPerMinute: cfg.WordsPerMinute,
 	}
 
+	passkeysCfg := passkey.Config{
+		Secret:         cfg.JwtSecret,
+		SessionTimeout: cfg.SessionTimeout,
+	}
+
+	webAuthn, err := webauthn.New(webAuthnConfig(cfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dashboardCfg := dashboard.Config{
+		CacheDir:          cfg.CacheDir,
+		ActiveUsersPeriod: 30 * 24 * time.Hour,
+		RankingSize:       10,
+	}
+
 	homeCfg := home.Config{
 		LibraryPath:     cfg.LibraryPath,
 		CoverMaxWidth:   cfg.CoverMaxWidth,
@@ -92,12 +165,59 @@ func SetupControllers(cfg Config, db *gorm.DB, metadataReaders map[string]metada
 	}
 
 	return Controllers{
-		Auth:       auth.NewController(usersRepository, sender, authCfg, translator),
-		Users:      user.NewController(usersRepository, invitationsRepository, usersCfg, sender, translator),
-		Completed:  completed.NewController(readingRepository, idx),
-		Highlights: highlight.NewController(highlightsRepository, readingRepository, usersRepository, sender, cfg.WordsPerMinute, idx),
-		Documents:  document.NewController(highlightsRepository, usersRepository, readingRepository, sender, idx, metadataReaders, appFs, documentsCfg, translator),
-		Home:       home.NewController(highlightsRepository, readingRepository, sender, idx, homeCfg),
-		Authors:    author.NewController(highlightsRepository, readingRepository, sender, idx, authorsCfg, dataSource, appFs, imagesFS),
-		Series:     series.NewController(highlightsRepository, readingRepository, sender, idx, seriesCfg, appFs)}
+		Auth:          auth.NewController(usersRepository, sender, cfg.LDAP, authCfg, translator),
+		Users:         user.NewController(usersRepository, invitationsRepository, readingRepository, notificationsRepository, usersCfg, sender, translator),
+		Completed:     completed.NewController(readingRepository, queueRepository, activityRepository, idx),
+		Highlights:    highlight.NewController(highlightsRepository, readingRepository, usersRepository, activityRepository, sender, cfg.WordsPerMinute, idx),
+		Documents:     document.NewController(highlightsRepository, usersRepository, readingRepository, reviewsRepository, shelvesRepository, queueRepository, activityRepository, commentsRepository, notificationsRepository, devicesRepository, readerPreferencesRepository, conversions, sender, idx, metadataReaders, appFs, documentsCfg, translator),
+		Home:          home.NewController(highlightsRepository, readingRepository, goalsRepository, sender, idx, homeCfg),
+		Authors:       author.NewController(highlightsRepository, readingRepository, sender, idx, authorsCfg, dataSource, appFs, imagesFS),
+		Series:        series.NewController(highlightsRepository, readingRepository, notificationsRepository, sender, idx, seriesCfg, appFs),
+		Passkeys:      passkey.NewController(usersRepository, passkeysRepository, webAuthn, passkeysCfg),
+		Audit:         audit.NewController(auditRepository),
+		Dashboard:     dashboard.NewController(idx, usersRepository, readingRepository, highlightsRepository, appFs, dashboardCfg),
+		Stats:         stats.NewController(readingRepository, usersRepository),
+		Goals:         goal.NewController(usersRepository, goalsRepository),
+		Devices:       device.NewController(usersRepository, devicesRepository),
+		Emails:        email.NewController(emailRepository, sender, translator),
+		History:       history.NewController(readingRepository),
+		Reviews:       review.NewController(reviewsRepository, activityRepository, idx),
+		Shelves:       shelf.NewController(shelvesRepository, usersRepository, idx),
+		Queue:         queue.NewController(queueRepository, readingRepository, idx),
+		Activity:      activity.NewController(activityRepository, usersRepository),
+		Notifications: notification.NewController(notificationsRepository),
+		Comments:      comment.NewController(commentsRepository, usersRepository, notificationsRepository, idx),
+
+		auditRepository: auditRepository,
+	}
+}
+
+// webAuthnConfig derives the WebAuthn relying party from the FQDN, accepting both HTTP and HTTPS origins.
+// If the FQDN does not include a port, origins using the port the server listens to are also accepted.
+func webAuthnConfig(cfg Config) *webauthn.Config {
+	host := cfg.FQDN
+	if h, _, err := net.SplitHostPort(cfg.FQDN); err == nil {
+		host = h
+	}
+	if host == "" {
+		host = "localhost"
+	}
+
+	origins := []string{}
+	for _, scheme := range []string{"https", "http"} {
+		if cfg.FQDN == host || cfg.FQDN == "" {
+			origins = append(origins, fmt.Sprintf("%s://%s", scheme, host))
+			if cfg.Port != 0 {
+				origins = append(origins, fmt.Sprintf("%s://%s:%d", scheme, host, cfg.Port))
+			}
+			continue
+		}
+		origins = append(origins, fmt.Sprintf("%s://%s", scheme, cfg.FQDN))
+	}
+
+	return &webauthn.Config{
+		RPDisplayName: "Coreander",
+		RPID:          host,
+		RPOrigins:     origins,
+	}
 }
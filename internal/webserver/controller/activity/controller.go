@@ -0,0 +1,34 @@
+package activity
+
+import (
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type activityRepository interface {
+	Feed(followerID int, page int, resultsPerPage int) (result.Paginated[[]model.ActivityEntry], error)
+	UserActivity(userID int, page int, resultsPerPage int) (result.Paginated[[]model.ActivityEntry], error)
+	Follow(followerID, followeeID int) error
+	Unfollow(followerID, followeeID int) error
+	Follows(followerID, followeeID int) bool
+	Following(followerID int) ([]model.User, error)
+	Followers(followeeID int) (int64, error)
+	Suggestions(followerID int) ([]model.User, error)
+}
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type Controller struct {
+	activityRepository activityRepository
+	usersRepository    usersRepository
+}
+
+// NewController returns a new instance of the activity controller
+func NewController(activityRepository activityRepository, usersRepository usersRepository) *Controller {
+	return &Controller{
+		activityRepository: activityRepository,
+		usersRepository:    usersRepository,
+	}
+}
@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// Feed renders the activity of the users followed by the logged in user
+func (a *Controller) Feed(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	user, err := a.usersRepository.FindByUsername(session.Username)
+	if err != nil || user == nil {
+		return fiber.ErrInternalServerError
+	}
+
+	results, err := a.activityRepository.Feed(int(session.ID), page, model.ResultsPerPage)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	following, err := a.activityRepository.Following(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	suggestions, err := a.activityRepository.Suggestions(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err = c.Render("activity/feed", fiber.Map{
+		"Title":          "Activity",
+		"Results":        results,
+		"Paginator":      view.Pagination(model.MaxPagesNavigator, results, c.Queries()),
+		"Following":      following,
+		"Suggestions":    suggestions,
+		"SharesActivity": user.SharesActivity(),
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
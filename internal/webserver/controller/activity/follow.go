@@ -0,0 +1,46 @@
+package activity
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Follow adds the activity of a user to the feed of the logged in one
+func (a *Controller) Follow(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	user, err := a.user(c)
+	if err != nil {
+		return err
+	}
+	if user.PrivateProfile != 0 {
+		return fiber.ErrNotFound
+	}
+	if user.ID == session.ID {
+		return fiber.ErrBadRequest
+	}
+
+	if err := a.activityRepository.Follow(int(session.ID), int(user.ID)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Unfollow removes the activity of a user from the feed of the logged in one
+func (a *Controller) Unfollow(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	user, err := a.user(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.activityRepository.Unfollow(int(session.ID), int(user.ID)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
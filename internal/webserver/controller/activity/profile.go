@@ -0,0 +1,68 @@
+package activity
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// Profile renders the public profile of a user, along with their activity if they share it
+func (a *Controller) Profile(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	user, err := a.user(c)
+	if err != nil {
+		return err
+	}
+	if user.PrivateProfile != 0 && session.ID != user.ID && session.Role != model.RoleAdmin {
+		return fiber.ErrNotFound
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	results, err := a.activityRepository.UserActivity(int(user.ID), page, model.ResultsPerPage)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	followers, err := a.activityRepository.Followers(int(user.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err = c.Render("activity/profile", fiber.Map{
+		"Title":          user.Name,
+		"User":           user,
+		"Results":        results,
+		"Paginator":      view.Pagination(model.MaxPagesNavigator, results, c.Queries()),
+		"Followers":      followers,
+		"Follows":        a.activityRepository.Follows(int(session.ID), int(user.ID)),
+		"IsOwnProfile":   session.ID == user.ID,
+		"SharesActivity": user.SharesActivity(),
+		"Year":           time.Now().Year(),
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// user returns the user whose username is in the URL
+func (a *Controller) user(c fiber.Ctx) (*model.User, error) {
+	user, err := a.usersRepository.FindByUsername(c.Params("username"))
+	if err != nil {
+		log.Println(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return nil, fiber.ErrNotFound
+	}
+	return user, nil
+}
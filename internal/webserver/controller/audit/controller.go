@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type auditRepository interface {
+	List(filter model.AuditFilter, page int, resultsPerPage int) (result.Paginated[[]model.AuditEntry], error)
+	All(filter model.AuditFilter) ([]model.AuditEntry, error)
+}
+
+type Controller struct {
+	auditRepository auditRepository
+}
+
+// NewController returns a new instance of the audit log controller
+func NewController(auditRepository auditRepository) *Controller {
+	return &Controller{
+		auditRepository: auditRepository,
+	}
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+const dateLayout = "2006-01-02"
+
+// List renders the audit log, or exports it as JSON if the format query parameter is set to "json".
+// Entries can be filtered by actor, action and a range of dates.
+func (a *Controller) List(c fiber.Ctx) error {
+	filter, err := parseFilter(c)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if c.Query("format") == "json" {
+		entries, err := a.auditRepository.All(filter)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		c.Attachment(fmt.Sprintf("audit-%s.json", time.Now().Format(dateLayout)))
+		return c.JSON(entries)
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	entries, err := a.auditRepository.List(filter, page, model.ResultsPerPage)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	queries := c.Queries()
+	delete(queries, "page")
+	delete(queries, "format")
+	queries["format"] = "json"
+
+	return c.Render("audit/list", fiber.Map{
+		"Title":     "Audit log",
+		"Entries":   entries.Hits(),
+		"Paginator": view.Pagination(model.MaxPagesNavigator, entries, c.Queries()),
+		"Actions":   model.AuditActions,
+		"Actor":     filter.Actor,
+		"Action":    filter.Action,
+		"From":      c.Query("from"),
+		"To":        c.Query("to"),
+		"ExportURL": "/audit?" + view.ToQueryString(queries),
+	}, "layout")
+}
+
+func parseFilter(c fiber.Ctx) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+	}
+
+	if filter.Action != "" && !slices.Contains(model.AuditActions, filter.Action) {
+		return filter, fmt.Errorf("unknown audit action %s", filter.Action)
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(dateLayout, from); err != nil {
+			return filter, err
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(dateLayout, to); err != nil {
+			return filter, err
+		}
+		// Include the whole day
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	return filter, nil
+}
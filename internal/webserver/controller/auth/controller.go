@@ -4,22 +4,31 @@ import (
 	"time"
 
 	"github.com/svera/coreander/v4/internal/i18n"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
 type authRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	FindByRecoveryUuid(recoveryUuid string) (*model.User, error)
+	Create(user *model.User) error
 	Update(user *model.User) error
+	AvailableUsername(candidate, email string) (string, error)
+}
+
+// directory authenticates users against an external user directory, such as LDAP
+type directory interface {
+	Authenticate(login, password string) (*model.User, error)
 }
 
 type recoveryEmail interface {
-	Send(address, subject, body string) error
+	Send(address string, message mailer.Message) error
 }
 
 type Controller struct {
 	repository authRepository
 	sender     recoveryEmail
+	directory  directory
 	translator i18n.Translator
 	config     Config
 }
@@ -31,12 +40,14 @@ type Config struct {
 	Port              int
 	SessionTimeout    time.Duration
 	RecoveryTimeout   time.Duration
+	WordsPerMinute    float64
 }
 
-func NewController(repository authRepository, sender recoveryEmail, cfg Config, translator i18n.Translator) *Controller {
+func NewController(repository authRepository, sender recoveryEmail, directory directory, cfg Config, translator i18n.Translator) *Controller {
 	return &Controller{
 		repository: repository,
 		sender:     sender,
+		directory:  directory,
 		translator: translator,
 		config:     cfg,
 	}
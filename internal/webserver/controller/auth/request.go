@@ -10,6 +10,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
 	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 )
 
 func (a *Controller) Request(c fiber.Ctx) error {
@@ -42,16 +43,17 @@ func (a *Controller) Request(c fiber.Ctx) error {
 			c.Locals("fqdn"),
 			user.RecoveryUUID,
 		)
-		c.Render("auth/email", fiber.Map{
+		// The email is written in the language chosen by the user, as the request may come from another device
+		message, err := mailer.NewComposer(c.App().Config().Views, a.translator).Compose("recovery", user.Language, fiber.Map{
 			"RecoveryLink":    recoveryLink,
 			"RecoveryTimeout": strconv.FormatFloat(a.config.RecoveryTimeout.Hours(), 'f', -1, 64),
-		})
+		}, "Password recovery request")
+		if err != nil {
+			log.Printf("error rendering recovery email: %v\n", err)
+			return fiber.ErrInternalServerError
+		}
 
-		if err := a.sender.Send(
-			c.FormValue("email"),
-			a.translator.T(c.Locals("Lang").(string), "Password recovery request"),
-			string(c.Response().Body()),
-		); err != nil {
+		if err := a.sender.Send(c.FormValue("email"), message); err != nil {
 			log.Printf("error sending recovery email: %v\n", err)
 			return fiber.ErrInternalServerError
 		}
@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"log"
+	"net/mail"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
@@ -21,12 +24,12 @@ func (a *Controller) SignIn(c fiber.Ctx) error {
 	)
 
 	// If username or password are incorrect, do not allow access.
-	user, err = a.repository.FindByEmail(c.FormValue("email"))
+	user, err = a.authenticate(c.FormValue("email"), c.FormValue("password"))
 	if err != nil {
 		return fiber.ErrInternalServerError
 	}
 
-	if user == nil || user.Password != model.Hash(c.FormValue("password")) {
+	if user == nil {
 		return c.Status(fiber.StatusUnauthorized).Render("auth/login", fiber.Map{
 			"Title":            "Login",
 			"Error":            "Wrong email or password",
@@ -35,29 +38,98 @@ func (a *Controller) SignIn(c fiber.Ctx) error {
 	}
 
 	// Send back JWT as a cookie.
-	expiration := time.Now().Add(a.config.SessionTimeout)
-	signedToken, err := GenerateToken(c, user, expiration, a.config.Secret)
-	if err != nil {
+	if err = SetSessionCookie(c, user, a.config.SessionTimeout, a.config.Secret); err != nil {
 		return fiber.ErrInternalServerError
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "session",
-		Value:    signedToken,
-		Path:     "/",
-		MaxAge:   34560000, // 400 days which is the life limit imposed by Chrome
-		Secure:   false,
-		HTTPOnly: true,
-	})
+	return c.Redirect().To(RedirectAfterSignIn(c))
+}
 
-	// Redirect back to the page they came from, but never to guest-only routes:
-	// those use AllowIfNotLoggedIn and would return Forbidden for a logged-in user.
+// RedirectAfterSignIn returns the page the user came from, but never guest-only routes:
+// those use AllowIfNotLoggedIn and would return Forbidden for a logged-in user.
+func RedirectAfterSignIn(c fiber.Ctx) string {
 	referer := string(c.RequestCtx().Referer())
 	if referer != "" && !isGuestOnlyReferer(referer) {
-		return c.Redirect().To(referer)
+		return referer
+	}
+
+	return "/"
+}
+
+// authenticate checks credentials against the directory first, if any, falling back
+// to the local password check. A nil user is returned if credentials are incorrect.
+func (a *Controller) authenticate(email, password string) (*model.User, error) {
+	if a.directory != nil {
+		directoryUser, err := a.directory.Authenticate(email, password)
+		if err != nil {
+			// A directory outage must not lock out local users, such as the admin
+			log.Println(err)
+		}
+		if directoryUser != nil {
+			if user, err := a.syncDirectoryUser(directoryUser); err != nil || user != nil {
+				return user, err
+			}
+		}
+	}
+
+	user, err := a.repository.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Password != model.Hash(password) {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// syncDirectoryUser updates the local user matching the directory one with its name,
+// email and role, creating it if it does not exist yet. Users with a local password, such as the admin,
+// were not created by the directory, so they are never signed in nor updated through it: otherwise whoever
+// controls a directory entry with their email would take over their account.
+func (a *Controller) syncDirectoryUser(directoryUser *model.User) (*model.User, error) {
+	if _, err := mail.ParseAddress(directoryUser.Email); err != nil {
+		log.Printf("directory user %s has no valid email, ignoring\n", directoryUser.Username)
+		return nil, nil
+	}
+
+	user, err := a.repository.FindByEmail(directoryUser.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil && user.Password != "" {
+		log.Printf("directory user %s matches local user %s, which has a local password, ignoring\n", directoryUser.Username, user.Username)
+		return nil, nil
+	}
+
+	if user == nil {
+		username, err := a.repository.AvailableUsername(directoryUser.Username, directoryUser.Email)
+		if err != nil {
+			return nil, err
+		}
+		user = &model.User{
+			Uuid:              uuid.NewString(),
+			Username:          username,
+			Email:             directoryUser.Email,
+			Role:              model.RoleRegular,
+			WordsPerMinute:    a.config.WordsPerMinute,
+			PreferredEpubType: "epub",
+			DefaultAction:     "download",
+		}
+	}
+
+	user.Name = directoryUser.Name
+	if user.Name == "" {
+		user.Name = user.Username
+	}
+	user.Name = model.TruncateName(user.Name)
+	if directoryUser.Role != 0 {
+		user.Role = directoryUser.Role
 	}
 
-	return c.Redirect().To("/")
+	if user.ID == 0 {
+		return user, a.repository.Create(user)
+	}
+	return user, a.repository.Update(user)
 }
 
 func isGuestOnlyReferer(referer string) bool {
@@ -69,6 +141,27 @@ func isGuestOnlyReferer(referer string) bool {
 	return false
 }
 
+// SetSessionCookie starts a session for user by sending back a signed JWT as a cookie,
+// and makes it available for the rest of the request
+func SetSessionCookie(c fiber.Ctx, user *model.User, sessionTimeout time.Duration, secret []byte) error {
+	expiration := time.Now().Add(sessionTimeout)
+	signedToken, err := GenerateToken(c, user, expiration, secret)
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "session",
+		Value:    signedToken,
+		Path:     "/",
+		MaxAge:   34560000, // 400 days which is the life limit imposed by Chrome
+		Secure:   false,
+		HTTPOnly: true,
+	})
+	c.Locals("Session", model.Session{User: *user, Exp: float64(expiration.Unix())})
+	return nil
+}
+
 func GenerateToken(c fiber.Ctx, user *model.User, expiration time.Time, secret []byte) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"userdata": model.User{
@@ -0,0 +1,43 @@
+package comment
+
+import (
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type commentsRepository interface {
+	Thread(documentSlug string) ([]model.Comment, error)
+	Get(commentID uint) (*model.Comment, error)
+	Create(comment *model.Comment) error
+	Delete(commentID uint) error
+	Hide(commentID uint, hidden bool) error
+}
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type notificationsRepository interface {
+	Notify(userIDs []int, notification model.Notification, emailed bool)
+}
+
+type idxReader interface {
+	Document(slug string) (index.Document, error)
+}
+
+type Controller struct {
+	commentsRepository      commentsRepository
+	usersRepository         usersRepository
+	notificationsRepository notificationsRepository
+	idx                     idxReader
+}
+
+// NewController returns a new instance of the comments controller
+func NewController(commentsRepository commentsRepository, usersRepository usersRepository, notificationsRepository notificationsRepository, idx idxReader) *Controller {
+	return &Controller{
+		commentsRepository:      commentsRepository,
+		usersRepository:         usersRepository,
+		notificationsRepository: notificationsRepository,
+		idx:                     idx,
+	}
+}
@@ -0,0 +1,89 @@
+package comment
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Create adds a comment to the discussion of a document, or a reply if a parent comment is passed.
+// Replies to replies are added to the comment that started the thread.
+// Mentioned users are notified.
+func (a *Controller) Create(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+	if session.PrivateProfile != 0 {
+		return fiber.ErrForbidden
+	}
+
+	document, err := a.idx.Document(c.Params("slug"))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	comment := model.Comment{
+		Slug:   document.Slug,
+		UserID: int(session.ID),
+		Text:   c.FormValue("text"),
+	}
+	if errs := comment.Validate(); len(errs) > 0 {
+		return fiber.ErrBadRequest
+	}
+
+	if c.FormValue("parent") != "" {
+		parentID, err := strconv.ParseUint(c.FormValue("parent"), 10, 0)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		parent, err := a.commentsRepository.Get(uint(parentID))
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if parent == nil || parent.Slug != document.Slug {
+			return fiber.ErrBadRequest
+		}
+		if parent.ParentID != nil {
+			parentID = uint64(*parent.ParentID)
+		}
+		threadID := uint(parentID)
+		comment.ParentID = &threadID
+	}
+
+	if err := a.commentsRepository.Create(&comment); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	a.notifyMentions(session, comment, document.Title)
+
+	c.Response().Header.Set("HX-Trigger", "comments-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// notifyMentions tells the users mentioned in a comment about it. Unknown users, users with a private profile
+// and the author of the comment are skipped.
+func (a *Controller) notifyMentions(session model.Session, comment model.Comment, documentTitle string) {
+	author := session.Name
+	if author == "" {
+		author = session.Username
+	}
+
+	mentioned := []int{}
+	for _, username := range comment.Mentions() {
+		user, err := a.usersRepository.FindByUsername(username)
+		if err != nil || user == nil || user.PrivateProfile != 0 || user.ID == session.ID {
+			continue
+		}
+		mentioned = append(mentioned, int(user.ID))
+	}
+
+	a.notificationsRepository.Notify(mentioned, model.Notification{
+		Type:  model.NotificationMentioned,
+		Actor: author,
+		Slug:  comment.Slug,
+		Title: documentTitle,
+		Link:  "/documents/" + comment.Slug + "#comment-" + strconv.FormatUint(uint64(comment.ID), 10),
+	}, false)
+}
@@ -0,0 +1,32 @@
+package comment
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// List renders the discussion of a document
+func (a *Controller) List(c fiber.Ctx) error {
+	var session model.Session
+	if val, ok := c.Locals("Session").(model.Session); ok {
+		session = val
+	}
+
+	comments, err := a.commentsRepository.Thread(c.Params("slug"))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err = c.Render("partials/comments", fiber.Map{
+		"Slug":           c.Params("slug"),
+		"Comments":       comments,
+		"Session":        session,
+		"CommentMaxSize": model.CommentTextMaxLength,
+	}); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package comment
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Delete removes a comment along with its replies. Only its author and administrators can delete it.
+func (a *Controller) Delete(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	comment, err := a.comment(c)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != int(session.ID) && session.Role != model.RoleAdmin {
+		return fiber.ErrForbidden
+	}
+
+	if err := a.commentsRepository.Delete(comment.ID); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "comments-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Hide hides the text of a comment from users other than administrators
+func (a *Controller) Hide(c fiber.Ctx) error {
+	return a.hide(c, true)
+}
+
+// Unhide shows again the text of a previously hidden comment
+func (a *Controller) Unhide(c fiber.Ctx) error {
+	return a.hide(c, false)
+}
+
+func (a *Controller) hide(c fiber.Ctx, hidden bool) error {
+	comment, err := a.comment(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.commentsRepository.Hide(comment.ID, hidden); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "comments-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// comment returns the comment whose ID is in the URL
+func (a *Controller) comment(c fiber.Ctx) (*model.Comment, error) {
+	commentID, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return nil, fiber.ErrNotFound
+	}
+
+	comment, err := a.commentsRepository.Get(uint(commentID))
+	if err != nil {
+		return nil, fiber.ErrInternalServerError
+	}
+	if comment == nil {
+		return nil, fiber.ErrNotFound
+	}
+	return comment, nil
+}
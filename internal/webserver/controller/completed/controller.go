@@ -18,16 +18,29 @@ type readingRepository interface {
 	Get(userID int, documentSlug string) (model.Reading, error)
 	Touch(userID int, documentSlug string) error
 	UpdateCompletionDate(userID int, documentSlug string, completedAt *time.Time) error
+	Reread(userID int, documentSlug string) error
+}
+
+type queueRepository interface {
+	Remove(userID int, documentSlug string) error
+}
+
+type activityRepository interface {
+	Record(userID int, activityType, documentSlug string)
 }
 
 type Controller struct {
-	readingRepository readingRepository
-	idxReader         idxReader
+	readingRepository  readingRepository
+	queueRepository    queueRepository
+	activityRepository activityRepository
+	idxReader          idxReader
 }
 
-func NewController(readingRepository readingRepository, idxReader idxReader) *Controller {
+func NewController(readingRepository readingRepository, queueRepository queueRepository, activityRepository activityRepository, idxReader idxReader) *Controller {
 	return &Controller{
-		readingRepository: readingRepository,
-		idxReader:         idxReader,
+		readingRepository:  readingRepository,
+		queueRepository:    queueRepository,
+		activityRepository: activityRepository,
+		idxReader:          idxReader,
 	}
 }
@@ -0,0 +1,32 @@
+package completed
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Reread starts reading a completed document again. Its previous completions are kept in the reading history.
+func (c *Controller) Reread(ctx fiber.Ctx) error {
+	session, _ := ctx.Locals("Session").(model.Session)
+
+	document, err := c.idxReader.Document(ctx.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	if err := c.readingRepository.Reread(int(session.ID), document.Slug); err != nil {
+		log.Printf("error starting a new read-through: %s\n", err)
+		return fiber.ErrInternalServerError
+	}
+	c.activityRepository.Record(int(session.ID), model.ActivityStarted, document.Slug)
+
+	ctx.Set("HX-Refresh", "true")
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
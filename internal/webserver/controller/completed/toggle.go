@@ -15,6 +15,7 @@ type updateCompletionDateRequest struct {
 // ToggleComplete marks a document as complete or incomplete.
 // If a date is provided in the request body, it sets the completion date to that value.
 // If no date is provided (POST), it toggles between complete (with current date) and incomplete.
+// Completed documents are taken out of the user's reading queue.
 func (c *Controller) ToggleComplete(ctx fiber.Ctx) error {
 	session, _ := ctx.Locals("Session").(model.Session)
 
@@ -58,6 +59,10 @@ func (c *Controller) ToggleComplete(ctx fiber.Ctx) error {
 					log.Printf("error updating completion date: %s\n", err)
 					return fiber.ErrInternalServerError
 				}
+				if err := c.queueRepository.Remove(int(session.ID), document.Slug); err != nil {
+					return fiber.ErrInternalServerError
+				}
+				c.activityRepository.Record(int(session.ID), model.ActivityFinished, document.Slug)
 			}
 			return ctx.SendStatus(fiber.StatusNoContent)
 		}
@@ -83,5 +88,12 @@ func (c *Controller) ToggleComplete(ctx fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
+	if newCompletionDate != nil {
+		if err := c.queueRepository.Remove(int(session.ID), document.Slug); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		c.activityRepository.Record(int(session.ID), model.ActivityFinished, document.Slug)
+	}
+
 	return ctx.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type idxReader interface {
+	Stats(subjectsLimit int) (index.Stats, error)
+	IndexingProgress() (index.Progress, error)
+	Documents(slugs []string) (map[string]index.Document, error)
+	LatestDocs(limit int) ([]index.Document, error)
+}
+
+type usersRepository interface {
+	Total(filter string) int64
+	ActiveSince(since time.Time) int64
+}
+
+type readingRepository interface {
+	MostRead(limit int) ([]model.DocumentCount, error)
+}
+
+type highlightsRepository interface {
+	MostHighlighted(limit int) ([]model.DocumentCount, error)
+}
+
+type Config struct {
+	CacheDir string
+	// ActiveUsersPeriod is how far back a user must have made a request to be considered active
+	ActiveUsersPeriod time.Duration
+	// RankingSize is the number of entries shown in each of the dashboard rankings
+	RankingSize int
+}
+
+type Controller struct {
+	idx                  idxReader
+	usersRepository      usersRepository
+	readingRepository    readingRepository
+	highlightsRepository highlightsRepository
+	appFs                afero.Fs
+	config               Config
+	// The cache directory may hold many conversions, so its size is calculated at most once every cacheSizeTTL
+	cacheSizeMu        sync.Mutex
+	cachedSize         int64
+	cacheSizeUpdatedAt time.Time
+}
+
+// NewController returns a new instance of the admin dashboard controller
+func NewController(idx idxReader, usersRepository usersRepository, readingRepository readingRepository, highlightsRepository highlightsRepository, appFs afero.Fs, cfg Config) *Controller {
+	return &Controller{
+		idx:                  idx,
+		usersRepository:      usersRepository,
+		readingRepository:    readingRepository,
+		highlightsRepository: highlightsRepository,
+		appFs:                appFs,
+		config:               cfg,
+	}
+}
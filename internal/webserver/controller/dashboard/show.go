@@ -0,0 +1,149 @@
+package dashboard
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// RankedDocument is a document along with the number of users who read or highlighted it
+type RankedDocument struct {
+	index.Document
+	Count int
+}
+
+// Show renders the admin dashboard with statistics about the library and its usage
+func (d *Controller) Show(c fiber.Ctx) error {
+	stats, err := d.idx.Stats(d.config.RankingSize)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	progress, err := d.idx.IndexingProgress()
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	mostRead, err := d.readingRepository.MostRead(d.config.RankingSize)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	mostHighlighted, err := d.highlightsRepository.MostHighlighted(d.config.RankingSize)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	rankings, err := d.rank(mostRead, mostHighlighted)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	latest, err := d.idx.LatestDocs(d.config.RankingSize)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("dashboard/index", fiber.Map{
+		"Title":           "Dashboard",
+		"Stats":           stats,
+		"Progress":        progress,
+		"Users":           d.usersRepository.Total(""),
+		"ActiveUsers":     d.usersRepository.ActiveSince(time.Now().UTC().Add(-d.config.ActiveUsersPeriod)),
+		"ActiveUsersDays": int(d.config.ActiveUsersPeriod.Hours() / 24),
+		"MostRead":        rankings[0],
+		"MostHighlighted": rankings[1],
+		"LatestDocuments": latest,
+		"CacheSize":       byteSize(d.cacheSize()),
+		"RankingSize":     d.config.RankingSize,
+	}, "layout")
+}
+
+// rank retrieves from the index the documents in each of the passed rankings,
+// skipping those which are no longer in the library
+func (d *Controller) rank(rankings ...[]model.DocumentCount) ([][]RankedDocument, error) {
+	slugs := []string{}
+	for _, ranking := range rankings {
+		for _, entry := range ranking {
+			slugs = append(slugs, entry.Slug)
+		}
+	}
+
+	documents := map[string]index.Document{}
+	if len(slugs) > 0 {
+		var err error
+		if documents, err = d.idx.Documents(slugs); err != nil {
+			return nil, err
+		}
+	}
+
+	ranked := make([][]RankedDocument, len(rankings))
+	for i, ranking := range rankings {
+		ranked[i] = []RankedDocument{}
+		for _, entry := range ranking {
+			if document, ok := documents[entry.Slug]; ok && document.Slug != "" {
+				ranked[i] = append(ranked[i], RankedDocument{Document: document, Count: entry.Count})
+			}
+		}
+	}
+	return ranked, nil
+}
+
+// cacheSizeTTL is how long the size of the cache directory is reused before calculating it again
+const cacheSizeTTL = 10 * time.Minute
+
+// cacheSize returns the size in bytes of the files stored in the cache directory
+func (d *Controller) cacheSize() int64 {
+	d.cacheSizeMu.Lock()
+	defer d.cacheSizeMu.Unlock()
+
+	if time.Since(d.cacheSizeUpdatedAt) >= cacheSizeTTL {
+		d.cachedSize = d.walkCacheDir()
+		d.cacheSizeUpdatedAt = time.Now()
+	}
+	return d.cachedSize
+}
+
+// walkCacheDir adds up the size of all the files in the cache directory
+func (d *Controller) walkCacheDir() int64 {
+	var size int64
+
+	if d.config.CacheDir == "" {
+		return size
+	}
+	err := afero.Walk(d.appFs, d.config.CacheDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error calculating cache size: %s\n", err)
+	}
+	return size
+}
+
+// byteSize formats a size in bytes using the largest binary unit which keeps it above 1
+func byteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
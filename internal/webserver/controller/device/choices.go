@@ -0,0 +1,22 @@
+package device
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Choices renders the devices the logged in user can send a document to
+func (d *Controller) Choices(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	choices, err := d.devicesRepository.Choices(session.ID, c.Params("slug"))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/device-choices", fiber.Map{
+		"Slug":    c.Params("slug"),
+		"Session": session,
+		"Choices": choices,
+	})
+}
@@ -0,0 +1,55 @@
+package device
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// sentHistoryLength is the number of sent documents shown in the devices section of the user profile
+const sentHistoryLength = 20
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type devicesRepository interface {
+	ByUser(userID uint) ([]model.Device, error)
+	Save(device *model.Device) error
+	Delete(userID, deviceID uint) error
+	Choices(userID uint, documentSlug string) ([]model.DeviceChoice, error)
+	Sent(userID uint, limit int) ([]model.SentDocument, error)
+}
+
+type Controller struct {
+	usersRepository   usersRepository
+	devicesRepository devicesRepository
+}
+
+// NewController returns a new instance of the devices controller
+func NewController(usersRepository usersRepository, devicesRepository devicesRepository) *Controller {
+	return &Controller{
+		usersRepository:   usersRepository,
+		devicesRepository: devicesRepository,
+	}
+}
+
+// owner returns the user whose username is in the URL, as long as it is the one making the request,
+// as devices hold personal email addresses that not even admins should manage
+func (d *Controller) owner(c fiber.Ctx) (*model.User, error) {
+	session, ok := c.Locals("Session").(model.Session)
+	if !ok || session.Username != c.Params("username") {
+		return nil, fiber.ErrForbidden
+	}
+
+	user, err := d.usersRepository.FindByUsername(c.Params("username"))
+	if err != nil {
+		log.Println(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return nil, fiber.ErrNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,26 @@
+package device
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Delete removes a device from a user and renders the updated list
+func (d *Controller) Delete(c fiber.Ctx) error {
+	user, err := d.owner(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err = d.devicesRepository.Delete(user.ID, uint(id)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return d.renderList(c, user, map[string]string{})
+}
@@ -0,0 +1,36 @@
+package device
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// List renders the devices of a user along with the last documents they sent
+func (d *Controller) List(c fiber.Ctx) error {
+	user, err := d.owner(c)
+	if err != nil {
+		return err
+	}
+
+	return d.renderList(c, user, map[string]string{})
+}
+
+func (d *Controller) renderList(c fiber.Ctx, user *model.User, errs map[string]string) error {
+	devices, err := d.devicesRepository.ByUser(user.ID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	sent, err := d.devicesRepository.Sent(user.ID, sentHistoryLength)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/devices-list", fiber.Map{
+		"User":    user,
+		"Devices": devices,
+		"Sent":    sent,
+		"Formats": model.DeviceFormats,
+		"Errors":  errs,
+	})
+}
@@ -0,0 +1,38 @@
+package device
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Save adds a device to the user, replacing the existing one with the same name if any,
+// and renders the updated list
+func (d *Controller) Save(c fiber.Ctx) error {
+	user, err := d.owner(c)
+	if err != nil {
+		return err
+	}
+
+	maxAttachmentSize, _ := strconv.Atoi(c.FormValue("max-attachment-size"))
+	device := model.Device{
+		UserID:            user.ID,
+		Name:              strings.TrimSpace(c.FormValue("name")),
+		Email:             strings.TrimSpace(c.FormValue("email")),
+		Format:            c.FormValue("format"),
+		MaxAttachmentSize: maxAttachmentSize,
+	}
+
+	if errs := device.Validate(); len(errs) > 0 {
+		c.Status(fiber.StatusBadRequest)
+		return d.renderList(c, user, errs)
+	}
+
+	if err := d.devicesRepository.Save(&device); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return d.renderList(c, user, map[string]string{})
+}
@@ -0,0 +1,54 @@
+package document
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type addReadingSessionBody struct {
+	StartedAt       time.Time `json:"started_at"`
+	EndedAt         time.Time `json:"ended_at"`
+	StartPercentage int       `json:"start_percentage"`
+	EndPercentage   int       `json:"end_percentage"`
+}
+
+// AddReadingSession stores a period of time the user spent reading a document, as reported by the reader
+func (d *Controller) AddReadingSession(c fiber.Ctx) error {
+	document, err := d.idx.Document(c.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	session, _ := c.Locals("Session").(model.Session)
+
+	var body addReadingSessionBody
+	if err := c.Bind().Body(&body); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	readingSession := model.ReadingSession{
+		UserID:          int(session.ID),
+		Slug:            document.Slug,
+		StartedAt:       body.StartedAt,
+		EndedAt:         body.EndedAt,
+		StartPercentage: model.ClampReadingPercentage(body.StartPercentage),
+		EndPercentage:   model.ClampReadingPercentage(body.EndPercentage),
+	}
+	if errs := readingSession.Validate(); len(errs) > 0 {
+		return fiber.ErrBadRequest
+	}
+
+	if err := d.readingRepository.AddSession(&readingSession); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,6 +1,8 @@
 package document
 
 import (
+	"io"
+	"io/fs"
 	"time"
 
 	"github.com/spf13/afero"
@@ -8,17 +10,23 @@ import (
 	"github.com/svera/coreander/v4/internal/index"
 	"github.com/svera/coreander/v4/internal/metadata"
 	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
 const relatedDocuments = 4
 
 type Sender interface {
-	SendBCC(addresses []string, subject, body string) error
+	SendBCC(addresses []string, message mailer.Message) error
 	SendDocument(address, subject string, file []byte, fileName string) error
 	From() string
 }
 
+// documentQueue is implemented by senders which deliver documents in the background
+type documentQueue interface {
+	QueueDocument(address, subject string, file []byte, fileName string) (uint, error)
+}
+
 // IdxReaderWriter defines a set of reading and writing operations over an index
 type IdxReaderWriter interface {
 	Search(searchFields index.SearchFields, page, resultsPerPage int) (result.Paginated[[]index.Document], error)
@@ -47,16 +55,72 @@ type highlightsRepository interface {
 type usersRepository interface {
 	FindByEmail(email string) (*model.User, error)
 	FindByUsername(username string) (*model.User, error)
+	PreferKepub() int64
+}
+
+type converter interface {
+	Convert(documentID string, modTime time.Time, source io.ReaderAt, size int64, from, to string) (afero.File, fs.FileInfo, error)
+	Targets(from string) []string
+	Remove(documentID string)
 }
 
 type readingRepository interface {
 	Get(userID int, documentSlug string) (model.Reading, error)
 	Update(userID int, documentSlug, position string, percentage *int) error
+	UpdateAt(userID int, documentSlug, position string, percentage *int, recordedAt time.Time) error
 	Touch(userID int, documentSlug string) error
 	RemoveDocument(documentSlug string) error
 	UpdateCompletionDate(userID int, documentSlug string, completedAt *time.Time) error
 	CompletedOn(userID int, documentSlug string) (*time.Time, error)
 	CompletedPaginatedResult(userID int, results result.Paginated[[]model.AugmentedDocument]) result.Paginated[[]model.AugmentedDocument]
+	AddSession(session *model.ReadingSession) error
+	TimeSpent(userID int, documentSlug string) (time.Duration, error)
+	MeasuredWordsPerMinute(userID int) (float64, error)
+	ReadThroughs(userID int, documentSlug string) (int64, error)
+}
+
+type reviewsRepository interface {
+	Get(userID int, documentSlug string) (model.Review, error)
+	DocumentReviews(documentSlug string, viewerID int, includePrivate bool) ([]model.Review, error)
+	Ratings(documentSlugs []string) (map[string]model.DocumentRating, error)
+	Rated(offset, limit int) ([]model.DocumentRating, error)
+	RatedPaginatedResult(results result.Paginated[[]model.AugmentedDocument]) result.Paginated[[]model.AugmentedDocument]
+	RemoveDocument(documentSlug string) error
+}
+
+type shelvesRepository interface {
+	Get(shelfID uint) (*model.Shelf, error)
+	Slugs(shelfID uint) ([]string, error)
+	RemoveDocument(documentSlug string) error
+}
+
+type queueRepository interface {
+	RemoveDocument(documentSlug string) error
+}
+
+type activityRepository interface {
+	Record(userID int, activityType, documentSlug string)
+	RemoveDocument(documentSlug string) error
+}
+
+type commentsRepository interface {
+	RemoveDocument(documentSlug string) error
+}
+
+type notificationsRepository interface {
+	Notify(userIDs []int, notification model.Notification, emailed bool)
+	WantsEmail(userID int, notificationType string) bool
+	SeriesFollowers(series string) ([]int, error)
+}
+
+type devicesRepository interface {
+	Get(userID, deviceID uint) (*model.Device, error)
+	RecordSent(sent *model.SentDocument) error
+}
+
+type readerPreferencesRepository interface {
+	Get(userID uint) (model.ReaderPreferences, error)
+	Save(preferences *model.ReaderPreferences) error
 }
 
 type Config struct {
@@ -70,30 +134,50 @@ type Config struct {
 	ServerImageCacheTTL   int
 	ShareCommentMaxSize   int
 	ShareMaxRecipients    int
+	// KepubPregenerate enables converting new EPUB files to KEPUB in the background when any user prefers them
+	KepubPregenerate bool
 }
 
 type Controller struct {
-	hlRepository      highlightsRepository
-	usersRepository   usersRepository
-	readingRepository readingRepository
-	idx               IdxReaderWriter
-	sender            Sender
-	config            Config
-	metadataReaders   map[string]metadata.Reader
-	appFs             afero.Fs
-	translator        i18n.Translator
-}
-
-func NewController(hlRepository highlightsRepository, usersRepository usersRepository, readingRepository readingRepository, sender Sender, idx IdxReaderWriter, metadataReaders map[string]metadata.Reader, appFs afero.Fs, cfg Config, translator i18n.Translator) *Controller {
+	hlRepository                highlightsRepository
+	usersRepository             usersRepository
+	readingRepository           readingRepository
+	reviewsRepository           reviewsRepository
+	shelvesRepository           shelvesRepository
+	queueRepository             queueRepository
+	activityRepository          activityRepository
+	commentsRepository          commentsRepository
+	notificationsRepository     notificationsRepository
+	devicesRepository           devicesRepository
+	readerPreferencesRepository readerPreferencesRepository
+	converter                   converter
+	idx                         IdxReaderWriter
+	sender                      Sender
+	config                      Config
+	metadataReaders             map[string]metadata.Reader
+	appFs                       afero.Fs
+	translator                  i18n.Translator
+}
+
+func NewController(hlRepository highlightsRepository, usersRepository usersRepository, readingRepository readingRepository, reviewsRepository reviewsRepository, shelvesRepository shelvesRepository, queueRepository queueRepository, activityRepository activityRepository, commentsRepository commentsRepository, notificationsRepository notificationsRepository, devicesRepository devicesRepository, readerPreferencesRepository readerPreferencesRepository, converter converter, sender Sender, idx IdxReaderWriter, metadataReaders map[string]metadata.Reader, appFs afero.Fs, cfg Config, translator i18n.Translator) *Controller {
 	return &Controller{
-		hlRepository:      hlRepository,
-		usersRepository:   usersRepository,
-		readingRepository: readingRepository,
-		idx:               idx,
-		sender:            sender,
-		config:            cfg,
-		metadataReaders:   metadataReaders,
-		appFs:             appFs,
-		translator:        translator,
+		hlRepository:                hlRepository,
+		usersRepository:             usersRepository,
+		readingRepository:           readingRepository,
+		reviewsRepository:           reviewsRepository,
+		shelvesRepository:           shelvesRepository,
+		queueRepository:             queueRepository,
+		activityRepository:          activityRepository,
+		commentsRepository:          commentsRepository,
+		notificationsRepository:     notificationsRepository,
+		devicesRepository:           devicesRepository,
+		readerPreferencesRepository: readerPreferencesRepository,
+		converter:                   converter,
+		idx:                         idx,
+		sender:                      sender,
+		config:                      cfg,
+		metadataReaders:             metadataReaders,
+		appFs:                       appFs,
+		translator:                  translator,
 	}
 }
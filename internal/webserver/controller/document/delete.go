@@ -11,6 +11,11 @@ import (
 func (d *Controller) Delete(c fiber.Ctx) error {
 	slug := c.Params("slug")
 
+	document, err := d.idx.Document(slug)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
 	if err := d.idx.DeleteDocument(slug); err != nil {
 		if errors.Is(err, index.ErrDocumentNotFound) {
 			return fiber.ErrNotFound
@@ -18,6 +23,8 @@ func (d *Controller) Delete(c fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
+	d.converter.Remove(document.ID)
+
 	if err := d.hlRepository.RemoveDocument(slug); err != nil {
 		log.Printf("error removing document %s from highlights\n", slug)
 	}
@@ -26,5 +33,25 @@ func (d *Controller) Delete(c fiber.Ctx) error {
 		log.Printf("error removing document %s from readings\n", slug)
 	}
 
+	if err := d.reviewsRepository.RemoveDocument(slug); err != nil {
+		log.Printf("error removing document %s from reviews\n", slug)
+	}
+
+	if err := d.shelvesRepository.RemoveDocument(slug); err != nil {
+		log.Printf("error removing document %s from shelves\n", slug)
+	}
+
+	if err := d.queueRepository.RemoveDocument(slug); err != nil {
+		log.Printf("error removing document %s from reading queues\n", slug)
+	}
+
+	if err := d.activityRepository.RemoveDocument(slug); err != nil {
+		log.Printf("error removing document %s from activities\n", slug)
+	}
+
+	if err := d.commentsRepository.RemoveDocument(slug); err != nil {
+		log.Printf("error removing document %s from comments\n", slug)
+	}
+
 	return nil
 }
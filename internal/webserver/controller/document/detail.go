@@ -8,6 +8,7 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/metadata"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
@@ -38,14 +39,40 @@ func (d *Controller) Detail(c fiber.Ctx) error {
 
 	sameSubjects, sameAuthors, sameSeries := d.related(document.Slug, int(session.ID))
 
-	var completedOn *time.Time
+	var (
+		completedOn  *time.Time
+		timeSpent    string
+		readThroughs int64
+		ownReview    model.Review
+	)
 	result := model.AugmentedDocument{Document: document}
+	if ratings, err := d.reviewsRepository.Ratings([]string{document.Slug}); err == nil {
+		result.Rating = ratings[document.Slug]
+	}
+	reviews, err := d.reviewsRepository.DocumentReviews(document.Slug, int(session.ID), session.Role == model.RoleAdmin)
+	if err != nil {
+		log.Println(err)
+	}
 	if session.ID > 0 {
 		result = d.hlRepository.Highlighted(int(session.ID), result)
 		completedOn, err = d.readingRepository.CompletedOn(int(session.ID), result.Slug)
 		if err != nil {
 			log.Println(err)
 		}
+		if spent, err := d.readingRepository.TimeSpent(int(session.ID), result.Slug); err == nil && spent >= time.Minute {
+			timeSpent = metadata.FmtDuration(spent)
+		}
+		if readThroughs, err = d.readingRepository.ReadThroughs(int(session.ID), result.Slug); err != nil {
+			log.Println(err)
+		}
+		if ownReview, err = d.reviewsRepository.Get(int(session.ID), result.Slug); err != nil {
+			log.Println(err)
+		}
+	}
+
+	ratings := make([]int, model.MaxRating)
+	for i := range ratings {
+		ratings[i] = i + 1
 	}
 
 	result.CompletedOn = completedOn
@@ -57,6 +84,13 @@ func (d *Controller) Detail(c fiber.Ctx) error {
 		"SameAuthors":    sameAuthors,
 		"SameSubjects":   sameSubjects,
 		"WordsPerMinute": d.config.WordsPerMinute,
+		"TimeSpent":      timeSpent,
+		"ReadThroughs":   readThroughs,
+		"Reviews":        reviews,
+		"OwnReview":      ownReview,
+		"Ratings":        ratings,
+		"ReviewMaxSize":  model.ReviewTextMaxLength,
+		"Conversions":    d.converter.Targets(strings.ToLower(document.Format)),
 	}, "layout")
 }
 
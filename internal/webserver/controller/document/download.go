@@ -1,47 +1,169 @@
 package document
 
 import (
-	"archive/zip"
-	"bytes"
-	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
-	"github.com/pgaskin/kepubify/v4/kepub"
+	"github.com/svera/coreander/v4/internal/conversion"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/valyala/fasthttp"
 )
 
+// Download streams a document file, converted to the format passed in the format query parameter if any.
+// Files are sent as attachments unless the disposition=inline query parameter is passed, which is used
+// when the file is opened in the browser instead of saved.
 func (d *Controller) Download(c fiber.Ctx) error {
 	slug := c.Params("slug")
 
-	result, err := d.idx.File(slug)
+	file, err := d.idx.File(slug)
 	if err != nil {
 		return fiber.ErrNotFound
 	}
 
-	data := result.Data
-	fileName := result.FileName
-	contentType := result.ContentType
+	content, err := d.content(file, strings.ToLower(c.Query("format")))
+	if err != nil {
+		return err
+	}
+
+	disposition := "attachment"
+	if c.Query("disposition") == "inline" {
+		disposition = "inline"
+	}
+
+	c.Response().Header.Set(fiber.HeaderContentType, content.contentType)
+	c.Response().Header.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename=\"%s\"", disposition, content.fileName))
+	return serveContent(c, content, content.size, file.ModTime, content.etag)
+}
+
+// documentContent is a document file, or its conversion to another format, ready to be sent
+type documentContent struct {
+	io.ReadSeekCloser
+	size        int64
+	fileName    string
+	contentType string
+	etag        string
+}
+
+// content returns the contents of a document file converted to format, or the file itself if format is empty
+// or the one of the file. The file is closed if it is converted.
+func (d *Controller) content(file *index.IndexedFile, format string) (documentContent, error) {
+	content := documentContent{
+		ReadSeekCloser: file.Content,
+		size:           file.Size,
+		fileName:       file.FileName,
+		contentType:    file.ContentType,
+		etag:           fmt.Sprintf("\"%x-%x\"", file.ModTime.UnixNano(), file.Size),
+	}
+
+	source := strings.TrimPrefix(strings.ToLower(filepath.Ext(file.FileName)), ".")
+	if format == "" || format == source {
+		return content, nil
+	}
+
+	converted, info, err := d.converter.Convert(file.Document.ID, file.ModTime, file.Content, file.Size, source, format)
+	file.Content.Close()
+	if errors.Is(err, conversion.ErrUnsupportedConversion) {
+		return documentContent{}, fiber.NewError(fiber.StatusNotAcceptable, fmt.Sprintf("%s documents cannot be converted to %s", source, format))
+	}
+	if err != nil {
+		log.Println(err)
+		return documentContent{}, fiber.ErrInternalServerError
+	}
+
+	content.ReadSeekCloser = converted
+	content.size = info.Size()
+	content.fileName = strings.TrimSuffix(file.FileName, filepath.Ext(file.FileName)) + conversion.Extension(format)
+	content.contentType = conversion.ContentType(format)
+	content.etag = fmt.Sprintf("\"%x-%x-%s\"", file.ModTime.UnixNano(), file.Size, format)
+	return content, nil
+}
+
+// serveContent streams content honouring conditional and single range requests. Multiple ranges are not
+// supported, so the whole content is sent when they are requested, as allowed by RFC 9110.
+// content is closed once sent.
+func serveContent(c fiber.Ctx, content io.ReadSeekCloser, size int64, modTime time.Time, etag string) error {
+	header := &c.Response().Header
+	header.Set(fiber.HeaderAcceptRanges, "bytes")
+	header.Set(fiber.HeaderETag, etag)
+	header.Set(fiber.HeaderLastModified, modTime.UTC().Format(http.TimeFormat))
+	// Documents are already compressed, and compressing them would remove their length from the response
+	header.Set(fiber.HeaderCacheControl, "private, no-transform")
 
-	if strings.ToLower(c.Query("format")) == "kepub" && result.ContentType == "application/epub+zip" {
-		z, err := zip.NewReader(bytes.NewReader(result.Data), int64(len(result.Data)))
+	if notModified(c, etag, modTime) {
+		content.Close()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	start, end := int64(0), size-1
+	status := fiber.StatusOK
+	if byteRange := c.Get(fiber.HeaderRange); byteRange != "" && !strings.Contains(byteRange, ",") && rangeApplies(c, etag, modTime) {
+		rangeStart, rangeEnd, err := fasthttp.ParseByteRange([]byte(byteRange), int(size))
 		if err != nil {
-			log.Println(err)
-			return fiber.ErrInternalServerError
+			content.Close()
+			header.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
 		}
-		buf := bytes.NewBuffer(nil)
-		if err := kepub.NewConverter().Convert(context.Background(), buf, z); err != nil {
-			log.Println(err)
-			return fiber.ErrInternalServerError
+		start, end = int64(rangeStart), int64(rangeEnd)
+		status = fiber.StatusPartialContent
+		header.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	}
+
+	if _, err := content.Seek(start, io.SeekStart); err != nil {
+		content.Close()
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	length := end - start + 1
+	return c.Status(status).SendStream(readCloser{io.LimitReader(content, length), content}, int(length))
+}
+
+// notModified tells whether the copy the client has is still valid. If-None-Match takes precedence
+// over If-Modified-Since.
+func notModified(c fiber.Ctx, etag string, modTime time.Time) bool {
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
 		}
-		data = buf.Bytes()
-		fileName = strings.TrimSuffix(filepath.Base(result.FileName), filepath.Ext(result.FileName)) + ".kepub.epub"
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	return !modTime.Truncate(time.Second).After(since)
+}
+
+// rangeApplies tells whether a range request has to be honoured, which is not the case when If-Range
+// refers to a different version of the content than the current one
+func rangeApplies(c fiber.Ctx, etag string, modTime time.Time) bool {
+	ifRange := c.Get(fiber.HeaderIfRange)
+	if ifRange == "" {
+		return true
 	}
+	if strings.HasPrefix(ifRange, "\"") {
+		return ifRange == etag
+	}
+	date, err := http.ParseTime(ifRange)
+	if err != nil {
+		return false
+	}
+	return modTime.Truncate(time.Second).Equal(date)
+}
 
-	c.Response().Header.Set(fiber.HeaderContentType, contentType)
-	c.Response().Header.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"%s\"", fileName))
-	c.Response().BodyWriter().Write(data)
-	return nil
+// readCloser makes the response close the file after sending the part of it being read
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
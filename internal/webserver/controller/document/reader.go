@@ -26,11 +26,41 @@ func (d *Controller) Reader(c fiber.Ctx) error {
 	if val, ok := c.Locals("Session").(model.Session); ok {
 		session = val
 	}
+	wordsPerMinute := d.config.WordsPerMinute
+	completed := false
+	var preferences *readerPreferencesBody
 	if session.ID > 0 {
-		if err := d.readingRepository.Touch(int(session.ID), document.Slug); err != nil {
+		// Pages downloaded to be read offline later must not count as the document being opened
+		if !fiber.Query[bool](c, "offline") {
+			_, err := d.readingRepository.Get(int(session.ID), document.Slug)
+			started := err != nil
+			if err := d.readingRepository.Touch(int(session.ID), document.Slug); err != nil {
+				log.Println(err)
+				return fiber.ErrInternalServerError
+			}
+			if started {
+				d.activityRepository.Record(int(session.ID), model.ActivityStarted, document.Slug)
+			}
+		}
+		completedOn, err := d.readingRepository.CompletedOn(int(session.ID), document.Slug)
+		if err != nil {
 			log.Println(err)
 			return fiber.ErrInternalServerError
 		}
+		completed = completedOn != nil
+		if session.WordsPerMinute > 0 {
+			wordsPerMinute = session.WordsPerMinute
+		}
+		// Time left estimations are more accurate using the speed measured from the user's reading sessions
+		if measured, err := d.readingRepository.MeasuredWordsPerMinute(int(session.ID)); err == nil && measured > 0 {
+			wordsPerMinute = measured
+		}
+		stored, err := d.readerPreferencesRepository.Get(session.ID)
+		if err != nil {
+			return fiber.ErrInternalServerError
+		}
+		body := readerPreferencesJSON(stored)
+		preferences = &body
 	}
 
 	title := document.Title
@@ -39,9 +69,14 @@ func (d *Controller) Reader(c fiber.Ctx) error {
 		title = fmt.Sprintf("%s - %s", authors, document.Title)
 	}
 	return c.Render("document/reader", fiber.Map{
-		"Title":       title,
-		"Author":      strings.Join(document.Authors, ", "),
-		"Description": document.Description,
-		"Slug":        document.Slug,
+		"Title":          title,
+		"Author":         strings.Join(document.Authors, ", "),
+		"Description":    document.Description,
+		"Language":       document.Language,
+		"Slug":           document.Slug,
+		"Words":          document.Words,
+		"WordsPerMinute": wordsPerMinute,
+		"Completed":      completed,
+		"Preferences":    preferences,
 	})
 }
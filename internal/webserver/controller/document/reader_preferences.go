@@ -0,0 +1,55 @@
+package document
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type readerPreferencesBody struct {
+	model.ReaderPreferences
+	// Updated is empty until the user changes the default preferences
+	Updated string `json:"updated"`
+}
+
+func (d *Controller) ReaderPreferences(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	preferences, err := d.readerPreferencesRepository.Get(session.ID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(readerPreferencesJSON(preferences))
+}
+
+func (d *Controller) UpdateReaderPreferences(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	var body readerPreferencesBody
+	if err := c.Bind().Body(&body); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	preferences := body.ReaderPreferences
+	if errs := preferences.Validate(); len(errs) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(errs)
+	}
+
+	preferences.UserID = session.ID
+	if err := d.readerPreferencesRepository.Save(&preferences); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func readerPreferencesJSON(preferences model.ReaderPreferences) readerPreferencesBody {
+	body := readerPreferencesBody{ReaderPreferences: preferences}
+	if !preferences.UpdatedAt.IsZero() {
+		body.Updated = preferences.UpdatedAt.UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	return body
+}
@@ -29,17 +29,33 @@ func (d *Controller) Search(c fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
+	shelf, err := d.shelfFilter(c, session)
+	if err != nil {
+		return err
+	}
+	if shelf != nil {
+		if searchFields.Slugs, err = d.shelvesRepository.Slugs(shelf.ID); err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+
 	page, err := strconv.Atoi(c.Query("page"))
 	if err != nil {
 		page = 1
 	}
 
-	if documentResults, err = d.idx.Search(searchFields, page, model.ResultsPerPage); err != nil {
+	if c.Query("sort-by") == "rating-higher-first" {
+		documentResults, err = d.searchByRating(searchFields, page)
+	} else {
+		documentResults, err = d.idx.Search(searchFields, page, model.ResultsPerPage)
+	}
+	if err != nil {
 		log.Println(err)
 		return fiber.ErrInternalServerError
 	}
 
 	searchResults := model.AugmentedDocumentsFromDocuments(documentResults)
+	searchResults = d.reviewsRepository.RatedPaginatedResult(searchResults)
 	if session.ID > 0 {
 		searchResults = d.readingRepository.CompletedPaginatedResult(int(session.ID), searchResults)
 		searchResults = d.hlRepository.HighlightedPaginatedResult(int(session.ID), searchResults)
@@ -47,6 +63,7 @@ func (d *Controller) Search(c fiber.Ctx) error {
 
 	templateVars := fiber.Map{
 		"SearchFields":        searchFields,
+		"Shelf":               shelf,
 		"Results":             searchResults,
 		"Paginator":           view.Pagination(model.MaxPagesNavigator, searchResults, c.Queries()),
 		"Title":               "Search results",
@@ -65,6 +82,7 @@ func (d *Controller) Search(c fiber.Ctx) error {
 			{"pub-date-newer-first", "newer"},
 			{"est-read-time-shorter-first", "shorter"},
 			{"est-read-time-longer-first", "longer"},
+			{"rating-higher-first", "best rated"},
 		},
 	}
 
@@ -84,6 +102,124 @@ func (d *Controller) Search(c fiber.Ctx) error {
 	return nil
 }
 
+// shelfFilter returns the shelf search results are restricted to, if any, as long as the user can see it
+func (d *Controller) shelfFilter(c fiber.Ctx, session model.Session) (*model.Shelf, error) {
+	shelfID, err := strconv.ParseUint(c.Query("shelf"), 10, 0)
+	if err != nil || shelfID == 0 {
+		return nil, nil
+	}
+
+	shelf, err := d.shelvesRepository.Get(uint(shelfID))
+	if err != nil {
+		return nil, fiber.ErrInternalServerError
+	}
+	if shelf == nil || !shelf.CanView(int(session.ID)) {
+		return nil, fiber.ErrNotFound
+	}
+	return shelf, nil
+}
+
+// ratingBatchSize is how many rated documents are looked up in the index at a time when sorting by rating
+const ratingBatchSize = 100
+
+// searchByRating returns the requested page of results sorted from the best to the worst rated.
+// As ratings are not part of the index, rated documents are walked in batches from the best rated one
+// and looked up in the index until the page is filled, and unrated documents matching the search follow them.
+func (d *Controller) searchByRating(searchFields index.SearchFields, page int) (result.Paginated[[]index.Document], error) {
+	if page < 1 {
+		page = 1
+	}
+
+	matching, err := d.idx.Search(searchFields, 1, 1)
+	if err != nil || matching.TotalHits() == 0 {
+		return result.Paginated[[]index.Document]{}, err
+	}
+
+	var inShelf map[string]struct{}
+	if searchFields.Slugs != nil {
+		inShelf = make(map[string]struct{}, len(searchFields.Slugs))
+		for _, slug := range searchFields.Slugs {
+			inShelf[slug] = struct{}{}
+		}
+	}
+
+	from := (page - 1) * model.ResultsPerPage
+	documents := make([]index.Document, 0, model.ResultsPerPage)
+	var rated []string
+	for offset := 0; len(documents) < model.ResultsPerPage; offset += ratingBatchSize {
+		ratings, err := d.reviewsRepository.Rated(offset, ratingBatchSize)
+		if err != nil {
+			return result.Paginated[[]index.Document]{}, err
+		}
+
+		batch := make([]string, 0, len(ratings))
+		for _, rating := range ratings {
+			if _, ok := inShelf[rating.Slug]; inShelf == nil || ok {
+				batch = append(batch, rating.Slug)
+			}
+		}
+		if len(batch) > 0 {
+			batchFields := searchFields
+			batchFields.Slugs = batch
+			found, err := d.idx.Search(batchFields, 1, len(batch))
+			if err != nil {
+				return result.Paginated[[]index.Document]{}, err
+			}
+			bySlug := make(map[string]index.Document, len(found.Hits()))
+			for _, doc := range found.Hits() {
+				bySlug[doc.Slug] = doc
+			}
+			for _, slug := range batch {
+				doc, ok := bySlug[slug]
+				if !ok {
+					continue
+				}
+				if len(rated) >= from && len(documents) < model.ResultsPerPage {
+					documents = append(documents, doc)
+				}
+				rated = append(rated, slug)
+			}
+		}
+
+		if len(ratings) < ratingBatchSize {
+			break
+		}
+	}
+
+	if len(documents) < model.ResultsPerPage {
+		unratedFields := searchFields
+		unratedFields.ExcludeSlugs = rated
+		unrated, err := d.searchFrom(unratedFields, max(from-len(rated), 0), model.ResultsPerPage-len(documents))
+		if err != nil {
+			return result.Paginated[[]index.Document]{}, err
+		}
+		documents = append(documents, unrated...)
+	}
+
+	return result.NewPaginated(model.ResultsPerPage, page, matching.TotalHits(), documents), nil
+}
+
+// searchFrom returns up to limit documents matching searchFields, skipping the first offset ones
+func (d *Controller) searchFrom(searchFields index.SearchFields, offset, limit int) ([]index.Document, error) {
+	page := offset/limit + 1
+	skip := offset % limit
+
+	first, err := d.idx.Search(searchFields, page, limit)
+	if err != nil {
+		return nil, err
+	}
+	documents := first.Hits()[min(skip, len(first.Hits())):]
+	if skip == 0 || len(first.Hits()) < limit {
+		return documents, nil
+	}
+
+	next, err := d.idx.Search(searchFields, page+1, limit)
+	if err != nil {
+		return nil, err
+	}
+	return append(documents, next.Hits()[:min(skip, len(next.Hits()))]...), nil
+}
+
 func (d *Controller) parseSearchQuery(c fiber.Ctx) (index.SearchFields, error) {
 	searchFields := index.SearchFields{
 		Keywords:        c.Query("search"),
@@ -1,18 +1,45 @@
 package document
 
 import (
+	"cmp"
 	"errors"
+	"io"
 	"log"
 	"net/mail"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
+// Send emails a document to the address in the form, or to one of the devices of the logged in user.
+// Documents sent to a device are converted to its format when possible, and sent in their original one otherwise.
 func (d *Controller) Send(c fiber.Ctx) error {
 	slug := c.Params("slug")
+	session, _ := c.Locals("Session").(model.Session)
 
-	if _, err := mail.ParseAddress(c.FormValue("email")); err != nil {
+	email := c.FormValue("email")
+	format := strings.ToLower(c.FormValue("format"))
+	var device *model.Device
+	if c.FormValue("device") != "" {
+		id, err := strconv.ParseUint(c.FormValue("device"), 10, 0)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		if device, err = d.devicesRepository.Get(session.ID, uint(id)); err != nil {
+			return fiber.ErrInternalServerError
+		}
+		if device == nil {
+			return fiber.ErrNotFound
+		}
+		email = device.Email
+	}
+
+	if _, err := mail.ParseAddress(email); err != nil {
 		return fiber.ErrBadRequest
 	}
 
@@ -24,5 +51,65 @@ func (d *Controller) Send(c fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
-	return d.sender.SendDocument(c.FormValue("email"), file.Document.Title, file.Data, file.FileName)
+	source := strings.TrimPrefix(strings.ToLower(filepath.Ext(file.FileName)), ".")
+	if device != nil {
+		format = device.Format
+		if !slices.Contains(d.converter.Targets(source), format) {
+			format = ""
+		}
+	}
+
+	content, err := d.content(file, format)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	// Attachments need the whole file anyway
+	data, err := io.ReadAll(content)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if device != nil && !device.Fits(len(data)) {
+		return fiber.ErrRequestEntityTooLarge
+	}
+
+	deliveryID, err := d.sendDocument(email, file.Document.Title, data, content.fileName)
+	if err != nil {
+		log.Printf("error sending document: %v\n", err)
+		return fiber.ErrInternalServerError
+	}
+
+	sent := model.SentDocument{
+		UserID:          session.ID,
+		Email:           email,
+		Slug:            slug,
+		Title:           file.Document.Title,
+		Format:          cmp.Or(format, source),
+		EmailDeliveryID: deliveryID,
+	}
+	if device != nil {
+		sent.DeviceID = &device.ID
+		sent.DeviceName = device.Name
+	}
+	// Failing to record the document does not change the fact it has been sent
+	d.devicesRepository.RecordSent(&sent)
+
+	return nil
+}
+
+// sendDocument sends a document through the configured sender, returning the ID of its delivery
+// if the sender queues it
+func (d *Controller) sendDocument(address, subject string, file []byte, fileName string) (*uint, error) {
+	queue, ok := d.sender.(documentQueue)
+	if !ok {
+		return nil, d.sender.SendDocument(address, subject, file, fileName)
+	}
+	deliveryID, err := queue.QueueDocument(address, subject, file, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return &deliveryID, nil
 }
@@ -9,10 +9,13 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/index"
 	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
-	"golang.org/x/exp/slices"
 )
 
+// shareEmailCoverWidth is the width in pixels of the document cover shown in share emails
+const shareEmailCoverWidth = 200
+
 func (d *Controller) Share(c fiber.Ctx) error {
 	session, _ := c.Locals("Session").(model.Session)
 	if session.PrivateProfile != 0 {
@@ -90,56 +93,75 @@ func (d *Controller) Share(c fiber.Ctx) error {
 			return fiber.ErrInternalServerError
 		}
 
-		if err := d.sendShareEmails(c, newRecipients, senderName, document.Title, docURL, highlightsURL, comment); err != nil {
+		emailRecipients := make([]*model.User, 0, len(newRecipients))
+		for _, user := range newRecipients {
+			if d.notificationsRepository.WantsEmail(int(user.ID), model.NotificationShared) {
+				emailRecipients = append(emailRecipients, user)
+			}
+		}
+		if err := d.sendShareEmails(c, emailRecipients, senderName, document, docURL, highlightsURL, comment); err != nil {
 			return err
 		}
+
+		d.notificationsRepository.Notify(newRecipientIDs, model.Notification{
+			Type:  model.NotificationShared,
+			Actor: senderName,
+			Slug:  document.Slug,
+			Title: document.Title,
+			Link:  "/documents/" + document.Slug,
+		}, true)
+
+		d.activityRepository.Record(int(session.ID), model.ActivityShared, document.Slug)
 	}
 
 	return nil
 }
 
-func (d *Controller) sendShareEmails(c fiber.Ctx, recipientUsers []*model.User, senderName, documentTitle, docURL, highlightsURL, comment string) error {
+func (d *Controller) sendShareEmails(c fiber.Ctx, recipientUsers []*model.User, senderName string, document index.Document, docURL, highlightsURL, comment string) error {
 	if _, ok := d.sender.(*infrastructure.NoEmail); ok {
 		return nil
 	}
 
-	supportedLanguages := d.translator.SupportedLanguages()
-	// Group recipients by language
+	composer := mailer.NewComposer(c.App().Config().Views, d.translator)
+	// Group recipients by the language their email is written in
 	recipientsByLang := make(map[string][]*model.User)
 	for _, recipientUser := range recipientUsers {
-		// Use recipient's language preference, fallback to "en" if not set or not supported
-		recipientLang := recipientUser.Language
-		if recipientLang == "" || !slices.Contains(supportedLanguages, recipientLang) {
-			recipientLang = "en"
-		}
+		recipientLang := composer.Language(recipientUser.Language)
 		recipientsByLang[recipientLang] = append(recipientsByLang[recipientLang], recipientUser)
 	}
 
+	// Documents without a cover are shared without it
+	cover, err := d.idx.Cover(document.Slug, shareEmailCoverWidth)
+	if err != nil {
+		cover = nil
+	}
+
 	// Send one BCC email per language group
 	for recipientLang, langRecipients := range recipientsByLang {
-		subject := d.translator.T(recipientLang, "%s shared \"%s\"", senderName, documentTitle)
-
-		// Render email template in this language
-		if err := c.Render("document/share-email", fiber.Map{
-			"Lang":          recipientLang,
+		message, err := composer.Compose("share", recipientLang, fiber.Map{
 			"SenderName":    senderName,
-			"DocumentTitle": documentTitle,
+			"DocumentTitle": document.Title,
 			"DocumentURL":   docURL,
 			"HighlightsURL": highlightsURL,
 			"Comment":       comment,
-		}); err != nil {
+			"Cover":         len(cover) > 0,
+			"CoverWidth":    shareEmailCoverWidth,
+		}, "%s shared \"%s\"", senderName, document.Title)
+		if err != nil {
 			log.Printf("error rendering email: %v\n", err)
 			return fiber.ErrInternalServerError
 		}
+		if len(cover) > 0 {
+			message.Embed("cover", "cover.jpg", cover)
+		}
 
-		body := string(c.Response().Body())
 		// Collect all email addresses for this language group
 		addresses := make([]string, 0, len(langRecipients))
 		for _, recipientUser := range langRecipients {
 			addresses = append(addresses, recipientUser.Email)
 		}
 
-		if err := d.sender.SendBCC(addresses, subject, body); err != nil {
+		if err := d.sender.SendBCC(addresses, message); err != nil {
 			log.Printf("error sending share email: %v\n", err)
 			return fiber.ErrInternalServerError
 		}
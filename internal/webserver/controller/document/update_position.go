@@ -2,6 +2,7 @@ package document
 
 import (
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/webserver/model"
@@ -10,6 +11,8 @@ import (
 type updateReadingPositionBody struct {
 	Position   string `json:"position"`
 	Percentage *int   `json:"percentage"`
+	// Updated is only sent for positions saved while offline, set to the time they were recorded
+	Updated *time.Time `json:"updated"`
 }
 
 func (d *Controller) UpdatePosition(c fiber.Ctx) error {
@@ -30,7 +33,17 @@ func (d *Controller) UpdatePosition(c fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	if err := d.readingRepository.Update(int(session.ID), document.Slug, body.Position, body.Percentage); err != nil {
+	if body.Updated != nil {
+		// Don't trust clocks ahead of the server's, as those positions would never be overwritten
+		recordedAt := body.Updated.UTC()
+		if now := time.Now().UTC(); recordedAt.After(now) {
+			recordedAt = now
+		}
+		err = d.readingRepository.UpdateAt(int(session.ID), document.Slug, body.Position, body.Percentage, recordedAt)
+	} else {
+		err = d.readingRepository.Update(int(session.ID), document.Slug, body.Position, body.Percentage)
+	}
+	if err != nil {
 		log.Println(err)
 		return fiber.ErrInternalServerError
 	}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/log"
+	"github.com/svera/coreander/v4/internal/conversion"
+	"github.com/svera/coreander/v4/internal/webserver/model"
 	"github.com/valyala/fasthttp"
 )
 
@@ -74,6 +76,9 @@ func (d *Controller) Upload(c fiber.Ctx) error {
 		return internalServerErrorStatus
 	}
 
+	d.notifySeriesFollowers(c, slug)
+	d.pregenerateKepub(slug)
+
 	c.Cookie(&fiber.Cookie{
 		Name:    "success-once",
 		Value:   "Document uploaded successfully.",
@@ -82,6 +87,57 @@ func (d *Controller) Upload(c fiber.Ctx) error {
 	return c.Redirect().To(fmt.Sprintf("/documents/%s", slug))
 }
 
+// notifySeriesFollowers tells the users following the series of a new document, if any, that it has been added
+func (d *Controller) notifySeriesFollowers(c fiber.Ctx, slug string) {
+	document, err := d.idx.Document(slug)
+	if err != nil || document.SeriesSlug == "" {
+		return
+	}
+
+	followers, err := d.notificationsRepository.SeriesFollowers(document.SeriesSlug)
+	if err != nil {
+		return
+	}
+
+	session, _ := c.Locals("Session").(model.Session)
+	followers = slices.DeleteFunc(followers, func(userID int) bool {
+		return userID == int(session.ID)
+	})
+
+	d.notificationsRepository.Notify(followers, model.Notification{
+		Type:    model.NotificationNewInSeries,
+		Subject: document.Series,
+		Slug:    document.Slug,
+		Title:   document.Title,
+		Link:    "/documents/" + document.Slug,
+	}, false)
+}
+
+// pregenerateKepub converts a new EPUB file to KEPUB in the background, so users who prefer that format
+// do not have to wait for the conversion when downloading it
+func (d *Controller) pregenerateKepub(slug string) {
+	if !d.config.KepubPregenerate || d.usersRepository.PreferKepub() == 0 {
+		return
+	}
+
+	go func() {
+		file, err := d.idx.File(slug)
+		if err != nil {
+			return
+		}
+		defer file.Content.Close()
+		if file.ContentType != "application/epub+zip" {
+			return
+		}
+		converted, _, err := d.converter.Convert(file.Document.ID, file.ModTime, file.Content, file.Size, conversion.EPUB, conversion.KEPUB)
+		if err != nil {
+			log.Errorf("error converting %s to KEPUB: %s", slug, err)
+			return
+		}
+		converted.Close()
+	}()
+}
+
 func fileToBytes(fileHeader *multipart.FileHeader) ([]byte, error) {
 	f, err := fileHeader.Open()
 	if err != nil {
@@ -0,0 +1,32 @@
+package email
+
+import (
+	"github.com/svera/coreander/v4/internal/i18n"
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type emailRepository interface {
+	List(status string, page int, resultsPerPage int) (result.Paginated[[]model.EmailDelivery], error)
+	Retry(deliveryID uint) (bool, error)
+}
+
+type Sender interface {
+	Send(address string, message mailer.Message) error
+}
+
+type Controller struct {
+	emailRepository emailRepository
+	sender          Sender
+	translator      i18n.Translator
+}
+
+// NewController returns a new instance of the email deliveries controller
+func NewController(emailRepository emailRepository, sender Sender, translator i18n.Translator) *Controller {
+	return &Controller{
+		emailRepository: emailRepository,
+		sender:          sender,
+		translator:      translator,
+	}
+}
@@ -0,0 +1,38 @@
+package email
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// List renders the deliveries of outgoing emails, optionally filtered by status
+func (e *Controller) List(c fiber.Ctx) error {
+	status := c.Query("status")
+	if status != "" && !slices.Contains(model.EmailStatuses, status) {
+		return fiber.ErrBadRequest
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	deliveries, err := e.emailRepository.List(status, page, model.ResultsPerPage)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	_, noEmail := e.sender.(*infrastructure.NoEmail)
+	return c.Render("email/list", fiber.Map{
+		"Title":                  "Email deliveries",
+		"Deliveries":             deliveries.Hits(),
+		"Paginator":              view.Pagination(model.MaxPagesNavigator, deliveries, c.Queries()),
+		"Status":                 status,
+		"EmailSendingConfigured": !noEmail,
+	}, "layout")
+}
@@ -0,0 +1,103 @@
+package email
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Preview renders an email template with sample data in the chosen language, along with its plain text version
+func (e *Controller) Preview(c fiber.Ctx) error {
+	name := c.Query("template", mailer.Templates[0])
+	if !slices.Contains(mailer.Templates, name) {
+		return fiber.ErrBadRequest
+	}
+
+	composer := mailer.NewComposer(c.App().Config().Views, e.translator)
+	lang := composer.Language(c.Query("lang", c.Locals("Lang").(string)))
+
+	vars, subject, values := sample(name, c.BaseURL())
+	message, err := composer.Compose(name, lang, vars, subject, values...)
+	if err != nil {
+		log.Printf("error rendering email preview: %s\n", err)
+		return fiber.ErrInternalServerError
+	}
+	if name == "share" {
+		message.Embed("cover", "cover.jpg", sampleCover())
+	}
+
+	return c.Render("email/preview", fiber.Map{
+		"Title":     "Email templates",
+		"Templates": mailer.Templates,
+		"Template":  name,
+		"Languages": e.translator.SupportedLanguages(),
+		"EmailLang": lang,
+		"Message":   message,
+		"Preview":   message.Preview(),
+	}, "layout")
+}
+
+// sample returns the variables and subject used to preview an email template
+func sample(name, baseURL string) (fiber.Map, string, []any) {
+	switch name {
+	case "invitation":
+		return fiber.Map{
+			"InvitationLink":    baseURL + "/invite?id=00000000-0000-0000-0000-000000000000",
+			"InvitationTimeout": "72",
+		}, "You've been invited to join Coreander", nil
+	case "recovery":
+		return fiber.Map{
+			"RecoveryLink":    baseURL + "/reset-password?id=00000000-0000-0000-0000-000000000000",
+			"RecoveryTimeout": "2",
+		}, "Password recovery request", nil
+	case "share":
+		return fiber.Map{
+			"SenderName":    "Jane Doe",
+			"DocumentTitle": "Moby Dick",
+			"DocumentURL":   baseURL + "/documents/moby-dick",
+			"HighlightsURL": baseURL + "/highlights",
+			"Comment":       "Call me Ishmael.",
+			"Cover":         true,
+			"CoverWidth":    200,
+		}, "%s shared \"%s\"", []any{"Jane Doe", "Moby Dick"}
+	case "test":
+		return fiber.Map{}, "Coreander test email", nil
+	case "notification":
+		return fiber.Map{
+			"Notification": model.Notification{Type: model.NotificationShared, Actor: "Jane Doe", Title: "Moby Dick"},
+			"Link":         baseURL + "/documents/moby-dick",
+		}, "%s shared \"%s\"", []any{"Jane Doe", "Moby Dick"}
+	}
+
+	now := time.Now()
+	return fiber.Map{
+		"Goal": model.GoalProgress{
+			ReadingGoal: model.ReadingGoal{Period: model.GoalPeriodYear, Unit: model.GoalUnitDocuments, Target: 24},
+			Start:       now.AddDate(0, -6, 0),
+			End:         now.AddDate(0, 6, 0),
+			Progress:    8,
+			Expected:    12,
+		},
+	}, "You are falling behind your reading goal", nil
+}
+
+// sampleCover returns a plain image standing in for a document cover
+func sampleCover() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 300))
+	for x := range 200 {
+		for y := range 300 {
+			img.Set(x, y, color.RGBA{R: 108, G: 117, B: 125, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}
@@ -0,0 +1,26 @@
+package email
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Retry queues again a failed delivery
+func (e *Controller) Retry(c fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	retried, err := e.emailRepository.Retry(uint(id))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if !retried {
+		return fiber.ErrNotFound
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
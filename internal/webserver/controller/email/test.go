@@ -0,0 +1,42 @@
+package email
+
+import (
+	"log"
+	"net/mail"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
+
+// SendTest queues a test email to the passed address, so admins can check the configured mail transport works
+func (e *Controller) SendTest(c fiber.Ctx) error {
+	if _, ok := e.sender.(*infrastructure.NoEmail); ok {
+		return fiber.ErrNotFound
+	}
+
+	address := c.FormValue("email")
+	if _, err := mail.ParseAddress(address); err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	lang := c.Locals("Lang").(string)
+	message, err := mailer.NewComposer(c.App().Config().Views, e.translator).Compose("test", lang, fiber.Map{}, "Coreander test email")
+	if err != nil {
+		log.Printf("error rendering test email: %s\n", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := e.sender.Send(address, message); err != nil {
+		log.Printf("error sending test email to %s: %s\n", address, err)
+		return fiber.ErrInternalServerError
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:    "success-once",
+		Value:   e.translator.T(lang, "Test email queued for %s. You can follow its delivery below.", address),
+		Expires: time.Now().Add(24 * time.Hour),
+	})
+	return c.Redirect().To("/emails")
+}
@@ -0,0 +1,51 @@
+package goal
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type goalsRepository interface {
+	Progress(userID uint, now time.Time) ([]model.GoalProgress, error)
+	Save(goal *model.ReadingGoal) error
+	Delete(userID, goalID uint) error
+}
+
+type Controller struct {
+	usersRepository usersRepository
+	goalsRepository goalsRepository
+}
+
+// NewController returns a new instance of the reading goals controller
+func NewController(usersRepository usersRepository, goalsRepository goalsRepository) *Controller {
+	return &Controller{
+		usersRepository: usersRepository,
+		goalsRepository: goalsRepository,
+	}
+}
+
+// owner returns the user whose username is in the URL, as long as it is the one making the request,
+// as goals are personal and not even admins can manage them on behalf of other users
+func (g *Controller) owner(c fiber.Ctx) (*model.User, error) {
+	session, ok := c.Locals("Session").(model.Session)
+	if !ok || session.Username != c.Params("username") {
+		return nil, fiber.ErrForbidden
+	}
+
+	user, err := g.usersRepository.FindByUsername(c.Params("username"))
+	if err != nil {
+		log.Println(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return nil, fiber.ErrNotFound
+	}
+	return user, nil
+}
@@ -0,0 +1,26 @@
+package goal
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Delete removes a reading goal from a user and renders the updated list
+func (g *Controller) Delete(c fiber.Ctx) error {
+	user, err := g.owner(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err = g.goalsRepository.Delete(user.ID, uint(id)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return g.renderList(c, user, map[string]string{})
+}
@@ -0,0 +1,33 @@
+package goal
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// List renders the reading goals of a user along with their progress
+func (g *Controller) List(c fiber.Ctx) error {
+	user, err := g.owner(c)
+	if err != nil {
+		return err
+	}
+
+	return g.renderList(c, user, map[string]string{})
+}
+
+func (g *Controller) renderList(c fiber.Ctx, user *model.User, errs map[string]string) error {
+	goals, err := g.goalsRepository.Progress(user.ID, time.Now())
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/goals-list", fiber.Map{
+		"User":    user,
+		"Goals":   goals,
+		"Periods": model.GoalPeriods,
+		"Units":   model.GoalUnits,
+		"Errors":  errs,
+	})
+}
@@ -0,0 +1,38 @@
+package goal
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Save sets the user's reading goal for the period chosen in the form, replacing the existing one
+// for that period if any, and renders the updated list
+func (g *Controller) Save(c fiber.Ctx) error {
+	user, err := g.owner(c)
+	if err != nil {
+		return err
+	}
+
+	target, _ := strconv.Atoi(c.FormValue("target"))
+	goal := model.ReadingGoal{
+		UserID: user.ID,
+		Period: c.FormValue("period"),
+		Unit:   c.FormValue("unit"),
+		Target: target,
+		// Reminders can only be enabled if email sending is configured
+		Reminders: c.FormValue("reminders") == "on" && c.Locals("EmailSendingConfigured") == true,
+	}
+
+	if errs := goal.Validate(); len(errs) > 0 {
+		c.Status(fiber.StatusBadRequest)
+		return g.renderList(c, user, errs)
+	}
+
+	if err := g.goalsRepository.Save(&goal); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return g.renderList(c, user, map[string]string{})
+}
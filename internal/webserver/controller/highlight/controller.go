@@ -31,27 +31,33 @@ type usersRepository interface {
 	FindByUsername(username string) (*model.User, error)
 }
 
+type activityRepository interface {
+	Record(userID int, activityType, documentSlug string)
+}
+
 type Sender interface {
 	SendDocument(address, subject string, file []byte, fileName string) error
 	From() string
 }
 
 type Controller struct {
-	hlRepository      highlightsRepository
-	readingRepository readingRepository
-	usrRepository     usersRepository
-	idx               IdxReaderWriter
-	sender            Sender
-	wordsPerMinute    float64
+	hlRepository       highlightsRepository
+	readingRepository  readingRepository
+	usrRepository      usersRepository
+	activityRepository activityRepository
+	idx                IdxReaderWriter
+	sender             Sender
+	wordsPerMinute     float64
 }
 
-func NewController(hlRepository highlightsRepository, readingRepository readingRepository, usrRepository usersRepository, sender Sender, wordsPerMinute float64, idx IdxReaderWriter) *Controller {
+func NewController(hlRepository highlightsRepository, readingRepository readingRepository, usrRepository usersRepository, activityRepository activityRepository, sender Sender, wordsPerMinute float64, idx IdxReaderWriter) *Controller {
 	return &Controller{
-		hlRepository:      hlRepository,
-		readingRepository: readingRepository,
-		usrRepository:     usrRepository,
-		idx:               idx,
-		sender:            sender,
-		wordsPerMinute:    wordsPerMinute,
+		hlRepository:       hlRepository,
+		readingRepository:  readingRepository,
+		usrRepository:      usrRepository,
+		activityRepository: activityRepository,
+		idx:                idx,
+		sender:             sender,
+		wordsPerMinute:     wordsPerMinute,
 	}
 }
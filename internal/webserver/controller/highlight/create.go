@@ -19,6 +19,7 @@ func (h *Controller) Create(c fiber.Ctx) error {
 		log.Println(err)
 		return fiber.ErrInternalServerError
 	}
+	h.activityRepository.Record(int(user.ID), model.ActivityHighlighted, document.Slug)
 
 	c.Response().Header.Set("HX-Trigger", "highlight")
 	return nil
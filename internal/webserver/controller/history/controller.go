@@ -0,0 +1,22 @@
+package history
+
+import (
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type readingRepository interface {
+	History(userID int, page int, resultsPerPage int) (result.Paginated[[]model.HistoryEntry], error)
+	Rate(userID int, readThroughID uint, rating int) error
+}
+
+type Controller struct {
+	readingRepository readingRepository
+}
+
+// NewController returns a new instance of the reading history controller
+func NewController(readingRepository readingRepository) *Controller {
+	return &Controller{
+		readingRepository: readingRepository,
+	}
+}
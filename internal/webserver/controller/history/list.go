@@ -0,0 +1,38 @@
+package history
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// List renders the timeline of documents read by the logged in user, including re-reads
+func (h *Controller) List(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	results, err := h.readingRepository.History(int(session.ID), page, model.ResultsPerPage)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	ratings := make([]int, model.MaxRating)
+	for i := range ratings {
+		ratings[i] = i + 1
+	}
+
+	return c.Render("history/index", fiber.Map{
+		"Title":     "Reading history",
+		"Results":   results,
+		"Paginator": view.Pagination(model.MaxPagesNavigator, results, c.Queries()),
+		"Ratings":   ratings,
+	}, "layout")
+}
@@ -0,0 +1,32 @@
+package history
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Rate sets the rating the logged in user gives to one of their read-throughs
+func (h *Controller) Rate(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	readThrough := model.ReadThrough{ID: uint(id)}
+	if readThrough.Rating, err = strconv.Atoi(c.FormValue("rating")); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if errs := readThrough.Validate(); len(errs) > 0 {
+		return fiber.ErrBadRequest
+	}
+
+	if err := h.readingRepository.Rate(int(session.ID), readThrough.ID, readThrough.Rating); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,6 +1,8 @@
 package home
 
 import (
+	"time"
+
 	"github.com/svera/coreander/v4/internal/index"
 	"github.com/svera/coreander/v4/internal/result"
 	"github.com/svera/coreander/v4/internal/webserver/model"
@@ -27,6 +29,10 @@ type readingRepository interface {
 	Latest(userID int, page int, resultsPerPage int) (result.Paginated[[]model.AugmentedDocument], error)
 }
 
+type goalsRepository interface {
+	Progress(userID uint, now time.Time) ([]model.GoalProgress, error)
+}
+
 type Config struct {
 	LibraryPath     string
 	CoverMaxWidth   int
@@ -36,15 +42,17 @@ type Config struct {
 type Controller struct {
 	hlRepository      highlightsRepository
 	readingRepository readingRepository
+	goalsRepository   goalsRepository
 	idx               IdxReaderWriter
 	sender            Sender
 	config            Config
 }
 
-func NewController(hlRepository highlightsRepository, readingRepository readingRepository, sender Sender, idx IdxReaderWriter, cfg Config) *Controller {
+func NewController(hlRepository highlightsRepository, readingRepository readingRepository, goalsRepository goalsRepository, sender Sender, idx IdxReaderWriter, cfg Config) *Controller {
 	return &Controller{
 		hlRepository:      hlRepository,
 		readingRepository: readingRepository,
+		goalsRepository:   goalsRepository,
 		idx:               idx,
 		sender:            sender,
 		config:            cfg,
@@ -2,6 +2,7 @@ package home
 
 import (
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/webserver/model"
@@ -30,7 +31,10 @@ func (d *Controller) Index(c fiber.Ctx) error {
 		latestDocs = append(latestDocs, model.AugmentedDocument{Document: doc})
 	}
 
-	var readingDocs []model.AugmentedDocument
+	var (
+		readingDocs []model.AugmentedDocument
+		goals       []model.GoalProgress
+	)
 	if session.ID > 0 {
 		for i := range latestDocs {
 			result := model.AugmentedDocument{Document: latestDocs[i].Document}
@@ -43,11 +47,16 @@ func (d *Controller) Index(c fiber.Ctx) error {
 			log.Println(err)
 			return fiber.ErrInternalServerError
 		}
+
+		if goals, err = d.goalsRepository.Progress(session.ID, time.Now()); err != nil {
+			return fiber.ErrInternalServerError
+		}
 	}
 
 	return c.Render("index", fiber.Map{
 		"Count":      totalDocumentsCount,
 		"EmailFrom":  d.sender.From(),
+		"Goals":      goals,
 		"HomeNavbar": true,
 		"LatestDocs": latestDocs,
 		"Reading":    readingDocs,
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type notificationsRepository interface {
+	Notifications(userID int, page int, resultsPerPage int) (result.Paginated[[]model.Notification], error)
+	Get(userID int, notificationID uint) (*model.Notification, error)
+	Unread(userID int) int64
+	MarkRead(userID int, notificationID uint) error
+	MarkAllRead(userID int) error
+	EmailPreferences(userID int) (map[string]bool, error)
+	SaveEmailPreferences(userID int, email map[string]bool) error
+}
+
+type Controller struct {
+	notificationsRepository notificationsRepository
+}
+
+// NewController returns a new instance of the notifications controller
+func NewController(notificationsRepository notificationsRepository) *Controller {
+	return &Controller{
+		notificationsRepository: notificationsRepository,
+	}
+}
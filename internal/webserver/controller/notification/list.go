@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"log"
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// List renders the notifications of the logged in user, along with their email preferences
+func (n *Controller) List(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	results, err := n.notificationsRepository.Notifications(int(session.ID), page, model.ResultsPerPage)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	preferences, err := n.notificationsRepository.EmailPreferences(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	types := model.NotificationTypes
+	if session.Role != model.RoleAdmin {
+		types = slices.DeleteFunc(slices.Clone(types), func(notificationType string) bool {
+			return notificationType == model.NotificationNewVersion
+		})
+	}
+
+	if err = c.Render("notification/index", fiber.Map{
+		"Title":             "Notifications",
+		"Results":           results,
+		"Paginator":         view.Pagination(model.MaxPagesNavigator, results, c.Queries()),
+		"Unread":            n.notificationsRepository.Unread(int(session.ID)),
+		"NotificationTypes": types,
+		"EmailPreferences":  preferences,
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// Unread renders the badge with the number of unread notifications shown in the navigation bar
+func (n *Controller) Unread(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	return c.Render("partials/notifications-badge", fiber.Map{
+		"Unread": n.notificationsRepository.Unread(int(session.ID)),
+	})
+}
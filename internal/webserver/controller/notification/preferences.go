@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// UpdatePreferences stores which kinds of notifications the logged in user wants to receive by email
+func (n *Controller) UpdatePreferences(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	email := make(map[string]bool, len(model.NotificationTypes))
+	for _, notificationType := range model.NotificationTypes {
+		email[notificationType] = c.FormValue("email-"+notificationType) == "on"
+	}
+
+	if err := n.notificationsRepository.SaveEmailPreferences(int(session.ID), email); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Open marks a notification as read and takes the user to what it is about
+func (n *Controller) Open(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	notificationID, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	notification, err := n.notificationsRepository.Get(int(session.ID), uint(notificationID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if notification == nil {
+		return fiber.ErrNotFound
+	}
+
+	if err := n.notificationsRepository.MarkRead(int(session.ID), notification.ID); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Redirect().To(notification.Link)
+}
+
+// Read marks a notification of the logged in user as read
+func (n *Controller) Read(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	notificationID, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	if err := n.notificationsRepository.MarkRead(int(session.ID), uint(notificationID)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ReadAll marks all notifications of the logged in user as read
+func (n *Controller) ReadAll(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	if err := n.notificationsRepository.MarkAllRead(int(session.ID)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package passkey
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	ceremonyCookie  = "webauthn-ceremony"
+	ceremonyTimeout = 5 * time.Minute
+)
+
+// storeCeremony keeps the WebAuthn session data between the begin and finish steps
+// of a ceremony in a short lived signed cookie, so no server side state is needed
+func (p *Controller) storeCeremony(c fiber.Ctx, kind string, session *webauthn.SessionData) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"kind":    kind,
+		"session": string(data),
+		"exp":     jwt.NewNumericDate(time.Now().Add(ceremonyTimeout)),
+	})
+	signedToken, err := token.SignedString(p.config.Secret)
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     ceremonyCookie,
+		Value:    signedToken,
+		Path:     "/",
+		MaxAge:   int(ceremonyTimeout.Seconds()),
+		Secure:   false,
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteStrictMode,
+	})
+	return nil
+}
+
+// loadCeremony retrieves the WebAuthn session data stored by storeCeremony and removes the cookie,
+// so every challenge can only be used once
+func (p *Controller) loadCeremony(c fiber.Ctx, kind string) (webauthn.SessionData, error) {
+	var session webauthn.SessionData
+
+	value := c.Cookies(ceremonyCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:    ceremonyCookie,
+		Path:    "/",
+		Expires: time.Now().Add(-(time.Hour * 2)),
+	})
+
+	token, err := jwt.Parse(value, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return p.config.Secret, nil
+	})
+	if err != nil {
+		return session, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["kind"] != kind {
+		return session, errors.New("wrong ceremony")
+	}
+	data, ok := claims["session"].(string)
+	if !ok {
+		return session, errors.New("missing ceremony session data")
+	}
+	err = json.Unmarshal([]byte(data), &session)
+	return session, err
+}
@@ -0,0 +1,68 @@
+package passkey
+
+import (
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type usersRepository interface {
+	FindByUuid(uuid string) (*model.User, error)
+	FindByUsername(username string) (*model.User, error)
+}
+
+type passkeysRepository interface {
+	ByUser(userID uint) ([]model.Passkey, error)
+	FindByCredentialID(credentialID []byte) (*model.Passkey, error)
+	Create(passkey *model.Passkey) error
+	Update(passkey *model.Passkey) error
+	Delete(userID, passkeyID uint) error
+}
+
+type Config struct {
+	Secret         []byte
+	SessionTimeout time.Duration
+}
+
+type Controller struct {
+	usersRepository    usersRepository
+	passkeysRepository passkeysRepository
+	webAuthn           *webauthn.WebAuthn
+	config             Config
+}
+
+// NewController returns a new instance of the passkeys controller
+func NewController(usersRepository usersRepository, passkeysRepository passkeysRepository, webAuthn *webauthn.WebAuthn, cfg Config) *Controller {
+	return &Controller{
+		usersRepository:    usersRepository,
+		passkeysRepository: passkeysRepository,
+		webAuthn:           webAuthn,
+		config:             cfg,
+	}
+}
+
+// owner returns the user whose username is in the URL, as long as it is the one
+// making the request, or an admin if allowAdmin is true
+func (p *Controller) owner(c fiber.Ctx, allowAdmin bool) (*model.User, error) {
+	session, ok := c.Locals("Session").(model.Session)
+	if !ok {
+		return nil, fiber.ErrForbidden
+	}
+
+	if session.Username != c.Params("username") && (!allowAdmin || session.Role != model.RoleAdmin) {
+		return nil, fiber.ErrForbidden
+	}
+
+	user, err := p.usersRepository.FindByUsername(c.Params("username"))
+	if err != nil {
+		log.Println(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return nil, fiber.ErrNotFound
+	}
+	return user, nil
+}
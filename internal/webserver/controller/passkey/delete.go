@@ -0,0 +1,26 @@
+package passkey
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Delete removes a passkey from a user and renders the updated list
+func (p *Controller) Delete(c fiber.Ctx) error {
+	user, err := p.owner(c, true)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err = p.passkeysRepository.Delete(user.ID, uint(id)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return p.renderList(c, user)
+}
@@ -0,0 +1,28 @@
+package passkey
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// List renders the passkeys registered by a user
+func (p *Controller) List(c fiber.Ctx) error {
+	user, err := p.owner(c, true)
+	if err != nil {
+		return err
+	}
+
+	return p.renderList(c, user)
+}
+
+func (p *Controller) renderList(c fiber.Ctx, user *model.User) error {
+	passkeys, err := p.passkeysRepository.ByUser(user.ID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/passkeys-list", fiber.Map{
+		"User":     user,
+		"Passkeys": passkeys,
+	})
+}
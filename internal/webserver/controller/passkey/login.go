@@ -0,0 +1,91 @@
+package passkey
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/controller/auth"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// LoginOptions starts a passkey login ceremony, returning the options to be passed
+// to navigator.credentials.get() in the browser
+func (p *Controller) LoginOptions(c fiber.Ctx) error {
+	assertion, session, err := p.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err = p.storeCeremony(c, "login", session); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(assertion)
+}
+
+// Login finishes a passkey login ceremony and starts a session for the user the passkey belongs to
+func (p *Controller) Login(c fiber.Ctx) error {
+	session, err := p.loadCeremony(c, "login")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := protocol.ParseCredentialRequestResponseBytes(c.Body())
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	var user *model.User
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		var err error
+		if user, err = p.usersRepository.FindByUuid(string(userHandle)); err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors.New("passkey owner not found")
+		}
+		return p.webAuthnUser(user)
+	}
+
+	credential, err := p.webAuthn.ValidateDiscoverableLogin(findUser, session, response)
+	if err != nil {
+		log.Printf("passkey login failed: %s\n", err)
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		log.Printf("passkey login rejected for user %s, sign counter suggests a cloned authenticator\n", user.Username)
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	if err = p.recordUse(credential); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err = auth.SetSessionCookie(c, user, p.config.SessionTimeout, p.config.Secret); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(fiber.Map{"redirect": auth.RedirectAfterSignIn(c)})
+}
+
+// recordUse stores the updated sign counter of the credential and when it was last used
+func (p *Controller) recordUse(credential *webauthn.Credential) error {
+	passkey, err := p.passkeysRepository.FindByCredentialID(credential.ID)
+	if err != nil || passkey == nil {
+		return errors.Join(err, errors.New("used passkey not found"))
+	}
+
+	if passkey.Credential, err = json.Marshal(credential); err != nil {
+		return err
+	}
+	passkey.LastUsedAt = time.Now().UTC()
+	return p.passkeysRepository.Update(passkey)
+}
@@ -0,0 +1,100 @@
+package passkey
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+const nameMaxLength = 50
+
+// RegistrationOptions starts a passkey registration ceremony, returning the options to be passed
+// to navigator.credentials.create() in the browser. Users can only register passkeys for themselves.
+func (p *Controller) RegistrationOptions(c fiber.Ctx) error {
+	user, err := p.owner(c, false)
+	if err != nil {
+		return err
+	}
+
+	webAuthnUser, err := p.webAuthnUser(user)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	creation, session, err := p.webAuthn.BeginRegistration(
+		webAuthnUser,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(webauthn.Credentials(webAuthnUser.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err = p.storeCeremony(c, "registration", session); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(creation)
+}
+
+// Register finishes a passkey registration ceremony, storing the new credential
+func (p *Controller) Register(c fiber.Ctx) error {
+	user, err := p.owner(c, false)
+	if err != nil {
+		return err
+	}
+
+	session, err := p.loadCeremony(c, "registration")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := protocol.ParseCredentialCreationResponseBytes(c.Body())
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	webAuthnUser, err := p.webAuthnUser(user)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	credential, err := p.webAuthn.CreateCredential(webAuthnUser, session, response)
+	if err != nil {
+		log.Printf("passkey registration failed: %s\n", err)
+		return fiber.ErrBadRequest
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" {
+		name = "Passkey"
+	}
+	if utf8.RuneCountInString(name) > nameMaxLength {
+		name = string([]rune(name)[:nameMaxLength])
+	}
+
+	passkey := &model.Passkey{
+		UserID:       user.ID,
+		Name:         name,
+		CredentialID: credential.ID,
+		Credential:   data,
+	}
+	if err = p.passkeysRepository.Create(passkey); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.SendStatus(fiber.StatusCreated)
+}
@@ -0,0 +1,50 @@
+package passkey
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// webAuthnUser adapts a user and its passkeys to the interface required by the WebAuthn library
+type webAuthnUser struct {
+	user     *model.User
+	passkeys []model.Passkey
+}
+
+// WebAuthnID returns the user handle stored in the authenticator, for which the user's UUID
+// is used as it does not contain personal information and never changes
+func (u webAuthnUser) WebAuthnID() []byte {
+	return []byte(u.user.Uuid)
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Name
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(passkey.Credential, &credential); err != nil {
+			log.Printf("error decoding passkey %d: %s\n", passkey.ID, err)
+			continue
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
+
+func (p *Controller) webAuthnUser(user *model.User) (webAuthnUser, error) {
+	passkeys, err := p.passkeysRepository.ByUser(user.ID)
+	if err != nil {
+		return webAuthnUser{}, err
+	}
+	return webAuthnUser{user: user, passkeys: passkeys}, nil
+}
@@ -0,0 +1,41 @@
+package queue
+
+import (
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// seriesLookup is the maximum number of documents of the same series checked when looking for the next one to read
+const seriesLookup = 100
+
+type queueRepository interface {
+	Add(userID int, documentSlug string) error
+	Remove(userID int, documentSlug string) error
+	Move(userID int, documentSlug string, position int) error
+	Documents(userID int) ([]index.Document, error)
+}
+
+type readingRepository interface {
+	CompletedOn(userID int, documentSlug string) (*time.Time, error)
+}
+
+type idxReader interface {
+	Document(slug string) (index.Document, error)
+	SameSeries(slug string, quantity int) ([]index.Document, error)
+}
+
+type Controller struct {
+	queueRepository   queueRepository
+	readingRepository readingRepository
+	idx               idxReader
+}
+
+// NewController returns a new instance of the reading queue controller
+func NewController(queueRepository queueRepository, readingRepository readingRepository, idx idxReader) *Controller {
+	return &Controller{
+		queueRepository:   queueRepository,
+		readingRepository: readingRepository,
+		idx:               idx,
+	}
+}
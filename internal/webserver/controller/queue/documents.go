@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Add places a document at the end of the user's reading queue
+func (q *Controller) Add(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	document, err := q.idx.Document(c.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	if err := q.queueRepository.Add(int(session.ID), document.Slug); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "queue-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Remove takes a document out of the user's reading queue
+func (q *Controller) Remove(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	if err := q.queueRepository.Remove(int(session.ID), c.Params("slug")); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "queue-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Move changes the priority of a document in the user's reading queue
+func (q *Controller) Move(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	position, err := strconv.Atoi(c.FormValue("position"))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := q.queueRepository.Move(int(session.ID), c.Params("slug"), position); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "queue-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// entry is a queued document, along with the positions it takes when moved up or down
+type entry struct {
+	index.Document
+	Up   int
+	Down int
+}
+
+// List renders the documents the user wants to read, by priority
+func (q *Controller) List(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	documents, err := q.queueRepository.Documents(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	entries := make([]entry, len(documents))
+	for i, doc := range documents {
+		entries[i] = entry{Document: doc, Up: i - 1, Down: i + 1}
+	}
+
+	if err = c.Render("queue/index", fiber.Map{
+		"Title":     "Reading queue",
+		"Documents": entries,
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
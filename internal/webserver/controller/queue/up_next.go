@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// UpNext returns the document the user should read after the passed one: the first one in their reading queue or,
+// if the queue is empty, the next uncompleted one in the same series. Nothing is returned if there is none.
+func (q *Controller) UpNext(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	document, err := q.idx.Document(c.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	next, err := q.upNext(int(session.ID), document)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	if next == nil {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	return c.JSON(fiber.Map{
+		"slug":    next.Slug,
+		"title":   next.Title,
+		"authors": next.Authors,
+		"series":  next.Series,
+		"reason":  next.Reason,
+	})
+}
+
+func (q *Controller) upNext(userID int, document index.Document) (*model.UpNext, error) {
+	queued, err := q.queueRepository.Documents(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range queued {
+		if doc.Slug != document.Slug {
+			return &model.UpNext{Document: doc, Reason: model.UpNextFromQueue}, nil
+		}
+	}
+
+	sameSeries, err := q.idx.SameSeries(document.Slug, seriesLookup)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var next *model.UpNext
+	for _, doc := range sameSeries {
+		if doc.SeriesIndex <= document.SeriesIndex || (next != nil && doc.SeriesIndex >= next.SeriesIndex) {
+			continue
+		}
+		completedOn, err := q.readingRepository.CompletedOn(userID, doc.Slug)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if completedOn == nil {
+			next = &model.UpNext{Document: doc, Reason: model.UpNextFromSeries}
+		}
+	}
+	return next, nil
+}
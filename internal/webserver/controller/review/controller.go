@@ -0,0 +1,36 @@
+package review
+
+import (
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type reviewsRepository interface {
+	Save(review *model.Review) error
+	Delete(userID int, documentSlug string) error
+	Reviews(userID int, page int, resultsPerPage int) (result.Paginated[[]model.ReviewEntry], error)
+}
+
+type activityRepository interface {
+	Record(userID int, activityType, documentSlug string)
+}
+
+type idxReader interface {
+	Document(slug string) (index.Document, error)
+}
+
+type Controller struct {
+	reviewsRepository  reviewsRepository
+	activityRepository activityRepository
+	idx                idxReader
+}
+
+// NewController returns a new instance of the reviews controller
+func NewController(reviewsRepository reviewsRepository, activityRepository activityRepository, idx idxReader) *Controller {
+	return &Controller{
+		reviewsRepository:  reviewsRepository,
+		activityRepository: activityRepository,
+		idx:                idx,
+	}
+}
@@ -0,0 +1,18 @@
+package review
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Delete removes the logged in user's review of a document
+func (r *Controller) Delete(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	if err := r.reviewsRepository.Delete(int(session.ID), c.Params("slug")); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
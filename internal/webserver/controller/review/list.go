@@ -0,0 +1,38 @@
+package review
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"github.com/svera/coreander/v4/internal/webserver/view"
+)
+
+// List renders the reviews written by the logged in user
+func (r *Controller) List(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil {
+		page = 1
+	}
+
+	results, err := r.reviewsRepository.Reviews(int(session.ID), page, model.ResultsPerPage)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+
+	ratings := make([]int, model.MaxRating)
+	for i := range ratings {
+		ratings[i] = i + 1
+	}
+
+	return c.Render("review/index", fiber.Map{
+		"Title":     "My reviews",
+		"Results":   results,
+		"Paginator": view.Pagination(model.MaxPagesNavigator, results, c.Queries()),
+		"Ratings":   ratings,
+	}, "layout")
+}
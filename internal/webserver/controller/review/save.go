@@ -0,0 +1,44 @@
+package review
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Save creates or updates the logged in user's review of a document
+func (r *Controller) Save(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	document, err := r.idx.Document(c.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	review := model.Review{
+		UserID: int(session.ID),
+		Slug:   document.Slug,
+		Text:   strings.TrimSpace(c.FormValue("text")),
+	}
+	if review.Rating, err = strconv.Atoi(c.FormValue("rating")); err != nil {
+		return fiber.ErrBadRequest
+	}
+	if errs := review.Validate(); len(errs) > 0 {
+		return fiber.ErrBadRequest
+	}
+
+	if err := r.reviewsRepository.Save(&review); err != nil {
+		return fiber.ErrInternalServerError
+	}
+	r.activityRepository.Record(int(session.ID), model.ActivityReviewed, document.Slug)
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -25,26 +25,34 @@ type readingRepository interface {
 	CompletedPaginatedResult(userID int, results result.Paginated[[]model.AugmentedDocument]) result.Paginated[[]model.AugmentedDocument]
 }
 
+type notificationsRepository interface {
+	FollowSeries(userID int, series string) error
+	UnfollowSeries(userID int, series string) error
+	FollowsSeries(userID int, series string) bool
+}
+
 type Config struct {
 	WordsPerMinute float64
 }
 
 type Controller struct {
-	hlRepository      highlightsRepository
-	readingRepository readingRepository
-	idx               IdxReader
-	sender            Sender
-	config            Config
-	appFs             afero.Fs
+	hlRepository            highlightsRepository
+	readingRepository       readingRepository
+	notificationsRepository notificationsRepository
+	idx                     IdxReader
+	sender                  Sender
+	config                  Config
+	appFs                   afero.Fs
 }
 
-func NewController(hlRepository highlightsRepository, readingRepository readingRepository, sender Sender, idx IdxReader, cfg Config, appFs afero.Fs) *Controller {
+func NewController(hlRepository highlightsRepository, readingRepository readingRepository, notificationsRepository notificationsRepository, sender Sender, idx IdxReader, cfg Config, appFs afero.Fs) *Controller {
 	return &Controller{
-		hlRepository:      hlRepository,
-		readingRepository: readingRepository,
-		idx:               idx,
-		sender:            sender,
-		config:            cfg,
-		appFs:             appFs,
+		hlRepository:            hlRepository,
+		readingRepository:       readingRepository,
+		notificationsRepository: notificationsRepository,
+		idx:                     idx,
+		sender:                  sender,
+		config:                  cfg,
+		appFs:                   appFs,
 	}
 }
@@ -59,6 +59,8 @@ func (a *Controller) Documents(c fiber.Ctx) error {
 		"Results":        searchResults,
 		"Paginator":      view.Pagination(model.MaxPagesNavigator, searchResults, c.Queries()),
 		"Title":          title,
+		"SeriesSlug":     seriesSlug,
+		"FollowsSeries":  session.ID > 0 && a.notificationsRepository.FollowsSeries(int(session.ID), seriesSlug),
 		"EmailFrom":      a.sender.From(),
 		"WordsPerMinute": a.config.WordsPerMinute,
 		"URL":            view.URL(c),
@@ -0,0 +1,42 @@
+package series
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Follow makes the logged in user be notified when new documents of a series are added to the library
+func (a *Controller) Follow(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	documents, err := a.idx.SearchBySeries(index.SearchFields{Keywords: c.Params("slug")}, 1, 1)
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if documents.TotalHits() == 0 {
+		return fiber.ErrNotFound
+	}
+
+	if err := a.notificationsRepository.FollowSeries(int(session.ID), c.Params("slug")); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Unfollow stops notifying the logged in user about new documents of a series
+func (a *Controller) Unfollow(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	if err := a.notificationsRepository.UnfollowSeries(int(session.ID), c.Params("slug")); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,84 @@
+package shelf
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type shelvesRepository interface {
+	Get(shelfID uint) (*model.Shelf, error)
+	Owned(userID int) ([]model.Shelf, error)
+	SharedWith(userID int) ([]model.Shelf, error)
+	PublicShelves(exceptUserID int) ([]model.Shelf, error)
+	Choices(userID int, documentSlug string) ([]model.ShelfChoice, error)
+	Save(shelf *model.Shelf) error
+	Delete(userID int, shelfID uint) error
+	AddToShelf(shelfID uint, documentSlug string) error
+	RemoveFromShelf(shelfID uint, documentSlug string) error
+	Move(shelfID uint, documentSlug string, position int) error
+	Documents(shelfID uint) ([]index.Document, error)
+	Share(shelfID uint, userID int) error
+	Unshare(shelfID uint, userID int) error
+}
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type idxReader interface {
+	Document(slug string) (index.Document, error)
+}
+
+type Controller struct {
+	shelvesRepository shelvesRepository
+	usersRepository   usersRepository
+	idx               idxReader
+}
+
+// NewController returns a new instance of the shelves controller
+func NewController(shelvesRepository shelvesRepository, usersRepository usersRepository, idx idxReader) *Controller {
+	return &Controller{
+		shelvesRepository: shelvesRepository,
+		usersRepository:   usersRepository,
+		idx:               idx,
+	}
+}
+
+// shelf returns the shelf whose ID is in the URL, as long as the user making the request can see it
+func (s *Controller) shelf(c fiber.Ctx) (*model.Shelf, error) {
+	session, _ := c.Locals("Session").(model.Session)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 0)
+	if err != nil {
+		return nil, fiber.ErrNotFound
+	}
+
+	shelf, err := s.shelvesRepository.Get(uint(id))
+	if err != nil {
+		log.Println(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if shelf == nil || !shelf.CanView(int(session.ID)) {
+		return nil, fiber.ErrNotFound
+	}
+	return shelf, nil
+}
+
+// owned returns the shelf whose ID is in the URL, as long as it belongs to the user making the request,
+// as only owners can modify their shelves
+func (s *Controller) owned(c fiber.Ctx) (*model.Shelf, error) {
+	session, _ := c.Locals("Session").(model.Session)
+
+	shelf, err := s.shelf(c)
+	if err != nil {
+		return nil, err
+	}
+	if shelf.UserID != int(session.ID) {
+		return nil, fiber.ErrForbidden
+	}
+	return shelf, nil
+}
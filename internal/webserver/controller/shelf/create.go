@@ -0,0 +1,33 @@
+package shelf
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Create adds a new, private shelf for the logged in user and renders the updated list
+func (s *Controller) Create(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	shelf := model.Shelf{
+		UserID: int(session.ID),
+		Name:   strings.TrimSpace(c.FormValue("name")),
+	}
+
+	errs := shelf.Validate()
+	if len(errs) == 0 {
+		if err := s.shelvesRepository.Save(&shelf); errors.Is(err, model.ErrShelfNameTaken) {
+			errs["name"] = "A shelf with this name already exists"
+		} else if err != nil {
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if len(errs) > 0 {
+		c.Status(fiber.StatusBadRequest)
+	}
+	return s.renderList(c, int(session.ID), errs)
+}
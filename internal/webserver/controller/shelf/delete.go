@@ -0,0 +1,23 @@
+package shelf
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Delete removes a shelf of the logged in user. The documents in it are not affected.
+func (s *Controller) Delete(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.shelvesRepository.Delete(int(session.ID), shelf.ID); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Redirect", "/shelves")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package shelf
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Choices renders the shelves of the logged in user, so the passed document can be added to or removed from them
+func (s *Controller) Choices(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	choices, err := s.shelvesRepository.Choices(int(session.ID), c.Params("slug"))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/shelf-choices", fiber.Map{
+		"Slug":    c.Params("slug"),
+		"Choices": choices,
+	})
+}
+
+// AddDocument places a document at the end of a shelf
+func (s *Controller) AddDocument(c fiber.Ctx) error {
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := s.idx.Document(c.Params("slug"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if document.Slug == "" {
+		return fiber.ErrNotFound
+	}
+
+	if err := s.shelvesRepository.AddToShelf(shelf.ID, document.Slug); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "shelf-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RemoveDocument takes a document out of a shelf
+func (s *Controller) RemoveDocument(c fiber.Ctx) error {
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.shelvesRepository.RemoveFromShelf(shelf.ID, c.Params("slug")); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "shelf-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MoveDocument changes the position of a document inside a shelf
+func (s *Controller) MoveDocument(c fiber.Ctx) error {
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	position, err := strconv.Atoi(c.FormValue("position"))
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := s.shelvesRepository.Move(shelf.ID, c.Params("slug"), position); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Response().Header.Set("HX-Trigger", "shelf-updated")
+	return c.SendStatus(fiber.StatusNoContent)
+}
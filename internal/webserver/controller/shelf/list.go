@@ -0,0 +1,52 @@
+package shelf
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// List renders the shelves of the logged in user, along with the ones shared with them and the public ones
+func (s *Controller) List(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	owned, err := s.shelvesRepository.Owned(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	shared, err := s.shelvesRepository.SharedWith(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	public, err := s.shelvesRepository.PublicShelves(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	if err = c.Render("shelf/index", fiber.Map{
+		"Title":         "Shelves",
+		"Shelves":       owned,
+		"SharedShelves": shared,
+		"PublicShelves": public,
+		"Errors":        map[string]string{},
+		"NameMaxLength": model.ShelfNameMaxLength,
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+func (s *Controller) renderList(c fiber.Ctx, userID int, errs map[string]string) error {
+	owned, err := s.shelvesRepository.Owned(userID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("partials/shelves-list", fiber.Map{
+		"Shelves":       owned,
+		"Errors":        errs,
+		"NameMaxLength": model.ShelfNameMaxLength,
+	})
+}
@@ -0,0 +1,60 @@
+package shelf
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Share lets the user whose username is passed in the form see a shelf.
+// As with documents, users with a private profile can neither share shelves nor have shelves shared with them.
+func (s *Controller) Share(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+	if session.PrivateProfile != 0 {
+		return fiber.ErrForbidden
+	}
+
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.usersRepository.FindByUsername(strings.TrimSpace(c.FormValue("username")))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil || user.PrivateProfile != 0 || int(user.ID) == shelf.UserID {
+		return fiber.ErrBadRequest
+	}
+
+	if err := s.shelvesRepository.Share(shelf.ID, int(user.ID)); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Unshare stops sharing a shelf with a user
+func (s *Controller) Unshare(c fiber.Ctx) error {
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	userID, err := strconv.Atoi(c.Params("userID"))
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	if err := s.shelvesRepository.Unshare(shelf.ID, userID); err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package shelf
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Options renders the shelves the logged in user can filter search results by
+func (s *Controller) Options(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	owned, err := s.shelvesRepository.Owned(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	shared, err := s.shelvesRepository.SharedWith(int(session.ID))
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	selected, _ := strconv.ParseUint(c.Query("selected"), 10, 0)
+	return c.Render("partials/shelf-options", fiber.Map{
+		"Shelves":  append(owned, shared...),
+		"Selected": uint(selected),
+	})
+}
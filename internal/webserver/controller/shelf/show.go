@@ -0,0 +1,48 @@
+package shelf
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// entry is a document in a shelf, along with the positions it takes when moved up or down
+type entry struct {
+	index.Document
+	Up   int
+	Down int
+}
+
+// Show renders the documents in a shelf in the order set by its owner, who can also manage it from here
+func (s *Controller) Show(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	shelf, err := s.shelf(c)
+	if err != nil {
+		return err
+	}
+
+	documents, err := s.shelvesRepository.Documents(shelf.ID)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	entries := make([]entry, len(documents))
+	for i, doc := range documents {
+		entries[i] = entry{Document: doc, Up: i - 1, Down: i + 1}
+	}
+
+	if err = c.Render("shelf/show", fiber.Map{
+		"Title":         shelf.Name,
+		"Shelf":         shelf,
+		"Documents":     entries,
+		"IsOwner":       shelf.UserID == int(session.ID),
+		"NameMaxLength": model.ShelfNameMaxLength,
+	}, "layout"); err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package shelf
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Update renames a shelf and sets whether it is public
+func (s *Controller) Update(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	shelf, err := s.owned(c)
+	if err != nil {
+		return err
+	}
+
+	shelf.Name = strings.TrimSpace(c.FormValue("name"))
+	// Users with a private profile cannot make their shelves public
+	shelf.Public = c.FormValue("public") == "on" && session.PrivateProfile == 0
+	if errs := shelf.Validate(); len(errs) > 0 {
+		return fiber.ErrBadRequest
+	}
+
+	if err := s.shelvesRepository.Save(shelf); err != nil {
+		if errors.Is(err, model.ErrShelfNameTaken) {
+			return fiber.ErrBadRequest
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	c.Set("HX-Refresh", "true")
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,27 @@
+package stats
+
+import (
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+type readingRepository interface {
+	Stats(userID, year int, wordsPerMinute float64) (model.ReadingStats, error)
+	CompletedStatsByYear(userID int, wordsPerMinute float64) ([]model.CompletedYearStats, error)
+}
+
+type usersRepository interface {
+	FindByUsername(username string) (*model.User, error)
+}
+
+type Controller struct {
+	readingRepository readingRepository
+	usersRepository   usersRepository
+}
+
+// NewController returns a new instance of the reading statistics controller
+func NewController(readingRepository readingRepository, usersRepository usersRepository) *Controller {
+	return &Controller{
+		readingRepository: readingRepository,
+		usersRepository:   usersRepository,
+	}
+}
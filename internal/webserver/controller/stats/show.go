@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// Show renders the reading statistics of the logged in user for the year passed in the query string,
+// or for all time if it is 0
+func (s *Controller) Show(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	year := time.Now().Year()
+	if c.Query("year") != "" {
+		var err error
+		if year, err = strconv.Atoi(c.Query("year")); err != nil || year < 0 {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	stats, err := s.readingRepository.Stats(int(session.ID), year, session.WordsPerMinute)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	yearStats, err := s.readingRepository.CompletedStatsByYear(int(session.ID), session.WordsPerMinute)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	// Years without completions are not listed, but they can still be selected (e.g. at the beginning of a year)
+	if !slices.ContainsFunc(yearStats, func(s model.CompletedYearStats) bool { return s.Year == year }) {
+		yearStats = slices.Insert(yearStats, min(1, len(yearStats)), model.CompletedYearStats{Year: year})
+	}
+
+	return c.Render("stats/index", fiber.Map{
+		"Title":     "Statistics",
+		"User":      session.User,
+		"Stats":     stats,
+		"Year":      year,
+		"YearStats": yearStats,
+	}, "layout")
+}
+
+// YearInReview renders a shareable summary of the documents completed by a user in a year.
+// Users with a private profile only have their summary available to themselves and to admins.
+func (s *Controller) YearInReview(c fiber.Ctx) error {
+	session, _ := c.Locals("Session").(model.Session)
+
+	year, err := strconv.Atoi(c.Params("year"))
+	if err != nil || year <= 0 {
+		return fiber.ErrNotFound
+	}
+
+	user, err := s.usersRepository.FindByUsername(c.Params("username"))
+	if err != nil {
+		log.Println(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrNotFound
+	}
+	if user.PrivateProfile != 0 && session.ID != user.ID && session.Role != model.RoleAdmin {
+		return fiber.ErrNotFound
+	}
+
+	stats, err := s.readingRepository.Stats(int(user.ID), year, user.WordsPerMinute)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.Render("stats/year-in-review", fiber.Map{
+		"Title": "Year in review",
+		"User":  user,
+		"Stats": stats,
+	}, "layout")
+}
@@ -5,12 +5,13 @@ import (
 
 	"github.com/svera/coreander/v4/internal/i18n"
 	"github.com/svera/coreander/v4/internal/result"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
 type Sender interface {
 	From() string
-	Send(address, subject, body string) error
+	Send(address string, message mailer.Message) error
 }
 
 type usersRepository interface {
@@ -32,6 +33,14 @@ type invitationsRepository interface {
 	DeleteByEmail(email string) error
 }
 
+type readingRepository interface {
+	MeasuredWordsPerMinute(userID int) (float64, error)
+}
+
+type notificationsRepository interface {
+	Notify(userIDs []int, notification model.Notification, emailed bool)
+}
+
 type Config struct {
 	MinPasswordLength        int
 	WordsPerMinute           float64
@@ -43,20 +52,24 @@ type Config struct {
 }
 
 type Controller struct {
-	usersRepository       usersRepository
-	invitationsRepository invitationsRepository
-	config                Config
-	sender                Sender
-	translator            i18n.Translator
+	usersRepository         usersRepository
+	invitationsRepository   invitationsRepository
+	readingRepository       readingRepository
+	notificationsRepository notificationsRepository
+	config                  Config
+	sender                  Sender
+	translator              i18n.Translator
 }
 
 // NewController returns a new instance of the users controller
-func NewController(usersRepository usersRepository, invitationsRepository invitationsRepository, usersCfg Config, sender Sender, translator i18n.Translator) *Controller {
+func NewController(usersRepository usersRepository, invitationsRepository invitationsRepository, readingRepository readingRepository, notificationsRepository notificationsRepository, usersCfg Config, sender Sender, translator i18n.Translator) *Controller {
 	return &Controller{
-		usersRepository:       usersRepository,
-		invitationsRepository: invitationsRepository,
-		config:                usersCfg,
-		sender:                sender,
-		translator:            translator,
+		usersRepository:         usersRepository,
+		invitationsRepository:   invitationsRepository,
+		readingRepository:       readingRepository,
+		notificationsRepository: notificationsRepository,
+		config:                  usersCfg,
+		sender:                  sender,
+		translator:              translator,
 	}
 }
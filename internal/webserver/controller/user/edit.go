@@ -2,6 +2,7 @@ package user
 
 import (
 	"log"
+	"slices"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/svera/coreander/v4/internal/webserver/model"
@@ -27,15 +28,24 @@ func (u *Controller) Edit(c fiber.Ctx) error {
 		return fiber.ErrForbidden
 	}
 
+	// Allow linking to a specific tab of the form
+	activeTab := c.Query("tab")
+	if !slices.Contains([]string{"profile", "password", "passkeys", "goals", "devices"}, activeTab) {
+		activeTab = "options"
+	}
+
+	measuredWordsPerMinute, _ := u.readingRepository.MeasuredWordsPerMinute(int(user.ID))
+
 	vars := fiber.Map{
-		"Title":              "Edit user",
-		"User":               user,
-		"MinPasswordLength":  u.config.MinPasswordLength,
-		"UsernamePattern":    model.UsernamePattern,
-		"Errors":             map[string]string{},
-		"EmailFrom":          u.sender.From(),
-		"ActiveTab":          "options",
-		"AvailableLanguages": c.Locals("AvailableLanguages"),
+		"Title":                  "Edit user",
+		"User":                   user,
+		"MinPasswordLength":      u.config.MinPasswordLength,
+		"UsernamePattern":        model.UsernamePattern,
+		"Errors":                 map[string]string{},
+		"EmailFrom":              u.sender.From(),
+		"ActiveTab":              activeTab,
+		"AvailableLanguages":     c.Locals("AvailableLanguages"),
+		"MeasuredWordsPerMinute": measuredWordsPerMinute,
 	}
 
 	if c.Get("HX-Request") == "true" {
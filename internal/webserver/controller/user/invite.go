@@ -11,6 +11,7 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
 	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 )
 
@@ -20,6 +21,7 @@ func (u *Controller) SendInvite(c fiber.Ctx) error {
 		return fiber.ErrNotFound
 	}
 
+	session, _ := c.Locals("Session").(model.Session)
 	raw := c.FormValue("email")
 	lang := c.Locals("Lang").(string)
 
@@ -44,10 +46,11 @@ func (u *Controller) SendInvite(c fiber.Ctx) error {
 		fqdn = "http://" + fqdn
 	}
 
-	subject := u.translator.T(lang, "You've been invited to join Coreander")
+	// Invitees have no language preference yet, so invitations are written in the language of the inviter
+	composer := mailer.NewComposer(c.App().Config().Views, u.translator)
 	type inviteEmail struct {
-		email string
-		body  string
+		email   string
+		message mailer.Message
 	}
 	emails := make([]inviteEmail, 0, len(addresses))
 
@@ -61,36 +64,34 @@ func (u *Controller) SendInvite(c fiber.Ctx) error {
 			Email:      email,
 			UUID:       uuid.NewString(),
 			ValidUntil: time.Now().UTC().Add(u.config.InvitationTimeout),
+			InviterID:  int(session.ID),
 		}
 		if err := u.invitationsRepository.Create(invitation); err != nil {
 			log.Printf("error creating invitation: %v\n", err)
 			return fiber.ErrInternalServerError
 		}
 
-		invitationLink := fmt.Sprintf("%s/invite?id=%s", fqdn, invitation.UUID)
-		if err := c.Render("user/invitation-email", fiber.Map{
-			"InvitationLink":    invitationLink,
+		message, err := composer.Compose("invitation", lang, fiber.Map{
+			"InvitationLink":    fmt.Sprintf("%s/invite?id=%s", fqdn, invitation.UUID),
 			"InvitationTimeout": strconv.FormatFloat(u.config.InvitationTimeout.Hours(), 'f', -1, 64),
-		}); err != nil {
+		}, "You've been invited to join Coreander")
+		if err != nil {
 			log.Printf("error rendering invitation email: %v\n", err)
 			return fiber.ErrInternalServerError
 		}
 		emails = append(emails, inviteEmail{
-			email: email,
-			body:  string(c.Response().Body()),
+			email:   email,
+			message: message,
 		})
 	}
 
-	// One background goroutine: sequential sends are gentler on SMTP and avoid a burst of connections.
-	emailsToSend := append([]inviteEmail(nil), emails...)
-	sender := u.sender
-	go func() {
-		for _, invite := range emailsToSend {
-			if err := sender.Send(invite.email, subject, invite.body); err != nil {
-				log.Printf("error sending invitation email to %s: %v\n", invite.email, err)
-			}
+	// Emails are queued by the sender, so this does not wait for the mail server
+	for _, invite := range emails {
+		if err := u.sender.Send(invite.email, invite.message); err != nil {
+			log.Printf("error sending invitation email to %s: %v\n", invite.email, err)
+			return fiber.ErrInternalServerError
 		}
-	}()
+	}
 
 	var successMsg string
 	if len(addresses) == 1 {
@@ -190,6 +191,15 @@ func (u *Controller) AcceptInvite(c fiber.Ctx) error {
 		log.Printf("error deleting invitation: %v\n", err)
 	}
 
+	if invitation.InviterID > 0 {
+		u.notificationsRepository.Notify([]int{invitation.InviterID}, model.Notification{
+			Type:    model.NotificationInvitationAccepted,
+			Actor:   user.Name,
+			Subject: user.Username,
+			Link:    "/users/" + user.Username,
+		}, false)
+	}
+
 	c.Cookie(&fiber.Cookie{
 		Name:    "success-once",
 		Value:   u.translator.T(lang, "Account created successfully. Please log in."),
@@ -49,14 +49,17 @@ func (u *Controller) Update(c fiber.Ctx) error {
 		return fiber.ErrInternalServerError
 	}
 
+	measuredWordsPerMinute, _ := u.readingRepository.MeasuredWordsPerMinute(int(user.ID))
+
 	vars := fiber.Map{
-		"Title":             "Edit user",
-		"User":              user,
-		"MinPasswordLength": u.config.MinPasswordLength,
-		"UsernamePattern":   model.UsernamePattern,
-		"Errors":            validationErrs,
-		"EmailFrom":         u.sender.From(),
-		"ActiveTab":         c.FormValue("tab"),
+		"Title":                  "Edit user",
+		"User":                   user,
+		"MinPasswordLength":      u.config.MinPasswordLength,
+		"UsernamePattern":        model.UsernamePattern,
+		"Errors":                 validationErrs,
+		"EmailFrom":              u.sender.From(),
+		"ActiveTab":              c.FormValue("tab"),
+		"MeasuredWordsPerMinute": measuredWordsPerMinute,
 	}
 
 	if len(validationErrs) > 0 {
@@ -73,6 +76,7 @@ func (u *Controller) updateOptions(c fiber.Ctx, user *model.User, session model.
 	} else {
 		user.PrivateProfile = 0
 	}
+	user.ShareActivity = c.FormValue("share-activity") == "on"
 	user.SendToEmail = c.FormValue("send-to-email")
 	user.PreferredEpubType = strings.ToLower(c.FormValue("preferred-epub-type"))
 	defaultActionFromForm := strings.ToLower(c.FormValue("default-action"))
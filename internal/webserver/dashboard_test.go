@@ -0,0 +1,67 @@
+package webserver_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+func TestDashboard(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	t.Run("Regular users cannot access the dashboard", func(t *testing.T) {
+		response, err := getRequest(regularCookie, app, "/dashboard", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+
+	t.Run("Admins see library and usage statistics", func(t *testing.T) {
+		for _, cookie := range []*http.Cookie{adminCookie, regularCookie} {
+			if _, err := highlight(cookie, app, "miguel-de-cervantes-y-saavedra-don-quijote-de-la-mancha", http.MethodPost, t); err != nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+		}
+
+		response, err := getRequest(adminCookie, app, "/dashboard", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		expected := map[string]string{
+			"#dashboard-documents":                "9",
+			"#dashboard-active-users":             "2 / 2",
+			"#dashboard-formats li:first-child":   "EPUB6",
+			"#dashboard-formats li:last-child":    "PDF3",
+			"#dashboard-languages li:first-child": "English6",
+			"#dashboard-most-highlighted li":      "Don Quijote de la Mancha2",
+			"#dashboard-most-read li:first-child": "No documents yet",
+		}
+		for selector, text := range expected {
+			if got := strings.Join(strings.Fields(doc.Find(selector).Text()), ""); got != strings.ReplaceAll(text, " ", "") {
+				t.Errorf("Expected %s to contain '%s', got '%s'", selector, text, got)
+			}
+		}
+	})
+}
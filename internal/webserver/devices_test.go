@@ -0,0 +1,156 @@
+package webserver_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestDevices(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	smtpMock := &infrastructure.SMTPMock{}
+	app := bootstrapApp(db, smtpMock, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+
+	saveDevice := func(t *testing.T, values url.Values) *http.Response {
+		t.Helper()
+
+		response, err := postRequest(values, adminCookie, app, "/users/admin/devices", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return response
+	}
+
+	device := func(t *testing.T, name string) model.Device {
+		t.Helper()
+
+		var device model.Device
+		if err := db.Where("name = ?", name).First(&device).Error; err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return device
+	}
+
+	send := func(t *testing.T, deviceID uint, expectedStatus int) {
+		t.Helper()
+
+		if expectedStatus == http.StatusOK {
+			smtpMock.Wg.Add(1)
+		}
+		response, err := postRequest(url.Values{"device": {fmt.Sprint(deviceID)}}, adminCookie, app, "/documents/"+testDocSlug+"/send", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		if expectedStatus == http.StatusOK {
+			smtpMock.Wg.Wait()
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	t.Run("Users can add devices", func(t *testing.T) {
+		response := saveDevice(t, url.Values{"name": {"Kobo"}, "email": {"kobo@example.com"}, "format": {"kepub"}, "max-attachment-size": {"50"}})
+		mustReturnStatus(response, http.StatusOK, t)
+		body, _ := io.ReadAll(response.Body)
+		if !strings.Contains(string(body), "kobo@example.com") {
+			t.Error("Expected device to be listed")
+		}
+	})
+
+	t.Run("Adding a device with an existing name replaces it", func(t *testing.T) {
+		mustReturnStatus(saveDevice(t, url.Values{"name": {"kobo"}, "email": {"my-kobo@example.com"}, "format": {"kepub"}}), http.StatusOK, t)
+
+		var devices []model.Device
+		db.Find(&devices)
+		if len(devices) != 1 || devices[0].Email != "my-kobo@example.com" || devices[0].MaxAttachmentSize != 0 {
+			t.Errorf("Expected a single updated device, got %+v", devices)
+		}
+	})
+
+	t.Run("Invalid devices are rejected", func(t *testing.T) {
+		mustReturnStatus(saveDevice(t, url.Values{"name": {"Kindle"}, "email": {"kindle"}}), http.StatusBadRequest, t)
+		mustReturnStatus(saveDevice(t, url.Values{"name": {"Kindle"}, "email": {"kindle@example.com"}, "format": {"docx"}}), http.StatusBadRequest, t)
+	})
+
+	t.Run("Documents sent to a device are converted to its format", func(t *testing.T) {
+		send(t, device(t, "Kobo").ID, http.StatusOK)
+		if smtpMock.LastAddress != "my-kobo@example.com" || !strings.HasSuffix(smtpMock.LastFileName, ".kepub.epub") {
+			t.Errorf("Expected a KEPUB file sent to the device, got %s sent to %s", smtpMock.LastFileName, smtpMock.LastAddress)
+		}
+	})
+
+	t.Run("Documents are sent in their original format if they cannot be converted to the device one", func(t *testing.T) {
+		mustReturnStatus(saveDevice(t, url.Values{"name": {"Kindle"}, "email": {"kindle@example.com"}, "format": {"mobi"}}), http.StatusOK, t)
+
+		// MOBI conversions are only available when ebook-convert is installed
+		send(t, device(t, "Kindle").ID, http.StatusOK)
+		if !strings.HasSuffix(smtpMock.LastFileName, ".epub") && !strings.HasSuffix(smtpMock.LastFileName, ".mobi") {
+			t.Errorf("Expected an EPUB or MOBI file, got %s", smtpMock.LastFileName)
+		}
+	})
+
+	t.Run("Sent documents are listed in the history and in the send dialog", func(t *testing.T) {
+		response, err := getRequest(adminCookie, app, "/users/admin/devices", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		body, _ := io.ReadAll(response.Body)
+		if got := strings.Count(string(body), "/documents/"+testDocSlug+"\""); got != 2 {
+			t.Errorf("Expected 2 sent documents in the history, got %d", got)
+		}
+
+		response, err = getRequest(adminCookie, app, "/documents/"+testDocSlug+"/devices", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		body, _ = io.ReadAll(response.Body)
+		if got := strings.Count(string(body), "Already sent on"); got != 2 {
+			t.Errorf("Expected both devices to show the document as sent, got %d", got)
+		}
+	})
+
+	t.Run("Users cannot use or manage other users' devices", func(t *testing.T) {
+		response, err := getRequest(adminCookie, app, "/users/regular/devices", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		regularCookie, err := login(app, "regular@example.com", "regular", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		response, err = postRequest(url.Values{"device": {fmt.Sprint(device(t, "Kobo").ID)}}, regularCookie, app, "/documents/"+testDocSlug+"/send", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNotFound, t)
+	})
+
+	t.Run("Removing a device keeps its sent documents in the history", func(t *testing.T) {
+		response, err := deleteRequest(nil, adminCookie, app, fmt.Sprintf("/users/admin/devices/%d", device(t, "Kobo").ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		var sent []model.SentDocument
+		db.Order("id").Find(&sent)
+		if len(sent) != 2 || sent[0].DeviceID != nil || sent[0].DeviceName != "Kobo" {
+			t.Errorf("Expected the sent document to be kept without device, got %+v", sent)
+		}
+	})
+}
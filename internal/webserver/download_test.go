@@ -0,0 +1,142 @@
+package webserver_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+func TestDownload(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	appFs := loadDirInMemoryFs(testLibraryDir)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, appFs, defaultTestConfig())
+	downloadURL := "/documents/" + testDocSlug + "/download"
+
+	download := func(t *testing.T, URL string, headers map[string]string, expectedStatus int) (*http.Response, []byte) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, URL, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		response, err := app.Test(req)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return response, body
+	}
+
+	full, content := download(t, downloadURL, nil, http.StatusOK)
+	etag := full.Header.Get("ETag")
+	lastModified := full.Header.Get("Last-Modified")
+
+	t.Run("Whole files are sent as attachments by default", func(t *testing.T) {
+		if got := full.Header.Get("Accept-Ranges"); got != "bytes" {
+			t.Errorf("Expected range requests to be accepted, got '%s'", got)
+		}
+		if etag == "" || lastModified == "" {
+			t.Errorf("Expected validators to be sent, got ETag '%s' and Last-Modified '%s'", etag, lastModified)
+		}
+		if got := full.Header.Get("Content-Length"); got != strconv.Itoa(len(content)) || len(content) == 0 {
+			t.Errorf("Expected content length to be %d, got '%s'", len(content), got)
+		}
+		if got := full.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "attachment;") {
+			t.Errorf("Expected file to be sent as an attachment, got '%s'", got)
+		}
+
+		response, _ := download(t, downloadURL+"?disposition=inline", nil, http.StatusOK)
+		if got := response.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "inline;") {
+			t.Errorf("Expected file to be sent inline, got '%s'", got)
+		}
+	})
+
+	t.Run("Ranges of the file can be requested", func(t *testing.T) {
+		response, body := download(t, downloadURL, map[string]string{"Range": "bytes=10-19"}, http.StatusPartialContent)
+		if string(body) != string(content[10:20]) {
+			t.Errorf("Expected bytes 10 to 19 of the file, got %d bytes", len(body))
+		}
+		if got, expected := response.Header.Get("Content-Range"), "bytes 10-19/"+strconv.Itoa(len(content)); got != expected {
+			t.Errorf("Expected content range '%s', got '%s'", expected, got)
+		}
+
+		_, body = download(t, downloadURL, map[string]string{"Range": "bytes=-5"}, http.StatusPartialContent)
+		if string(body) != string(content[len(content)-5:]) {
+			t.Errorf("Expected last 5 bytes of the file, got %d bytes", len(body))
+		}
+
+		download(t, downloadURL, map[string]string{"Range": "bytes=" + strconv.Itoa(len(content)) + "-"}, http.StatusRequestedRangeNotSatisfiable)
+	})
+
+	t.Run("Ranges of outdated versions of the file are not honoured", func(t *testing.T) {
+		download(t, downloadURL, map[string]string{"Range": "bytes=0-9", "If-Range": etag}, http.StatusPartialContent)
+		download(t, downloadURL, map[string]string{"Range": "bytes=0-9", "If-Range": "\"outdated\""}, http.StatusOK)
+	})
+
+	t.Run("Unchanged files are not sent again", func(t *testing.T) {
+		download(t, downloadURL, map[string]string{"If-None-Match": etag}, http.StatusNotModified)
+		download(t, downloadURL, map[string]string{"If-None-Match": "\"outdated\""}, http.StatusOK)
+		download(t, downloadURL, map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified)
+	})
+
+	t.Run("Converted files have their own validators", func(t *testing.T) {
+		response, _ := download(t, downloadURL+"?format=kepub", map[string]string{"If-None-Match": etag}, http.StatusOK)
+		if got := response.Header.Get("Content-Disposition"); !strings.Contains(got, ".kepub.epub") {
+			t.Errorf("Expected a KEPUB file, got '%s'", got)
+		}
+		download(t, downloadURL+"?format=kepub", map[string]string{"If-None-Match": response.Header.Get("ETag")}, http.StatusNotModified)
+	})
+
+	t.Run("Files cannot be downloaded in formats there are no converters for", func(t *testing.T) {
+		download(t, downloadURL+"?format=docx", nil, http.StatusNotAcceptable)
+	})
+
+	t.Run("Requesting the original format sends the original file", func(t *testing.T) {
+		_, original := download(t, downloadURL+"?format=epub", nil, http.StatusOK)
+		if string(original) != string(content) {
+			t.Errorf("Expected the original file to be sent")
+		}
+	})
+
+	t.Run("Converted files are cached until the document is deleted", func(t *testing.T) {
+		_, converted := download(t, downloadURL+"?format=kepub", nil, http.StatusOK)
+		_, cached := download(t, downloadURL+"?format=kepub", nil, http.StatusOK)
+		if len(converted) == 0 || string(converted) != string(cached) {
+			t.Errorf("Expected the cached conversion to be sent, got %d bytes instead of %d", len(cached), len(converted))
+		}
+		if entries, _ := afero.ReadDir(appFs, "conversions"); len(entries) != 1 {
+			t.Fatalf("Expected 1 cached conversion, got %d", len(entries))
+		}
+
+		adminCookie, err := login(app, "admin@example.com", "admin", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		response, err := deleteRequest(url.Values{}, adminCookie, app, "/documents/"+testDocSlug, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		if entries, _ := afero.ReadDir(appFs, "conversions"); len(entries) != 0 {
+			t.Errorf("Expected cached conversions to be removed, got %d", len(entries))
+		}
+	})
+
+	t.Run("Unknown documents cannot be downloaded", func(t *testing.T) {
+		download(t, "/documents/unknown/download", nil, http.StatusNotFound)
+	})
+}
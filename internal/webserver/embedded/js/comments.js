@@ -0,0 +1,101 @@
+"use strict"
+
+// Autocompletion of @mentions in discussion comments, using the same users lookup as sharing.
+// Comments are reloaded from the server after each change, so the event listeners are attached to the document.
+const mentionQuery = /(?:^|[^A-Za-z0-9_\-.])@([A-Za-z0-9_\-.]*)$/
+
+let mentionMenu = null
+let mentionFetchController = null
+let mentionDebounceTimer = null
+
+function closeMentionMenu() {
+    if (mentionMenu) {
+        mentionMenu.remove()
+        mentionMenu = null
+    }
+}
+
+function currentMention(textarea) {
+    const match = textarea.value.slice(0, textarea.selectionStart).match(mentionQuery)
+    return match ? match[1] : null
+}
+
+function insertMention(textarea, username) {
+    const caret = textarea.selectionStart
+    const before = textarea.value.slice(0, caret).replace(/@[A-Za-z0-9_\-.]*$/, `@${username} `)
+    textarea.value = before + textarea.value.slice(caret)
+    textarea.setSelectionRange(before.length, before.length)
+    textarea.focus()
+    closeMentionMenu()
+}
+
+function showMentionMenu(textarea, users) {
+    closeMentionMenu()
+    if (users.length === 0) {
+        return
+    }
+    mentionMenu = document.createElement('ul')
+    mentionMenu.className = 'dropdown-menu show position-absolute'
+    users.forEach(user => {
+        const item = document.createElement('li')
+        const button = document.createElement('button')
+        button.type = 'button'
+        button.className = 'dropdown-item'
+        button.textContent = `${user.name} (${user.username})`
+        button.addEventListener('mousedown', evt => {
+            evt.preventDefault()
+            insertMention(textarea, user.username)
+        })
+        item.appendChild(button)
+        mentionMenu.appendChild(item)
+    })
+    textarea.parentNode.classList.add('position-relative')
+    textarea.parentNode.appendChild(mentionMenu)
+}
+
+document.addEventListener('input', evt => {
+    const textarea = evt.target.closest?.('textarea[data-mentions]')
+    if (!textarea) {
+        return
+    }
+    const query = currentMention(textarea)
+    clearTimeout(mentionDebounceTimer)
+    if (!query) {
+        closeMentionMenu()
+        return
+    }
+    mentionDebounceTimer = setTimeout(() => {
+        if (mentionFetchController) {
+            mentionFetchController.abort()
+        }
+        mentionFetchController = new AbortController()
+        const endpoint = textarea.dataset.usersEndpoint || '/users/share-recipients'
+        fetch(`${endpoint}?q=${encodeURIComponent(query)}`, { signal: mentionFetchController.signal })
+            .then(response => response.ok ? response.json() : [])
+            .then(users => showMentionMenu(textarea, users))
+            .catch(() => {})
+    }, 200)
+})
+
+document.addEventListener('keydown', evt => {
+    if (evt.key === 'Escape' && mentionMenu) {
+        closeMentionMenu()
+    }
+})
+
+document.addEventListener('focusout', evt => {
+    if (evt.target.closest?.('textarea[data-mentions]')) {
+        closeMentionMenu()
+    }
+})
+
+// Notifications about mentions link to the comment, which is not in the page until the discussion is loaded
+document.addEventListener('htmx:afterSettle', evt => {
+    if (!location.hash.startsWith('#comment-') || !evt.target.closest?.('#discussion')) {
+        return
+    }
+    const comment = document.getElementById(location.hash.slice(1))
+    if (comment) {
+        comment.scrollIntoView({ block: 'center' })
+    }
+})
@@ -13,6 +13,10 @@ import(`./luxon.min.js${versionParam}`).then(({ DateTime }) => {
             if (dt.isValid) {
                 if (element.classList.contains('relative')) {
                     element.textContent = dt.toRelative({ locale: document.documentElement.lang });
+                } else if (element.classList.contains('month')) {
+                    element.textContent = dt.toLocaleString({ month: 'long' }, { locale: document.documentElement.lang });
+                } else if (element.classList.contains('with-time')) {
+                    element.textContent = dt.toLocaleString(DateTime.DATETIME_MED_WITH_SECONDS, { locale: document.documentElement.lang });
                 } else {
                     // This is a temporary fix to a bug in Luxon
                     // https://github.com/moment/luxon/issues/1687
@@ -0,0 +1,166 @@
+// Registers the service worker and manages the documents pinned for offline reading.
+// Pinned documents are kept in the same cache the service worker reads from, along with a small
+// JSON entry per document used to list them in the offline page.
+
+const DOCUMENTS_CACHE = 'coreander-documents'
+const PINNED_PREFIX = '/offline-documents/'
+
+const version = new URL(import.meta.url).searchParams.get('v')
+
+const documentURLs = slug => [
+    // The offline parameter prevents the download from counting as the document being opened
+    [`/documents/${slug}/read?offline=true`, `/documents/${slug}/read`],
+    [`/documents/${slug}/download?disposition=inline`, `/documents/${slug}/download?disposition=inline`],
+    [`/documents/${slug}/cover`, `/documents/${slug}/cover`],
+]
+
+export async function pin(slug, title, authors) {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    for (const [url, key] of documentURLs(slug)) {
+        const response = await fetch(url)
+        // Documents without cover are still readable
+        if (!response.ok && !key.endsWith('/cover')) {
+            throw new Error(`Could not download ${url}: ${response.status}`)
+        }
+        if (response.ok) {
+            await cache.put(key, response)
+        }
+    }
+    await cache.put(PINNED_PREFIX + slug, Response.json({ slug, title, authors }))
+}
+
+export async function unpin(slug) {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    await Promise.all([
+        ...documentURLs(slug).map(([, key]) => cache.delete(key)),
+        cache.delete(PINNED_PREFIX + slug),
+    ])
+}
+
+export async function isPinned(slug) {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    return Boolean(await cache.match(PINNED_PREFIX + slug))
+}
+
+export async function pinned() {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    const keys = await cache.keys()
+    const documents = []
+    for (const request of keys) {
+        if (new URL(request.url).pathname.startsWith(PINNED_PREFIX)) {
+            documents.push(await (await cache.match(request)).json())
+        }
+    }
+    return documents.sort((a, b) => a.title.localeCompare(b.title))
+}
+
+async function refreshButtons() {
+    for (const button of document.querySelectorAll('[data-offline-slug]')) {
+        const pinnedDocument = await isPinned(button.dataset.offlineSlug)
+        button.classList.remove('d-none')
+        button.querySelector('.offline-label').textContent = pinnedDocument ? button.dataset.unpinLabel : button.dataset.pinLabel
+        button.querySelector('i').className = `bi ${pinnedDocument ? 'bi-cloud-slash' : 'bi-cloud-check'} me-2`
+    }
+    for (const button of document.querySelectorAll('[data-offline-queue]')) {
+        button.classList.remove('d-none')
+        button.disabled = document.querySelectorAll('#queue-documents [data-slug]').length === 0
+    }
+}
+
+async function toggle(button) {
+    const { offlineSlug: slug, offlineTitle: title, offlineAuthors: authors } = button.dataset
+    button.disabled = true
+    try {
+        if (await isPinned(slug)) {
+            await unpin(slug)
+            window.showToast?.(button.dataset.unpinnedMessage)
+        } else {
+            await pin(slug, title, authors)
+            window.showToast?.(button.dataset.pinnedMessage)
+        }
+    } catch (error) {
+        console.error(error)
+        window.showToast?.(button.dataset.errorMessage, 'danger')
+    } finally {
+        button.disabled = false
+        refreshButtons()
+    }
+}
+
+async function pinQueue(button) {
+    button.disabled = true
+    try {
+        for (const item of document.querySelectorAll('#queue-documents [data-slug]')) {
+            if (!await isPinned(item.dataset.slug)) {
+                await pin(item.dataset.slug, item.dataset.title, item.dataset.authors)
+            }
+        }
+        window.showToast?.(button.dataset.pinnedMessage)
+    } catch (error) {
+        console.error(error)
+        window.showToast?.(button.dataset.errorMessage, 'danger')
+    } finally {
+        button.disabled = false
+    }
+}
+
+async function listPinned(list) {
+    const documents = await pinned()
+    if (documents.length === 0) return
+
+    document.getElementById('offline-documents-empty')?.remove()
+    for (const pinnedDocument of documents) {
+        const item = document.createElement('li')
+        item.className = 'list-group-item px-0'
+        const link = document.createElement('a')
+        link.href = `/documents/${pinnedDocument.slug}/read`
+        link.className = 'fw-bold'
+        link.textContent = pinnedDocument.title
+        item.append(link)
+        if (pinnedDocument.authors) {
+            const authors = document.createElement('p')
+            authors.className = 'mb-0 text-body-secondary'
+            authors.textContent = pinnedDocument.authors
+            item.append(authors)
+        }
+        list.append(item)
+    }
+}
+
+if ('serviceWorker' in navigator && 'caches' in window) {
+    navigator.serviceWorker.register(`/service-worker.js${version ? `?v=${version}` : ''}`, { scope: '/' })
+        .catch(error => console.error('Error registering service worker:', error))
+
+    // Let the service worker know who is logged in, so positions and documents saved offline by
+    // someone else are not kept for this user
+    const user = document.querySelector('meta[name="user-id"]')?.content
+    if (user) {
+        navigator.serviceWorker.ready.then(() => navigator.serviceWorker.controller?.postMessage({ type: 'session', user }))
+    }
+
+    // Send the reading positions saved while offline
+    const replay = () => navigator.serviceWorker.controller?.postMessage({ type: 'replay-positions' })
+    window.addEventListener('online', replay)
+    if (navigator.onLine) {
+        navigator.serviceWorker.ready.then(replay)
+    }
+
+    document.addEventListener('click', event => {
+        const button = event.target.closest('[data-offline-slug]')
+        if (button) {
+            toggle(button)
+            return
+        }
+        const queueButton = event.target.closest('[data-offline-queue]')
+        if (queueButton) {
+            pinQueue(queueButton)
+        }
+    })
+    refreshButtons()
+    document.body.addEventListener('htmx:afterSettle', refreshButtons)
+
+    const list = document.getElementById('offline-documents')
+    if (list) {
+        listPinned(list)
+    }
+}
@@ -0,0 +1,107 @@
+"use strict"
+
+// Passkey (WebAuthn) login and registration. The server sends the ceremony options with binary
+// values encoded as base64url, so they need to be converted back and forth to ArrayBuffers.
+
+function decode(value) {
+    const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+    const padded = base64.padEnd(base64.length + (4 - base64.length % 4) % 4, '=')
+    return Uint8Array.from(atob(padded), c => c.charCodeAt(0)).buffer
+}
+
+function encode(buffer) {
+    const bytes = new Uint8Array(buffer)
+    let binary = ''
+    bytes.forEach(b => binary += String.fromCharCode(b))
+    return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+}
+
+async function post(url, body) {
+    const response = await fetch(url, {
+        method: 'POST',
+        headers: {'Content-Type': 'application/json'},
+        body: body ? JSON.stringify(body) : undefined,
+    })
+    if (!response.ok) {
+        throw new Error(`${url} returned ${response.status}`)
+    }
+    return response
+}
+
+async function signIn(container) {
+    try {
+        const options = await (await post('/sessions/passkey/options')).json()
+        const publicKey = options.publicKey
+        publicKey.challenge = decode(publicKey.challenge)
+        if (publicKey.allowCredentials) {
+            publicKey.allowCredentials.forEach(c => c.id = decode(c.id))
+        }
+
+        const credential = await navigator.credentials.get({publicKey})
+        const result = await (await post('/sessions/passkey', {
+            id: credential.id,
+            rawId: encode(credential.rawId),
+            type: credential.type,
+            response: {
+                authenticatorData: encode(credential.response.authenticatorData),
+                clientDataJSON: encode(credential.response.clientDataJSON),
+                signature: encode(credential.response.signature),
+                userHandle: credential.response.userHandle ? encode(credential.response.userHandle) : null,
+            },
+        })).json()
+
+        window.location.href = result.redirect
+    } catch (err) {
+        console.error(err)
+        window.showToast?.(container.dataset.errorMessage, 'danger')
+    }
+}
+
+async function register(form) {
+    try {
+        const options = await (await post(`${form.getAttribute('action')}/options`)).json()
+        const publicKey = options.publicKey
+        publicKey.challenge = decode(publicKey.challenge)
+        publicKey.user.id = decode(publicKey.user.id)
+        if (publicKey.excludeCredentials) {
+            publicKey.excludeCredentials.forEach(c => c.id = decode(c.id))
+        }
+
+        const credential = await navigator.credentials.create({publicKey})
+        const name = encodeURIComponent(form.querySelector('[name="name"]').value)
+        await post(`${form.getAttribute('action')}?name=${name}`, {
+            id: credential.id,
+            rawId: encode(credential.rawId),
+            type: credential.type,
+            response: {
+                attestationObject: encode(credential.response.attestationObject),
+                clientDataJSON: encode(credential.response.clientDataJSON),
+                transports: credential.response.getTransports ? credential.response.getTransports() : [],
+            },
+        })
+
+        form.reset()
+        window.showToast?.(form.dataset.successMessage, 'success')
+        htmx.ajax('GET', form.getAttribute('action'), {target: '#passkeys-list', swap: 'outerHTML'})
+    } catch (err) {
+        console.error(err)
+        window.showToast?.(form.dataset.errorMessage, 'danger')
+    }
+}
+
+if (window.PublicKeyCredential) {
+    const login = document.getElementById('passkey-login')
+    if (login) {
+        login.classList.remove('d-none')
+        login.querySelector('button').addEventListener('click', () => signIn(login))
+    }
+
+    const form = document.getElementById('passkey-register-form')
+    if (form) {
+        form.classList.remove('d-none')
+        form.addEventListener('submit', event => {
+            event.preventDefault()
+            register(form)
+        })
+    }
+}
@@ -0,0 +1,48 @@
+"use strict"
+
+// Drag and drop reordering of the reading queue. The list is reloaded from the server
+// after each move, so the event listeners are attached to the document.
+let dragged = null
+
+document.addEventListener('dragstart', (evt) => {
+    const item = evt.target.closest?.('#queue-documents li[data-slug]')
+    if (!item) {
+        return
+    }
+    dragged = item
+    evt.dataTransfer.effectAllowed = 'move'
+    evt.dataTransfer.setData('text/plain', item.dataset.slug)
+    item.classList.add('opacity-50')
+})
+
+document.addEventListener('dragover', (evt) => {
+    const target = evt.target.closest?.('#queue-documents li[data-slug]')
+    if (!dragged || !target || target === dragged) {
+        return
+    }
+    evt.preventDefault()
+    const rect = target.getBoundingClientRect()
+    const after = evt.clientY > rect.top + rect.height / 2
+    target.parentNode.insertBefore(dragged, after ? target.nextSibling : target)
+})
+
+document.addEventListener('drop', (evt) => {
+    if (dragged) {
+        evt.preventDefault()
+    }
+})
+
+document.addEventListener('dragend', () => {
+    if (!dragged) {
+        return
+    }
+    const item = dragged
+    dragged = null
+    item.classList.remove('opacity-50')
+
+    const position = Array.from(item.parentNode.querySelectorAll('li[data-slug]')).indexOf(item)
+    htmx.ajax('PUT', `/queue/${encodeURIComponent(item.dataset.slug)}`, {
+        values: { position: position },
+        swap: 'none',
+    })
+})
@@ -0,0 +1,232 @@
+// ReadAloud reads the document aloud with the browser's speech synthesis, highlighting the sentence being read.
+// foliate-js splits each section in sentences and returns them as SSML with a mark before each one. Browsers don't
+// support SSML, so the text after each mark is spoken as a separate utterance, and the mark is set when it starts.
+export class ReadAloud {
+    #view
+    #language
+    #onSentence
+    #onStateChange
+    #onEnd
+    #segments = []
+    #index = 0
+    // Cancelling an utterance fires its end event, so events are ignored unless they belong to the last one spoken
+    #utterance = 0
+    #playing = false
+    #active = false
+    #rate = 1
+
+    static get supported() {
+        return 'speechSynthesis' in window && 'SpeechSynthesisUtterance' in window
+    }
+
+    constructor(view, language, { onSentence, onStateChange, onEnd } = {}) {
+        this.#view = view
+        this.#language = language
+        this.#onSentence = onSentence
+        this.#onStateChange = onStateChange
+        this.#onEnd = onEnd
+
+        const savedRate = parseFloat(window.localStorage.getItem('reader-speech-rate'))
+        if (savedRate > 0) {
+            this.#rate = savedRate
+        }
+    }
+
+    get playing() {
+        return this.#playing
+    }
+
+    get active() {
+        return this.#active
+    }
+
+    get rate() {
+        return this.#rate
+    }
+
+    // voices returns the voices available for the document language, or all of them if there is none
+    voices() {
+        const all = window.speechSynthesis.getVoices()
+        const language = primaryLanguage(this.#language)
+        const matching = language ? all.filter(voice => primaryLanguage(voice.lang) === language) : []
+        return matching.length > 0 ? matching : all
+    }
+
+    hasLanguageVoices() {
+        const language = primaryLanguage(this.#language)
+        return !language || window.speechSynthesis.getVoices().some(voice => primaryLanguage(voice.lang) === language)
+    }
+
+    get voice() {
+        const voices = this.voices()
+        const saved = window.localStorage.getItem(this.#voiceKey)
+        return voices.find(voice => voice.voiceURI === saved) ?? voices.find(voice => voice.default) ?? voices[0] ?? null
+    }
+
+    // The chosen voice is remembered per language, as each document may be written in a different one
+    get #voiceKey() {
+        return `reader-voice-${primaryLanguage(this.#language) || 'default'}`
+    }
+
+    setVoice(voiceURI) {
+        window.localStorage.setItem(this.#voiceKey, voiceURI)
+        this.#restartSentence()
+    }
+
+    setRate(rate) {
+        this.#rate = rate
+        window.localStorage.setItem('reader-speech-rate', rate)
+        this.#restartSentence()
+    }
+
+    // start reads aloud from the sentence at the given range, or from the start of the current section
+    async start(range) {
+        await this.#initTTS()
+        this.#active = true
+        this.#play(range ? this.#view.tts.from(range) : this.#view.tts.start())
+    }
+
+    pause() {
+        this.#playing = false
+        this.#utterance++
+        window.speechSynthesis.cancel()
+        this.#onStateChange?.()
+    }
+
+    resume() {
+        if (!this.#active) return
+        this.#playing = true
+        this.#onStateChange?.()
+        this.#speak(this.#index)
+    }
+
+    stop() {
+        this.pause()
+        this.#active = false
+        this.#segments = []
+        this.#index = 0
+        this.#clearHighlight()
+        this.#onStateChange?.()
+    }
+
+    next() {
+        if (!this.#active) return
+        this.#play(this.#view.tts.next())
+    }
+
+    prev() {
+        if (!this.#active) return
+        const ssml = this.#view.tts.prev()
+        if (ssml) {
+            this.#play(ssml)
+        }
+    }
+
+    async #initTTS() {
+        await this.#view.initTTS('sentence', range => this.#highlight(range))
+    }
+
+    #play(ssml) {
+        if (!ssml) {
+            this.#nextSection()
+            return
+        }
+        this.#segments = parseSSML(ssml)
+        this.#playing = true
+        this.#onStateChange?.()
+        this.#speak(0)
+    }
+
+    #speak(index) {
+        if (index >= this.#segments.length) {
+            this.#play(this.#view.tts.next())
+            return
+        }
+
+        const id = ++this.#utterance
+        window.speechSynthesis.cancel()
+        this.#index = index
+        const segment = this.#segments[index]
+        if (segment.mark) {
+            this.#view.tts.setMark(segment.mark)
+        }
+
+        const utterance = new SpeechSynthesisUtterance(segment.text)
+        utterance.lang = this.#language
+        utterance.voice = this.voice
+        utterance.rate = this.#rate
+        utterance.onend = () => {
+            if (id === this.#utterance && this.#playing) {
+                this.#speak(index + 1)
+            }
+        }
+        utterance.onerror = event => {
+            if (id !== this.#utterance || event.error === 'interrupted' || event.error === 'canceled') return
+            console.error('Error reading aloud:', event.error)
+            this.pause()
+        }
+        window.speechSynthesis.speak(utterance)
+    }
+
+    #restartSentence() {
+        if (this.#playing) {
+            this.#speak(this.#index)
+        }
+    }
+
+    async #nextSection() {
+        const { renderer } = this.#view
+        const section = renderer.getContents()[0]?.index
+        await renderer.nextSection?.()
+        if (renderer.getContents()[0]?.index === section) {
+            // There are no more sections, the end of the document was reached
+            this.stop()
+            this.#onEnd?.()
+            return
+        }
+        await this.#initTTS()
+        this.#play(this.#view.tts.start())
+    }
+
+    #highlight(range) {
+        this.#view.renderer.scrollToAnchor?.(range)
+        const win = range.startContainer.ownerDocument?.defaultView
+        if (win?.CSS?.highlights && win.Highlight) {
+            win.CSS.highlights.set('read-aloud', new win.Highlight(range))
+        }
+        this.#onSentence?.(range)
+    }
+
+    #clearHighlight() {
+        for (const { doc } of this.#view.renderer.getContents?.() ?? []) {
+            doc?.defaultView?.CSS?.highlights?.delete('read-aloud')
+        }
+    }
+}
+
+const primaryLanguage = tag => (tag ?? '').split(/[-_]/)[0].toLowerCase()
+
+// parseSSML returns the text following each mark of the SSML generated by foliate-js
+const parseSSML = ssml => {
+    const doc = new DOMParser().parseFromString(ssml, 'application/xml')
+    const segments = []
+    let current = null
+    const walk = node => {
+        for (const child of node.childNodes) {
+            if (child.nodeType === Node.ELEMENT_NODE && child.localName === 'mark') {
+                current = { mark: child.getAttribute('name'), text: '' }
+                segments.push(current)
+            } else if (child.nodeType === Node.TEXT_NODE) {
+                if (!current) {
+                    current = { mark: null, text: '' }
+                    segments.push(current)
+                }
+                current.text += child.textContent
+            } else if (child.nodeType === Node.ELEMENT_NODE) {
+                walk(child)
+            }
+        }
+    }
+    walk(doc.documentElement)
+    return segments.filter(segment => segment.text.trim() !== '')
+}
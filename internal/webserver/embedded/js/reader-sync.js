@@ -1,6 +1,8 @@
 export class ReaderSync {
     #updatePositionTimeout = null
     #syncFromServerTimeout = null
+    #updatePreferencesTimeout = null
+    #syncPreferencesFromServerTimeout = null
     #isAuthenticated = false
     #view = null
 
@@ -99,6 +101,28 @@ export class ReaderSync {
         }
     }
 
+    sendReadingSession(slug, session) {
+        // keepalive lets the request complete even if the reader is being closed
+        fetch(`/documents/${slug}/reading-sessions`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify(session),
+            keepalive: true
+        }).then(response => {
+            if (response.status === 403) {
+                this.#isAuthenticated = false
+                window.dispatchEvent(new CustomEvent('reader-session-expired'))
+                return
+            }
+
+            if (!response.ok && response.status !== 204) {
+                console.error('Failed to send reading session to server:', response.statusText)
+            }
+        }).catch(error => console.error('Error sending reading session to server:', error))
+    }
+
     debouncedSyncPositionFromServer() {
         // Debounce sync calls to prevent redundant requests when multiple events fire
         clearTimeout(this.#syncFromServerTimeout)
@@ -157,4 +181,75 @@ export class ReaderSync {
             this.syncPositionToServer(slug, position, percentage)
         }, 1000) // Wait 1 second after last position change
     }
+
+    async getServerPreferences() {
+        try {
+            const response = await fetch('/reader-preferences', {
+                method: 'GET',
+                headers: {
+                    'Content-Type': 'application/json',
+                }
+            })
+
+            if (response.status === 403) {
+                this.#isAuthenticated = false
+                window.dispatchEvent(new CustomEvent('reader-session-expired'))
+                return null
+            }
+
+            if (response.ok) {
+                return await response.json()
+            }
+
+            return null
+        } catch (error) {
+            console.error('Error fetching reader preferences from server:', error)
+            return null
+        }
+    }
+
+    async syncPreferencesToServer(preferences) {
+        try {
+            const response = await fetch('/reader-preferences', {
+                method: 'PUT',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(preferences)
+            })
+
+            if (response.status === 403) {
+                this.#isAuthenticated = false
+                window.dispatchEvent(new CustomEvent('reader-session-expired'))
+                return
+            }
+
+            if (!response.ok && response.status !== 204) {
+                console.error('Failed to sync reader preferences to server:', response.statusText)
+            }
+        } catch (error) {
+            console.error('Error syncing reader preferences to server:', error)
+        }
+    }
+
+    debouncedSyncPreferencesFromServer() {
+        if (!this.#isAuthenticated) {
+            return
+        }
+
+        clearTimeout(this.#syncPreferencesFromServerTimeout)
+        this.#syncPreferencesFromServerTimeout = setTimeout(async () => {
+            const preferences = await this.getServerPreferences()
+            if (preferences) {
+                window.dispatchEvent(new CustomEvent('reader-preferences-fetched', { detail: preferences }))
+            }
+        }, 100) // Wait 100ms for other events
+    }
+
+    schedulePreferencesUpdate(preferences) {
+        clearTimeout(this.#updatePreferencesTimeout)
+        this.#updatePreferencesTimeout = setTimeout(() => {
+            this.syncPreferencesToServer(preferences)
+        }, 1000) // Wait 1 second after last change, as font size buttons are usually clicked several times in a row
+    }
 }
@@ -0,0 +1,89 @@
+// UpNext offers to mark the document as complete when the end is reached, and then suggests
+// the next one to read: the first one in the reading queue or the next one in its series.
+export class UpNext {
+    #dialog
+    #slug
+    #translations
+    #shown = false
+
+    constructor(slug, translations) {
+        this.#dialog = document.getElementById('end-of-book')
+        this.#slug = slug
+        this.#translations = translations
+
+        if (!this.#dialog) return
+
+        document.getElementById('end-of-book-close').onclick = () => this.#dialog.close()
+        this.#dialog.onclick = (e) => {
+            if (e.target === this.#dialog) {
+                this.#dialog.close()
+            }
+        }
+        document.getElementById('mark-complete').onclick = () => this.#complete()
+    }
+
+    // show opens the dialog, only the first time the end of the document is reached
+    show() {
+        if (!this.#dialog || this.#shown) return
+        this.#shown = true
+
+        this.#dialog.showModal()
+        if (this.#dialog.dataset.completed === 'true') {
+            this.#load()
+        }
+    }
+
+    async #complete() {
+        try {
+            const response = await fetch(`/documents/${this.#slug}/complete`, {
+                method: 'POST',
+                credentials: 'same-origin'
+            })
+            if (response.status === 403) {
+                this.#dialog.close()
+                window.dispatchEvent(new CustomEvent('reader-session-expired'))
+                return
+            }
+            if (!response.ok) {
+                throw new Error(`HTTP error! status: ${response.status}`)
+            }
+        } catch (error) {
+            console.error('Error marking document as complete:', error)
+            return
+        }
+
+        this.#dialog.dataset.completed = 'true'
+        document.getElementById('mark-complete').hidden = true
+        this.#load()
+    }
+
+    async #load() {
+        let next = null
+        try {
+            const response = await fetch(`/documents/${this.#slug}/up-next`, {
+                credentials: 'same-origin'
+            })
+            if (response.status === 200) {
+                next = await response.json()
+            } else if (response.status !== 204) {
+                throw new Error(`HTTP error! status: ${response.status}`)
+            }
+        } catch (error) {
+            console.error('Error getting next document to read:', error)
+            return
+        }
+
+        if (!next) {
+            document.getElementById('up-next-empty').hidden = false
+            return
+        }
+
+        document.getElementById('up-next-reason').textContent = next.reason === 'series'
+            ? this.#translations.up_next_series.replace('%s', next.series)
+            : this.#translations.up_next_queue
+        document.getElementById('up-next-link').href = `/documents/${next.slug}/read`
+        document.getElementById('up-next-title').textContent = next.title
+        document.getElementById('up-next-authors').textContent = (next.authors || []).join(', ')
+        document.getElementById('up-next').hidden = false
+    }
+}
@@ -7,6 +7,8 @@ const [
     { Overlayer },
     { ReaderSync },
     { ReaderToast },
+    { UpNext },
+    { ReadAloud },
 ] = await Promise.all([
     importVersioned('./foliate-js/view.js'),
     importVersioned('./foliate-js/ui/tree.js'),
@@ -14,6 +16,8 @@ const [
     importVersioned('./foliate-js/overlayer.js'),
     importVersioned('./reader-sync.js'),
     importVersioned('./reader-toast.js'),
+    importVersioned('./reader-up-next.js'),
+    importVersioned('./reader-read-aloud.js'),
 ])
 
 document.addEventListener('click', e => {
@@ -37,9 +41,9 @@ const getCSS = ({ spacing, justify, hyphenate, theme, fontSize, fontFamily }) =>
             color: lightblue;
         }
     }
-    body {
-        font-family: ${fontFamily === 'sans-serif' ? 'sans-serif' : 'serif'} !important;
-    }
+    ${fontFamily === 'publisher' ? '' : `body {
+        font-family: ${fontFamily} !important;
+    }`}
     p, li, blockquote, dd {
         line-height: ${spacing};
         text-align: ${justify ? 'justify' : 'start'};
@@ -60,6 +64,9 @@ const getCSS = ({ spacing, justify, hyphenate, theme, fontSize, fontFamily }) =>
     pre {
         white-space: pre-wrap !important;
     }
+    ::highlight(read-aloud) {
+        background-color: rgba(255, 200, 0, .35);
+    }
     aside[epub|type~="endnote"],
     aside[epub|type~="footnote"],
     aside[epub|type~="note"],
@@ -88,15 +95,38 @@ const formatContributor = contributor => Array.isArray(contributor)
     ? listFormat.format(contributor.map(formatOneContributor))
     : formatOneContributor(contributor)
 
+// Same format used by the server for reading times, e.g. "1d 2h 30m"
+const formatDuration = totalMinutes => {
+    const days = Math.floor(totalMinutes / 1440)
+    const hours = Math.floor(totalMinutes % 1440 / 60)
+    const minutes = totalMinutes % 60
+    return days > 0 ? `${days}d ${hours}h ${minutes}m` : `${hours}h ${minutes}m`
+}
+
+// Reading sessions shorter than this are not reported
+const readingSessionMinDuration = 30 * 1000
+// Time without turning the page after which the reader is considered idle
+const readingSessionIdleTimeout = 5 * 60 * 1000
+
 class Reader {
     #tocView
     #footnoteModal
     #footnoteContent
     #toast
+    #upNext
     #sessionExpiredShown = false
     #notLoggedInShown = false
     #sidebarOpening = false
     #skipNextPush = false
+    #readingSession = null
+    #lastActivity = null
+    #percentage = null
+    #applyingPreferences = false
+    #readAloud = null
+    // Position of the sentence being read aloud, and the range shown on screen
+    #spokenPosition = null
+    #visibleRange = null
+    #menu = null
     sync = null
     view = null
     translations = null
@@ -106,7 +136,10 @@ class Reader {
         hyphenate: true,
         theme: 'auto',
         fontSize: 100, // Percentage: 100 = 100%
-        fontFamily: 'serif', // 'serif' or 'sans-serif'
+        fontFamily: 'serif', // 'publisher', 'serif', 'sans-serif' or 'monospace'
+        margin: 7, // Space between columns and around pages, as a percentage of the page width
+        columns: 2, // Maximum number of columns shown side by side
+        flow: 'paginated', // 'paginated' or 'scrolled'
     }
     #defaultFontSize = 100
     #minFontSize = 75
@@ -114,6 +147,41 @@ class Reader {
     #fontSizeStep = 10
     annotations = new Map()
     annotationsByValue = new Map()
+    #startReadingSession() {
+        if (!this.sync.isAuthenticated || this.#readingSession) return
+        this.#lastActivity = new Date()
+        this.#readingSession = {
+            startedAt: this.#lastActivity,
+            startPercentage: this.#percentage,
+        }
+    }
+    #endReadingSession() {
+        const session = this.#readingSession
+        this.#readingSession = null
+        if (!session || !this.sync.isAuthenticated || this.#percentage === null) return
+
+        // Time spent idle is not counted, as the reader may have been left open
+        const endedAt = new Date(Math.min(Date.now(), this.#lastActivity.getTime() + readingSessionIdleTimeout))
+        if (endedAt - session.startedAt < readingSessionMinDuration) return
+
+        this.sync.sendReadingSession(document.getElementById('slug').value, {
+            started_at: session.startedAt.toISOString(),
+            ended_at: endedAt.toISOString(),
+            start_percentage: session.startPercentage ?? this.#percentage,
+            end_percentage: this.#percentage,
+        })
+    }
+    #updateTimeLeft(fraction) {
+        const timeLeft = $('#time-left')
+        const words = parseFloat(document.getElementById('words').value)
+        const wordsPerMinute = parseFloat(document.getElementById('words-per-minute').value)
+        if (fraction === null || !(words > 0) || !(wordsPerMinute > 0)) {
+            timeLeft.textContent = ''
+            return
+        }
+        const minutes = Math.round(words * (1 - fraction) / wordsPerMinute)
+        timeLeft.textContent = this.translations.time_left.replace('%s', formatDuration(minutes))
+    }
     closeSideBar() {
         $('#dimming-overlay').classList.remove('show')
         $('#side-bar').classList.remove('show')
@@ -139,15 +207,8 @@ class Reader {
         this.#applyFontSize()
     }
     #applyFontSize() {
-        window.localStorage.setItem('reader-fontSize', this.style.fontSize)
-        if (this.view?.renderer) {
-            this.view.renderer.setStyles?.(getCSS(this.style))
-        }
-        // Also apply font size to footnote modal
-        const footnoteModal = document.getElementById('footnote-modal')
-        if (footnoteModal) {
-            footnoteModal.style.fontSize = `${this.style.fontSize}%`
-        }
+        this.#render()
+        this.#savePreferences()
         this.#updateFontSizeButtons()
     }
     #updateFontSizeButtons() {
@@ -162,11 +223,7 @@ class Reader {
         }
     }
     #setLineHeight(value) {
-        this.style.spacing = value
-        window.localStorage.setItem('reader-lineHeight', value.toString())
-        if (this.view?.renderer) {
-            this.view.renderer.setStyles?.(getCSS(this.style))
-        }
+        this.#changePreference('spacing', value)
         this.#updateLineHeightButtons()
     }
     #updateLineHeightButtons() {
@@ -197,40 +254,146 @@ class Reader {
         }
     }
     #setFontFamily(value) {
-        this.style.fontFamily = value
-        window.localStorage.setItem('reader-fontFamily', value)
-        if (this.view?.renderer) {
-            this.view.renderer.setStyles?.(getCSS(this.style))
-        }
+        this.#changePreference('fontFamily', value)
         this.#updateFontFamilyButtons()
     }
     #updateFontFamilyButtons() {
         // Early return if buttons aren't initialized
         if (!this.fontFamilyButtons) return
 
-        const serifBtn = this.fontFamilyButtons.serifBtn
-        const sansSerifBtn = this.fontFamilyButtons.sansSerifBtn
+        // Add active class to current selection (CSS handles styling)
+        for (const button of Object.values(this.fontFamilyButtons)) {
+            button.classList.toggle('active', button.dataset.fontFamily === this.style.fontFamily)
+        }
+    }
+    #changePreference(key, value) {
+        this.style[key] = value
+        this.#render()
+        this.#savePreferences()
+    }
+    // Applies the current preferences to the document being read
+    #render() {
+        this.#applyTheme(this.style.theme)
+        // Also apply font size to footnote modal
+        const footnoteModal = document.getElementById('footnote-modal')
+        if (footnoteModal) {
+            footnoteModal.style.fontSize = `${this.style.fontSize}%`
+        }
+        if (!this.view?.renderer) return
 
-        // Return if any button is missing
-        if (!serifBtn || !sansSerifBtn) return
+        this.view.renderer.setStyles?.(getCSS(this.style))
+        this.view.renderer.setAttribute('flow', this.style.flow)
+        this.view.renderer.setAttribute('gap', `${this.style.margin}%`)
+        this.view.renderer.setAttribute('max-column-count', this.style.columns)
+    }
+    // Refreshes the menu so it shows the current preferences
+    #updateControls() {
+        // Selecting a radio item runs its action, which must not be saved as a new change
+        this.#applyingPreferences = true
+        this.#menu.groups.theme.select(this.style.theme)
+        this.#menu.groups.margin.select(this.style.margin)
+        this.#menu.groups.columns.select(this.style.columns)
+        this.#applyingPreferences = false
+
+        this.#menu.groups.continuous.setChecked(this.style.flow === 'scrolled')
+        this.#menu.groups.justify.setChecked(this.style.justify)
+        this.#updateFontSizeButtons()
+        this.#updateLineHeightButtons()
+        this.#updateFontFamilyButtons()
+    }
+    #preferences() {
+        return {
+            font_family: this.style.fontFamily,
+            font_size: this.style.fontSize,
+            line_height: this.style.spacing,
+            margin: this.style.margin,
+            justify: this.style.justify,
+            theme: this.style.theme,
+            flow: this.style.flow,
+            columns: this.style.columns,
+        }
+    }
+    #loadLocalPreferences() {
+        const storage = window.localStorage
+        this.style.theme = storage.getItem('reader-theme') || 'auto'
+        this.style.flow = storage.getItem('reader-continuous') === 'true' ? 'scrolled' : 'paginated'
 
-        const current = this.style.fontFamily
+        const savedFontSize = parseInt(storage.getItem('reader-fontSize'))
+        if (savedFontSize && savedFontSize >= this.#minFontSize && savedFontSize <= this.#maxFontSize) {
+            this.style.fontSize = savedFontSize
+        }
 
-        // Remove active class from all buttons
-        serifBtn.classList.remove('active')
-        sansSerifBtn.classList.remove('active')
+        this.style.spacing = parseFloat(storage.getItem('reader-lineHeight') || '1.4')
+        this.style.fontFamily = storage.getItem('reader-fontFamily') || 'serif'
+        this.style.justify = storage.getItem('reader-justify') !== 'false'
 
-        // Add active class to current selection (CSS handles styling)
-        if (current === 'serif') {
-            serifBtn.classList.add('active')
-        } else if (current === 'sans-serif') {
-            sansSerifBtn.classList.add('active')
+        const savedMargin = parseInt(storage.getItem('reader-margin'))
+        if (!Number.isNaN(savedMargin)) {
+            this.style.margin = savedMargin
+        }
+        const savedColumns = parseInt(storage.getItem('reader-columns'))
+        if (savedColumns) {
+            this.style.columns = savedColumns
         }
     }
-    #applyTheme(theme) {
-        // Save theme preference to localStorage
-        window.localStorage.setItem('reader-theme', theme)
+    #storeLocalPreferences() {
+        const storage = window.localStorage
+        storage.setItem('reader-theme', this.style.theme)
+        storage.setItem('reader-continuous', this.style.flow === 'scrolled')
+        storage.setItem('reader-fontSize', this.style.fontSize)
+        storage.setItem('reader-lineHeight', this.style.spacing.toString())
+        storage.setItem('reader-fontFamily', this.style.fontFamily)
+        storage.setItem('reader-justify', this.style.justify)
+        storage.setItem('reader-margin', this.style.margin)
+        storage.setItem('reader-columns', this.style.columns)
+    }
+    // Keeps the preferences in this device and, for logged in users, in the server so they are the same in all their devices
+    #savePreferences() {
+        if (this.#applyingPreferences) return
 
+        this.#storeLocalPreferences()
+        window.localStorage.setItem('reader-preferences-updated', new Date().toISOString())
+        if (this.sync.isAuthenticated) {
+            this.sync.schedulePreferencesUpdate(this.#preferences())
+        }
+    }
+    // Uses the preferences stored in the server if they were changed after the ones in this device,
+    // otherwise sends the ones in this device to the server. Returns whether the preferences changed.
+    #mergeServerPreferences(preferences) {
+        const storage = window.localStorage
+        const localUpdated = storage.getItem('reader-preferences-updated')
+        if (!preferences.updated || (localUpdated && new Date(localUpdated) >= new Date(preferences.updated))) {
+            if (localUpdated && localUpdated !== preferences.updated) {
+                this.sync.schedulePreferencesUpdate(this.#preferences())
+            }
+            return false
+        }
+
+        storage.setItem('reader-preferences-updated', preferences.updated)
+        const changed = Object.entries(this.#preferences()).some(([key, value]) => preferences[key] !== value)
+        if (!changed) return false
+
+        Object.assign(this.style, {
+            fontFamily: preferences.font_family,
+            fontSize: preferences.font_size,
+            spacing: preferences.line_height,
+            margin: preferences.margin,
+            justify: preferences.justify,
+            theme: preferences.theme,
+            flow: preferences.flow,
+            columns: preferences.columns,
+        })
+        this.#storeLocalPreferences()
+        return true
+    }
+    #onServerPreferences(preferences) {
+        if (!this.#mergeServerPreferences(preferences)) return
+
+        this.#updateControls()
+        this.#render()
+        this.#toast.show('success', this.translations.preferences_updated_from_server)
+    }
+    #applyTheme(theme) {
         // Apply theme to the main document using system color-scheme
         const html = document.documentElement
         html.dataset.theme = theme
@@ -286,9 +449,13 @@ class Reader {
         // Initialize sync helper
         this.sync = new ReaderSync(isAuthenticated)
 
+        // Initialize the end of document dialog, only available to logged in users
+        this.#upNext = new UpNext(document.getElementById('slug').value, this.translations)
+
         // Listen for sync events
         window.addEventListener('reader-session-expired', () => this.showSessionExpired())
         window.addEventListener('reader-position-updated', () => this.showPositionUpdated())
+        window.addEventListener('reader-preferences-fetched', e => this.#onServerPreferences(e.detail))
 
         // Show not logged in notification if needed
         if (!isAuthenticated) {
@@ -384,18 +551,30 @@ class Reader {
        sansSerifBtn.innerHTML = '<svg class="icon" width="24" height="24" aria-hidden="true"><text x="12" y="18" text-anchor="middle" font-size="16" font-weight="bold" font-family="sans-serif">Aa</text></svg>'
        sansSerifBtn.addEventListener('click', () => this.#setFontFamily('sans-serif'))
 
-       fontFamilyControls.append(serifBtn, sansSerifBtn)
-       this.fontFamilyButtons = { serifBtn, sansSerifBtn }
+       const monospaceBtn = document.createElement('button')
+       monospaceBtn.setAttribute('data-font-family', 'monospace')
+       monospaceBtn.setAttribute('aria-label', t.monospace)
+       monospaceBtn.title = t.monospace
+       monospaceBtn.innerHTML = '<svg class="icon" width="24" height="24" aria-hidden="true"><text x="12" y="18" text-anchor="middle" font-size="16" font-weight="bold" font-family="monospace">Aa</text></svg>'
+       monospaceBtn.addEventListener('click', () => this.#setFontFamily('monospace'))
+
+       // Keeps the fonts embedded in the document
+       const publisherBtn = document.createElement('button')
+       publisherBtn.setAttribute('data-font-family', 'publisher')
+       publisherBtn.setAttribute('aria-label', t.publisher)
+       publisherBtn.title = t.publisher
+       publisherBtn.innerHTML = '<svg class="icon" width="24" height="24" aria-hidden="true"><text x="12" y="18" text-anchor="middle" font-size="16" font-weight="bold" font-style="italic" font-family="serif">Aa</text></svg>'
+       publisherBtn.addEventListener('click', () => this.#setFontFamily('publisher'))
+
+       fontFamilyControls.append(publisherBtn, serifBtn, sansSerifBtn, monospaceBtn)
+       this.fontFamilyButtons = { publisherBtn, serifBtn, sansSerifBtn, monospaceBtn }
 
        const menu = createMenu([
             {
                 name: 'continuous',
                 label: t.continuous,
                 type: 'checkbox',
-                onclick: checked => {
-                    window.localStorage.setItem('reader-continuous', checked)
-                    this.view?.renderer.setAttribute('flow', checked ? 'scrolled' : 'paginated')
-                },
+                onclick: checked => this.#changePreference('flow', checked ? 'scrolled' : 'paginated'),
             },
             {
                 type: 'separator',
@@ -409,13 +588,7 @@ class Reader {
                     [t.light, 'light'],
                     [t.dark, 'dark'],
                 ],
-                onclick: value => {
-                    this.style.theme = value
-                    this.#applyTheme(value)
-                    if (this.view?.renderer) {
-                        this.view.renderer.setStyles?.(getCSS(this.style))
-                    }
-                },
+                onclick: value => this.#changePreference('theme', value),
             },
             {
                 type: 'separator',
@@ -441,8 +614,45 @@ class Reader {
                 type: 'custom',
                 content: fontFamilyControls
             },
+            {
+                type: 'separator',
+            },
+            {
+                name: 'justify',
+                label: t.justify,
+                type: 'checkbox',
+                onclick: checked => this.#changePreference('justify', checked),
+            },
+            {
+                type: 'separator',
+            },
+            {
+                name: 'margin',
+                label: t.margins,
+                type: 'radio',
+                items: [
+                    [t.narrow_margins, 3],
+                    [t.regular_margins, 7],
+                    [t.wide_margins, 12],
+                ],
+                onclick: value => this.#changePreference('margin', value),
+            },
+            {
+                type: 'separator',
+            },
+            {
+                name: 'columns',
+                label: t.columns,
+                type: 'radio',
+                items: [
+                    [t.one_column, 1],
+                    [t.two_columns, 2],
+                ],
+                onclick: value => this.#changePreference('columns', value),
+            },
         ])
         menu.element.classList.add('menu')
+        this.#menu = menu
 
         // Store references to font size elements for later removal if needed
         this.fontSizeMenuItem = menu.groups.fontSize?.element
@@ -459,6 +669,9 @@ class Reader {
         // The separator is the element right before the fontFamily menu item
         this.fontFamilySeparator = this.fontFamilyMenuItem?.previousElementSibling
 
+        // Text layout controls don't work for pre-paginated content either
+        this.layoutMenuItems = ['justify', 'margin', 'columns'].map(name => menu.groups[name].element)
+
         $('#menu-button').append(menu.element)
         $('#menu-button > button').addEventListener('click', () => {
             const wasOpen = menu.element.classList.contains('show')
@@ -487,35 +700,17 @@ class Reader {
         })
         menuObserver.observe(menu.element, { attributes: true, attributeFilter: ['class'] })
 
-        // Load saved theme from localStorage or default to 'auto'
-        const storage = window.localStorage
-        const savedTheme = storage.getItem('reader-theme') || 'auto'
-        this.style.theme = savedTheme
-        menu.groups.theme.select(savedTheme)
-        this.#applyTheme(savedTheme)
-
-        // Load saved continuous mode from localStorage or default to false (paginated mode)
-        const savedContinuous = storage.getItem('reader-continuous') === 'true'
-        menu.groups.continuous.setChecked(savedContinuous)
-
-        // Load saved font size from localStorage or default to 100%
-        const savedFontSize = parseInt(storage.getItem('reader-fontSize'))
-        if (savedFontSize && savedFontSize >= this.#minFontSize && savedFontSize <= this.#maxFontSize) {
-            this.style.fontSize = savedFontSize
+        // Load preferences saved in this device, replacing them with the ones saved in the server
+        // if the user changed them later in another device
+        this.#loadLocalPreferences()
+        const serverPreferences = document.getElementById('reader-preferences')
+        if (isAuthenticated && serverPreferences) {
+            this.#mergeServerPreferences(JSON.parse(serverPreferences.textContent))
         }
 
-        // Load saved line height from localStorage or default to 1.4
-        const savedLineHeight = storage.getItem('reader-lineHeight') || '1.4'
-        this.style.spacing = parseFloat(savedLineHeight)
-
-        // Load saved font family from localStorage or default to 'serif'
-        const savedFontFamily = storage.getItem('reader-fontFamily') || 'serif'
-        this.style.fontFamily = savedFontFamily
-
         // Initialize button states
-        this.#updateFontSizeButtons()
-        this.#updateLineHeightButtons()
-        this.#updateFontFamilyButtons()
+        this.#updateControls()
+        this.#applyTheme(this.style.theme)
 
         // Initialize footnote modal
         this.#setupFootnoteModal()
@@ -528,6 +723,7 @@ class Reader {
                 }, 500)
 
                 this.sync.debouncedSyncPositionFromServer()
+                this.sync.debouncedSyncPreferencesFromServer()
             }
         })
 
@@ -539,6 +735,7 @@ class Reader {
                 }, 500)
 
                 this.sync.debouncedSyncPositionFromServer()
+                this.sync.debouncedSyncPreferencesFromServer()
             }
         })
     }
@@ -610,10 +807,25 @@ class Reader {
             this.lineHeightSeparator?.remove()
             this.fontFamilyMenuItem?.remove()
             this.fontFamilySeparator?.remove()
+            for (const item of this.layoutMenuItems) {
+                item.previousElementSibling?.remove()
+                item.remove()
+            }
         }
         this.view.addEventListener('load', this.#onLoad.bind(this))
         this.view.addEventListener('relocate', this.#onRelocate.bind(this))
 
+        // Report the time spent reading, so real reading times and speed can be calculated
+        this.#startReadingSession()
+        document.addEventListener('visibilitychange', () => {
+            if (document.hidden) {
+                this.#endReadingSession()
+            } else {
+                this.#startReadingSession()
+            }
+        })
+        window.addEventListener('pagehide', () => this.#endReadingSession())
+
         // Add keyboard listener directly to the view
         this.view.addEventListener('keydown', this.#handleKeydown.bind(this))
 
@@ -649,11 +861,10 @@ class Reader {
         document.body.removeChild($('#spinner-container'))
         document.body.removeChild($('#error-icon-container'))
 
-        this.view.renderer.setStyles?.(getCSS(this.style))
-
-        // Apply saved continuous mode state
-        const savedContinuous = storage.getItem('reader-continuous') === 'true'
-        this.view.renderer.setAttribute('flow', savedContinuous ? 'scrolled' : 'paginated')
+        this.#render()
+        if (!isPrePaginated) {
+            this.#setupReadAloud()
+        }
 
         $('#header-bar').style.visibility = 'visible'
         $('#nav-bar').style.visibility = 'visible'
@@ -748,6 +959,119 @@ class Reader {
             this.view.goRight()
         }
     }
+    #setupReadAloud() {
+        if (!ReadAloud.supported) return
+
+        const t = this.translations
+        const button = $('#read-aloud-button')
+        const bar = $('#read-aloud-bar')
+        const playButton = $('#read-aloud-play')
+        const rateSelect = $('#read-aloud-rate')
+        const voiceSelect = $('#read-aloud-voice')
+
+        const readAloud = new ReadAloud(this.view, document.getElementById('language').value, {
+            onSentence: range => this.#onSentence(range),
+            onStateChange: () => update(),
+        })
+        this.#readAloud = readAloud
+
+        const update = () => {
+            bar.hidden = !readAloud.active
+            button.setAttribute('aria-pressed', readAloud.active ? 'true' : 'false')
+            const label = readAloud.playing ? t.read_aloud_pause : t.read_aloud_play
+            playButton.setAttribute('aria-label', label)
+            playButton.title = label
+            playButton.querySelector('.read-aloud-pause-icon').hidden = !readAloud.playing
+            playButton.querySelector('.read-aloud-play-icon').hidden = readAloud.playing
+            if ('mediaSession' in navigator) {
+                navigator.mediaSession.playbackState = !readAloud.active ? 'none' : readAloud.playing ? 'playing' : 'paused'
+            }
+        }
+
+        // Voices are loaded asynchronously by some browsers
+        const fillVoices = () => {
+            const current = readAloud.voice
+            voiceSelect.replaceChildren(...readAloud.voices().map(voice =>
+                new Option(`${voice.name} (${voice.lang})`, voice.voiceURI, false, voice === current)))
+        }
+        fillVoices()
+        window.speechSynthesis.addEventListener('voiceschanged', fillVoices)
+        voiceSelect.addEventListener('change', () => readAloud.setVoice(voiceSelect.value))
+
+        rateSelect.value = String(readAloud.rate)
+        rateSelect.addEventListener('change', () => readAloud.setRate(parseFloat(rateSelect.value)))
+
+        button.hidden = false
+        button.addEventListener('click', () => readAloud.active ? readAloud.stop() : this.#startReadingAloud())
+        playButton.addEventListener('click', () => readAloud.playing ? readAloud.pause() : readAloud.resume())
+        $('#read-aloud-prev').addEventListener('click', () => readAloud.prev())
+        $('#read-aloud-next').addEventListener('click', () => readAloud.next())
+        $('#read-aloud-stop').addEventListener('click', () => readAloud.stop())
+
+        // Lets headphones and lock screens control reading aloud, useful when listening with the screen off
+        if ('mediaSession' in navigator) {
+            const handlers = {
+                play: () => readAloud.resume(),
+                pause: () => readAloud.pause(),
+                previoustrack: () => readAloud.prev(),
+                nexttrack: () => readAloud.next(),
+                stop: () => readAloud.stop(),
+            }
+            for (const [action, handler] of Object.entries(handlers)) {
+                try {
+                    navigator.mediaSession.setActionHandler(action, handler)
+                } catch {
+                    // Not all browsers support every action
+                }
+            }
+        }
+        window.addEventListener('pagehide', () => readAloud.stop())
+    }
+    async #startReadingAloud() {
+        if (!this.#readAloud.hasLanguageVoices()) {
+            this.#toast.show('warning', this.translations.read_aloud_no_voices)
+        }
+        if ('mediaSession' in navigator && 'MediaMetadata' in window) {
+            navigator.mediaSession.metadata = new MediaMetadata({ title: document.title })
+        }
+        await this.#readAloud.start(this.#savedRange() ?? this.#visibleRange)
+    }
+    // Returns the range of the saved reading position if it is on screen, so reading aloud resumes
+    // from the sentence where it was stopped instead of from the top of the page
+    #savedRange() {
+        const { position } = this.sync.getLocalPosition(document.getElementById('slug').value)
+        if (!position || !this.#visibleRange) return null
+
+        try {
+            const { index, anchor } = this.view.resolveCFI(position)
+            const content = this.view.renderer.getContents().find(content => content.index === index)
+            const range = content ? anchor(content.doc) : null
+            if (range?.startContainer && this.#visibleRange.isPointInRange(range.startContainer, range.startOffset)) {
+                return range
+            }
+        } catch (error) {
+            console.error('Error resolving reading position:', error)
+        }
+        return null
+    }
+    // Saves the sentence being read aloud as the reading position, so it is resumed from there in any device
+    #onSentence(range) {
+        const index = this.view.renderer.getContents()[0]?.index
+        this.#spokenPosition = this.view.getCFI(index, range)
+        this.#savePosition(this.#spokenPosition, this.#percentage ?? 0)
+    }
+    #savePosition(position, percentage) {
+        const slug = document.getElementById('slug').value
+        window.localStorage.setItem(slug, JSON.stringify({
+            position,
+            percentage,
+            updated: new Date().toISOString()
+        }))
+
+        if (this.sync.isAuthenticated && !this.#sidebarOpening && !this.#skipNextPush) {
+            this.sync.schedulePositionUpdate(slug, position, percentage)
+        }
+    }
     #onLoad({ detail: { doc } }) {
         doc.addEventListener('keydown', this.#handleKeydown.bind(this))
     }
@@ -838,22 +1162,33 @@ class Reader {
         }, 0)
     }
     #onRelocate({ detail }) {
-        const storage = window.localStorage
-        const slug = document.getElementById('slug').value
+        this.#visibleRange = detail.range
 
         const frac = typeof detail.fraction === 'number' && !Number.isNaN(detail.fraction)
             ? Math.min(1, Math.max(0, detail.fraction))
             : null
         const syncPct = frac !== null ? Math.round(frac * 100) : 0
-        storage.setItem(slug, JSON.stringify({
-            position: detail.cfi,
-            percentage: syncPct,
-            updated: new Date().toISOString()
-        }))
 
-        if (this.sync.isAuthenticated && !this.#sidebarOpening && !this.#skipNextPush) {
-            this.sync.schedulePositionUpdate(slug, detail.cfi, syncPct)
+        if (this.#readingSession) {
+            const now = new Date()
+            if (now - this.#lastActivity > readingSessionIdleTimeout) {
+                // The reader came back after being idle, so a new session starts
+                this.#endReadingSession()
+                this.#startReadingSession()
+            }
+            this.#readingSession.startPercentage ??= syncPct
+            this.#lastActivity = now
+        }
+        // Reaching the end by turning pages, not by opening the document there, offers what to read next
+        const atEnd = frac === 1 || (detail.location && detail.location.next >= detail.location.total)
+        if (atEnd && this.#percentage !== null && this.#percentage < 100 && this.sync.isAuthenticated) {
+            this.#upNext.show()
         }
+        this.#percentage = syncPct
+        this.#updateTimeLeft(frac)
+        // Pages are turned while reading aloud, but the position is the sentence being read, not the page start
+        const position = this.#readAloud?.playing && this.#spokenPosition ? this.#spokenPosition : detail.cfi
+        this.#savePosition(position, syncPct)
 
         const { fraction, location, tocItem, pageItem } = detail
         const percent = percentFormat.format(fraction)
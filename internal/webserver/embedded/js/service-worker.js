@@ -0,0 +1,307 @@
+// Keeps Coreander usable without a connection:
+// - Static files (/css, /js, /images) are cached as they are requested. They are versioned with ?v=,
+//   so a cached copy is never stale, and the caches of previous versions are removed on activation.
+// - Documents pinned for offline use are stored in their own cache, which survives new versions, and
+//   served from it only when the network is not available.
+// - Reading positions saved while offline are queued and sent when the connection is back, as long as
+//   the same user is still logged in.
+// - Signing out, or a different user logging in, removes the queued positions and the pinned documents.
+
+const version = new URL(self.location).searchParams.get('v') || 'dev'
+const versionParam = version === 'dev' ? '' : `?v=${version}`
+const SHELL_CACHE = `coreander-shell-${version}`
+const DOCUMENTS_CACHE = 'coreander-documents'
+const QUEUE_DB = 'coreander-offline'
+const QUEUE_STORE = 'positions'
+const SESSION_STORE = 'session'
+
+// Files needed to render the offline page and the reader, requested in advance so they are available
+// even if the user never opened the reader while online
+const shell = [
+    '/offline',
+    '/css/bootstrap.min.css',
+    '/css/display.css',
+    '/css/bootstrap-icons.min.css',
+    '/css/reader.css',
+    '/js/bootstrap.bundle.min.js',
+    '/js/htmx.min.js',
+    '/js/color-mode-toggler.js',
+    '/js/keyboard-shortcuts.js',
+    '/js/share-recipients.js',
+    '/js/feedback.js',
+    '/js/offline.js',
+    '/js/asset-version.js',
+    '/js/menu.js',
+    '/js/reader.js',
+    '/js/reader-sync.js',
+    '/js/reader-toast.js',
+    '/js/reader-up-next.js',
+    '/js/reader-read-aloud.js',
+    '/js/foliate-js/view.js',
+    '/js/foliate-js/epub.js',
+    '/js/foliate-js/epubcfi.js',
+    '/js/foliate-js/paginator.js',
+    '/js/foliate-js/fixed-layout.js',
+    '/js/foliate-js/overlayer.js',
+    '/js/foliate-js/progress.js',
+    '/js/foliate-js/text-walker.js',
+    '/js/foliate-js/search.js',
+    '/js/foliate-js/footnotes.js',
+    '/js/foliate-js/tts.js',
+    '/js/foliate-js/ui/tree.js',
+    '/js/foliate-js/vendor/zip.js',
+    '/js/foliate-js/vendor/fflate.js',
+].map(path => path === '/offline' ? path : path + versionParam)
+
+self.addEventListener('install', event => {
+    event.waitUntil((async () => {
+        const cache = await caches.open(SHELL_CACHE)
+        // Files are added one by one, so a missing optional module doesn't prevent the rest from being cached
+        await Promise.all(shell.map(url => cache.add(url).catch(error => console.warn(`Could not cache ${url}:`, error))))
+        await self.skipWaiting()
+    })())
+})
+
+self.addEventListener('activate', event => {
+    event.waitUntil((async () => {
+        const names = await caches.keys()
+        await Promise.all(names
+            .filter(name => name.startsWith('coreander-shell-') && name !== SHELL_CACHE)
+            .map(name => caches.delete(name)))
+        await self.clients.claim()
+        await replayPositions()
+    })())
+})
+
+self.addEventListener('fetch', event => {
+    const { request } = event
+    const url = new URL(request.url)
+
+    if (request.method === 'DELETE' && url.pathname === '/sessions') {
+        event.respondWith(signOut(request))
+        return
+    }
+
+    if (request.method === 'PUT' && /^\/documents\/[^/]+\/position$/.test(url.pathname)) {
+        event.respondWith(savePosition(request))
+        return
+    }
+
+    if (request.method !== 'GET') return
+
+    if (url.origin === self.location.origin && /^\/(css|js|images)\//.test(url.pathname)) {
+        event.respondWith(staticFile(request))
+        return
+    }
+
+    if (/^\/documents\/[^/]+\/(download|cover)$/.test(url.pathname)) {
+        event.respondWith(pinnedFallback(request))
+        return
+    }
+
+    if (request.mode === 'navigate') {
+        event.respondWith(networkFirst(request))
+    }
+})
+
+self.addEventListener('message', event => {
+    if (event.data?.type === 'session') {
+        sessionUpdate = setUser(String(event.data.user)).catch(error => console.error('Error updating session:', error))
+        event.waitUntil(sessionUpdate)
+    }
+    if (event.data?.type === 'replay-positions') {
+        event.waitUntil(replayPositions())
+    }
+})
+
+self.addEventListener('sync', event => {
+    if (event.tag === 'replay-positions') {
+        event.waitUntil(replayPositions())
+    }
+})
+
+// Documents may be requested from the address configured as FQDN, so they are cached by path
+const documentCacheKey = url => {
+    const { pathname, search } = new URL(url, self.location.origin)
+    return new URL(pathname + search, self.location.origin).toString()
+}
+
+// Without a version (development builds) files may change at any time, so the network is tried first
+async function staticFile(request) {
+    const cache = await caches.open(SHELL_CACHE)
+    if (versionParam) {
+        const cached = await cache.match(request)
+        if (cached) return cached
+    }
+
+    try {
+        const response = await fetch(request)
+        if (response.ok) {
+            cache.put(request, response.clone())
+        }
+        return response
+    } catch (error) {
+        const cached = await cache.match(request)
+        if (cached) return cached
+        throw error
+    }
+}
+
+// Downloads and covers are requested to the server while online, so access and removals are always checked
+async function pinnedFallback(request) {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    try {
+        const response = await fetch(request)
+        // A document removed from the library must not be kept available offline
+        const download = /^\/documents\/([^/]+)\/download$/.exec(new URL(request.url).pathname)
+        if (download && response.status === 404) {
+            await unpin(cache, download[1])
+        }
+        return response
+    } catch (error) {
+        const cached = await cache.match(documentCacheKey(request.url))
+        if (cached) return cached
+        throw error
+    }
+}
+
+// Removes everything pinned for a document, as offline.js stores it
+async function unpin(cache, slug) {
+    const keys = await cache.keys()
+    await Promise.all(keys
+        .filter(key => {
+            const { pathname } = new URL(key.url)
+            return pathname.startsWith(`/documents/${slug}/`) || pathname === `/offline-documents/${slug}`
+        })
+        .map(key => cache.delete(key)))
+}
+
+async function networkFirst(request) {
+    const cache = await caches.open(DOCUMENTS_CACHE)
+    try {
+        const response = await fetch(request)
+        // Keep the reader page of pinned documents up to date, as it includes the user's current preferences
+        if (response.ok && await cache.match(request, { ignoreSearch: true })) {
+            cache.put(new URL(request.url).pathname, response.clone())
+        }
+        return response
+    } catch (error) {
+        const cached = await cache.match(request, { ignoreSearch: true })
+        if (cached) return cached
+        const offline = await caches.match('/offline')
+        if (offline) return offline
+        throw error
+    }
+}
+
+// Whatever was saved offline belongs to the user signing out, so it is removed even if the request fails
+async function signOut(request) {
+    await forget().catch(error => console.error('Error removing offline data:', error))
+    return fetch(request)
+}
+
+async function forget() {
+    await Promise.all([
+        withQueue('readwrite', store => store.clear()),
+        withQueue('readwrite', store => store.clear(), SESSION_STORE),
+        caches.delete(DOCUMENTS_CACHE),
+    ])
+}
+
+let sessionUpdate = Promise.resolve()
+
+// Pages report the logged in user, so what a previous one saved offline is removed as soon as another one logs in
+async function setUser(user) {
+    const previous = await currentUser()
+    if (previous === user) return
+    if (previous) {
+        await forget()
+    }
+    await withQueue('readwrite', store => store.put(user, 'user'), SESSION_STORE)
+}
+
+const currentUser = () => withQueue('readonly', store => store.get('user'), SESSION_STORE)
+
+async function savePosition(request) {
+    const body = await request.clone().json().catch(() => null)
+    try {
+        const response = await fetch(request)
+        // A position saved while online is newer than any queued one, which must not be sent afterwards
+        if (response.ok) {
+            await dequeuePosition(request.url)
+            replayPositions()
+        }
+        return response
+    } catch (error) {
+        const user = await currentUser()
+        if (!body || !user) throw error
+        await queuePosition(request.url, { user, body: { ...body, updated: body.updated || new Date().toISOString() } })
+        self.registration.sync?.register('replay-positions').catch(() => {})
+        return new Response(null, { status: 202 })
+    }
+}
+
+function openQueue() {
+    return new Promise((resolve, reject) => {
+        const open = indexedDB.open(QUEUE_DB, 2)
+        open.onupgradeneeded = () => {
+            // Positions queued by previous versions don't say whose they are, so they are discarded
+            if (open.result.objectStoreNames.contains(QUEUE_STORE)) {
+                open.result.deleteObjectStore(QUEUE_STORE)
+            }
+            open.result.createObjectStore(QUEUE_STORE)
+            open.result.createObjectStore(SESSION_STORE)
+        }
+        open.onsuccess = () => resolve(open.result)
+        open.onerror = () => reject(open.error)
+    })
+}
+
+async function withQueue(mode, callback, storeName = QUEUE_STORE) {
+    const db = await openQueue()
+    return new Promise((resolve, reject) => {
+        const transaction = db.transaction(storeName, mode)
+        const result = callback(transaction.objectStore(storeName))
+        transaction.oncomplete = () => resolve(result?.result)
+        transaction.onerror = () => reject(transaction.error)
+    })
+}
+
+// Only the last position of each document is kept, keyed by its URL, along with the user who saved it
+const queuePosition = (url, entry) => withQueue('readwrite', store => store.put(entry, url))
+const dequeuePosition = url => withQueue('readwrite', store => store.delete(url))
+
+let replaying = null
+
+function replayPositions() {
+    // Several events may ask for a replay at the same time, run only one
+    replaying ??= (async () => {
+        try {
+            await sessionUpdate
+            const user = await currentUser()
+            const urls = await withQueue('readonly', store => store.getAllKeys())
+            for (const url of urls) {
+                const entry = await withQueue('readonly', store => store.get(url))
+                // The browser may be logged in as someone else now, who must not get these positions
+                if (!user || entry?.user !== user) {
+                    await dequeuePosition(url)
+                    continue
+                }
+                const response = await fetch(url, {
+                    method: 'PUT',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify(entry.body),
+                })
+                // Keep the position if the session expired, so it is sent after the same user logs in again
+                if (response.ok || (response.status >= 400 && response.status < 500 && response.status !== 403)) {
+                    await dequeuePosition(url)
+                }
+            }
+        } catch (error) {
+            // Still offline, positions stay queued for the next attempt
+        } finally {
+            replaying = null
+        }
+    })()
+    return replaying
+}
@@ -0,0 +1,83 @@
+package webserver
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+const (
+	// GoalReminderCheckInterval is how often reading goals are checked to find users falling behind
+	GoalReminderCheckInterval = 24 * time.Hour
+	// GoalReminderInterval is the minimum time between two reminders about the same goal
+	GoalReminderInterval = 7 * 24 * time.Hour
+)
+
+type goalsReminderRepository interface {
+	PendingReminders(now time.Time, interval time.Duration) ([]model.GoalProgress, error)
+	MarkReminded(goalID uint, remindedAt time.Time) error
+}
+
+// GoalReminder periodically emails users who enabled reminders for their reading goals
+// when they fall behind the pace needed to reach them.
+type GoalReminder struct {
+	goalsRepository goalsReminderRepository
+	sender          Sender
+	composer        *mailer.Composer
+}
+
+// NewGoalReminder creates a reminder which renders emails using the views of the passed app
+func NewGoalReminder(goalsRepository goalsReminderRepository, sender Sender, app *fiber.App) *GoalReminder {
+	return &GoalReminder{
+		goalsRepository: goalsRepository,
+		sender:          sender,
+		composer:        mailer.NewComposer(app.Config().Views, translator),
+	}
+}
+
+// Start checks goals right away and then every GoalReminderCheckInterval until the process exits.
+// It does nothing if email sending is not configured.
+func (g *GoalReminder) Start() {
+	if _, ok := g.sender.(*infrastructure.NoEmail); ok {
+		return
+	}
+	go g.run()
+}
+
+func (g *GoalReminder) run() {
+	g.Remind(time.Now())
+	ticker := time.NewTicker(GoalReminderCheckInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		g.Remind(now)
+	}
+}
+
+// Remind sends an email for every goal whose user is behind pace at the given time
+// and has not been reminded about it in the last GoalReminderInterval.
+func (g *GoalReminder) Remind(now time.Time) {
+	pending, err := g.goalsRepository.PendingReminders(now, GoalReminderInterval)
+	if err != nil {
+		return
+	}
+
+	for _, goal := range pending {
+		message, err := g.composer.Compose("goal-reminder", goal.User.Language, fiber.Map{
+			"Goal": goal,
+		}, "You are falling behind your reading goal")
+		if err != nil {
+			log.Printf("error rendering reading goal reminder email: %s\n", err)
+			continue
+		}
+
+		if err := g.sender.Send(goal.User.Email, message); err != nil {
+			log.Printf("error sending reading goal reminder to %s: %s\n", goal.User.Email, err)
+			continue
+		}
+		g.goalsRepository.MarkReminded(goal.ID, now)
+	}
+}
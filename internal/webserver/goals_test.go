@@ -0,0 +1,133 @@
+package webserver_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestReadingGoals(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	smtpMock := &infrastructure.SMTPMock{}
+	app := bootstrapApp(db, smtpMock, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+
+	setGoal := func(t *testing.T, values url.Values) *http.Response {
+		t.Helper()
+
+		response, err := postRequest(values, adminCookie, app, "/users/admin/goals", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return response
+	}
+
+	t.Run("Users can set reading goals and see their progress in the home page", func(t *testing.T) {
+		response, err := postRequest(nil, adminCookie, app, "/documents/"+testDocSlug+"/complete", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		response = setGoal(t, url.Values{"period": {model.GoalPeriodYear}, "unit": {model.GoalUnitDocuments}, "target": {"12"}})
+		mustReturnStatus(response, http.StatusOK, t)
+		body, _ := io.ReadAll(response.Body)
+		if !strings.Contains(string(body), "1 / 12") {
+			t.Error("Expected goal progress to be listed")
+		}
+
+		response, err = getRequest(adminCookie, app, "/", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		if got := strings.Join(strings.Fields(doc.Find("#reading-goals .goal-progress").Text()), " "); got != "1 / 12 documents" {
+			t.Errorf("Expected home page to show '1 / 12 documents', got '%s'", got)
+		}
+	})
+
+	t.Run("Setting a goal for the same period replaces the existing one", func(t *testing.T) {
+		mustReturnStatus(setGoal(t, url.Values{"period": {model.GoalPeriodYear}, "unit": {model.GoalUnitWords}, "target": {"1000000"}}), http.StatusOK, t)
+
+		var goals []model.ReadingGoal
+		db.Find(&goals)
+		if len(goals) != 1 || goals[0].Unit != model.GoalUnitWords || goals[0].Target != 1000000 {
+			t.Errorf("Expected a single words goal, got %+v", goals)
+		}
+	})
+
+	t.Run("Invalid goals are rejected", func(t *testing.T) {
+		mustReturnStatus(setGoal(t, url.Values{"period": {"week"}, "unit": {model.GoalUnitWords}, "target": {"10"}}), http.StatusBadRequest, t)
+		mustReturnStatus(setGoal(t, url.Values{"period": {model.GoalPeriodMonth}, "unit": {model.GoalUnitWords}, "target": {"0"}}), http.StatusBadRequest, t)
+	})
+
+	t.Run("Users cannot manage other users' goals", func(t *testing.T) {
+		response, err := getRequest(adminCookie, app, "/users/regular/goals", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		response, err = postRequest(url.Values{"period": {model.GoalPeriodYear}, "unit": {model.GoalUnitDocuments}, "target": {"1"}}, adminCookie, app, "/users/regular/goals", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+
+	t.Run("Users behind pace with reminders enabled are emailed once", func(t *testing.T) {
+		mustReturnStatus(setGoal(t, url.Values{"period": {model.GoalPeriodYear}, "unit": {model.GoalUnitDocuments}, "target": {"100"}, "reminders": {"on"}}), http.StatusOK, t)
+
+		reminder := webserver.NewGoalReminder(&model.GoalRepository{DB: db}, smtpMock, app)
+		endOfYear := time.Date(time.Now().Year(), time.December, 31, 0, 0, 0, 0, time.Local)
+
+		smtpMock.Wg.Add(1)
+		reminder.Remind(endOfYear)
+		smtpMock.Wg.Wait()
+		if !smtpMock.CalledSend() || !strings.Contains(smtpMock.LastBody, "So far you have read 1 of 100 documents") {
+			t.Errorf("Expected a reminder to be sent, got '%s'", smtpMock.LastBody)
+		}
+
+		var goal model.ReadingGoal
+		db.First(&goal)
+		if goal.RemindedAt == nil {
+			t.Fatal("Expected reminder date to be stored")
+		}
+
+		// A second reminder within the interval would make Send to be called without a matching Wg.Add, panicking
+		reminder.Remind(endOfYear.Add(time.Hour))
+	})
+
+	t.Run("Goals can be removed", func(t *testing.T) {
+		var goal model.ReadingGoal
+		db.First(&goal)
+
+		response, err := deleteRequest(nil, adminCookie, app, fmt.Sprintf("/users/admin/goals/%d", goal.ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		var count int64
+		db.Model(&model.ReadingGoal{}).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected goal to be removed, %d left", count)
+		}
+	})
+}
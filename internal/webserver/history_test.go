@@ -0,0 +1,119 @@
+package webserver_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestReadingHistory(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	post := func(t *testing.T, URL string) {
+		t.Helper()
+
+		response, err := postRequest(nil, adminCookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+	}
+
+	historyPage := func(t *testing.T, cookie *http.Cookie) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, "/history", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	t.Run("Re-reading a document keeps its previous completions", func(t *testing.T) {
+		post(t, "/documents/"+testDocSlug+"/complete")
+		post(t, "/documents/"+testDocSlug+"/reread")
+
+		var reading model.Reading
+		db.Where("slug = ?", testDocSlug).First(&reading)
+		if reading.CompletedOn != nil {
+			t.Error("Expected document to be in progress again")
+		}
+
+		post(t, "/documents/"+testDocSlug+"/complete")
+
+		doc := historyPage(t, adminCookie)
+		if got := doc.Find("#history li[id^='read-through-']").Length(); got != 2 {
+			t.Errorf("Expected 2 read-throughs in the history, got %d", got)
+		}
+		if got := strings.TrimSpace(doc.Find("#history .read-number").Text()); got != "Read #2" {
+			t.Errorf("Expected latest read-through to be labelled 'Read #2', got '%s'", got)
+		}
+
+		response, err := getRequest(adminCookie, app, "/stats", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		stats, _ := goquery.NewDocumentFromReader(response.Body)
+		if got := stats.Find("#stats-documents").Text(); got != "2" {
+			t.Errorf("Expected both completions to be counted in statistics, got '%s'", got)
+		}
+	})
+
+	t.Run("Users only see their own history", func(t *testing.T) {
+		doc := historyPage(t, regularCookie)
+		if doc.Find("#history").Length() != 0 {
+			t.Error("Expected history to be empty")
+		}
+	})
+
+	t.Run("Read-throughs can be rated", func(t *testing.T) {
+		var readThrough model.ReadThrough
+		db.Where("completed_on IS NOT NULL").Order("id ASC").First(&readThrough)
+		URL := fmt.Sprintf("/history/%d", readThrough.ID)
+
+		response, err := putRequest(url.Values{"rating": {"4"}}, adminCookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		response, err = putRequest(url.Values{"rating": {"9"}}, adminCookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusBadRequest, t)
+
+		// Ratings of other users' read-throughs are ignored
+		response, err = putRequest(url.Values{"rating": {"1"}}, regularCookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		db.First(&readThrough, readThrough.ID)
+		if readThrough.Rating != 4 {
+			t.Errorf("Expected rating to be 4, got %d", readThrough.Rating)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
+
+// HTTPMailTimeout is the longest time waited for the mail API to answer a request
+const HTTPMailTimeout = 30 * time.Second
+
+// HTTPMail sends emails by posting them as JSON to a mail delivery API, authenticating with a bearer token
+// if Key is set. Any 2xx response is considered a successful delivery.
+type HTTPMail struct {
+	URL     string
+	Key     string
+	Address string
+	Client  *http.Client
+}
+
+// HTTPMailRequest is the body of the requests sent to the mail API
+type HTTPMailRequest struct {
+	From        string               `json:"from"`
+	To          []string             `json:"to,omitempty"`
+	Bcc         []string             `json:"bcc,omitempty"`
+	Subject     string               `json:"subject"`
+	HTML        string               `json:"html,omitempty"`
+	Text        string               `json:"text,omitempty"`
+	Attachments []HTTPMailAttachment `json:"attachments,omitempty"`
+}
+
+// HTTPMailAttachment is a file sent along with an email. Inline files are shown in the HTML body,
+// which refers to them as cid:<ContentID>. Content is encoded in base64.
+type HTTPMailAttachment struct {
+	Filename  string `json:"filename"`
+	Content   []byte `json:"content"`
+	ContentID string `json:"content_id,omitempty"`
+	Inline    bool   `json:"inline,omitempty"`
+}
+
+func (h *HTTPMail) Send(address string, message mailer.Message) error {
+	return h.post(h.request(message, []string{address}, nil))
+}
+
+func (h *HTTPMail) SendBCC(addresses []string, message mailer.Message) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	return h.post(h.request(message, nil, addresses))
+}
+
+func (h *HTTPMail) SendDocument(address, subject string, file []byte, fileName string) error {
+	return h.post(HTTPMailRequest{
+		From:        h.Address,
+		To:          []string{address},
+		Subject:     subject,
+		Attachments: []HTTPMailAttachment{{Filename: fileName, Content: file}},
+	})
+}
+
+func (h *HTTPMail) From() string {
+	return h.Address
+}
+
+func (h *HTTPMail) request(message mailer.Message, to, bcc []string) HTTPMailRequest {
+	request := HTTPMailRequest{
+		From:    h.Address,
+		To:      to,
+		Bcc:     bcc,
+		Subject: message.Subject,
+		HTML:    message.HTML,
+		Text:    message.Text,
+	}
+	for _, inline := range message.Inline {
+		request.Attachments = append(request.Attachments, HTTPMailAttachment{
+			Filename:  inline.Name,
+			Content:   inline.Data,
+			ContentID: inline.ContentID,
+			Inline:    true,
+		})
+	}
+	return request
+}
+
+func (h *HTTPMail) post(request HTTPMailRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.Key != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Key)
+	}
+
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: HTTPMailTimeout}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		// Include the start of the response, as mail APIs usually explain there why the email was rejected
+		reason, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("mail API returned %s: %s", res.Status, bytes.TrimSpace(reason))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package infrastructure_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
+
+func TestHTTPMail(t *testing.T) {
+	var (
+		received      infrastructure.HTTPMailRequest
+		authorization string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		received = infrastructure.HTTPMailRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if received.To[0] == "rejected@example.com" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error": "invalid recipient"}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := &infrastructure.HTTPMail{URL: server.URL, Key: "secret", Address: "library@example.com"}
+
+	t.Run("Messages are posted as JSON", func(t *testing.T) {
+		message := mailer.Message{Subject: "Subject", HTML: `<p>Body</p><img src="cid:cover">`, Text: "Body"}
+		message.Embed("cover", "cover.jpg", []byte("image"))
+
+		if err := sender.Send("reader@example.com", message); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if authorization != "Bearer secret" {
+			t.Errorf("Expected the API key to be sent as bearer token, got '%s'", authorization)
+		}
+		if received.From != "library@example.com" || received.To[0] != "reader@example.com" || received.Subject != "Subject" || received.Text != "Body" {
+			t.Errorf("Unexpected request %+v", received)
+		}
+		if len(received.Attachments) != 1 || !received.Attachments[0].Inline || received.Attachments[0].ContentID != "cover" || string(received.Attachments[0].Content) != "image" {
+			t.Errorf("Expected the cover to be sent inline, got %+v", received.Attachments)
+		}
+	})
+
+	t.Run("Documents are sent as attachments", func(t *testing.T) {
+		if err := sender.SendDocument("reader@example.com", "Document", []byte("contents"), "document.epub"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(received.Attachments) != 1 || received.Attachments[0].Inline || received.Attachments[0].Filename != "document.epub" {
+			t.Errorf("Expected the document to be attached, got %+v", received.Attachments)
+		}
+	})
+
+	t.Run("Rejected messages return the reason", func(t *testing.T) {
+		err := sender.Send("rejected@example.com", mailer.Message{Subject: "Subject"})
+		if err == nil || !strings.Contains(err.Error(), "invalid recipient") {
+			t.Errorf("Expected the API error to be returned, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// LDAP authenticates users against an LDAP directory using a search and bind strategy:
+// the user entry is looked up with the service account, and then the user's own
+// credentials are verified by binding as that entry
+type LDAP struct {
+	URL               string
+	StartTLS          bool
+	BindDN            string
+	BindPassword      string
+	BaseDN            string
+	UserFilter        string
+	UsernameAttribute string
+	NameAttribute     string
+	EmailAttribute    string
+	GroupAttribute    string
+	AdminGroup        string
+	UserGroup         string
+}
+
+// Authenticate verifies login and password against the directory and returns a user with
+// the attributes stored in it. A nil user is returned if credentials are wrong or the
+// user does not belong to any of the allowed groups. The role of the returned user
+// is only set if an admin group is configured, otherwise it is left as zero.
+func (l *LDAP) Authenticate(login, password string) (*model.User, error) {
+	if l == nil || login == "" || password == "" {
+		return nil, nil
+	}
+
+	conn, err := l.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if l.BindDN != "" {
+		err = conn.Bind(l.BindDN, l.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error binding to LDAP server with service account: %w", err)
+	}
+
+	entry, err := l.findEntry(conn, login)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	if err = conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error binding to LDAP server as %s: %w", entry.DN, err)
+	}
+
+	groups := entry.GetAttributeValues(l.GroupAttribute)
+	isAdmin := l.AdminGroup != "" && containsDN(groups, l.AdminGroup)
+	if l.UserGroup != "" && !isAdmin && !containsDN(groups, l.UserGroup) {
+		return nil, nil
+	}
+
+	user := &model.User{
+		Username: entry.GetAttributeValue(l.UsernameAttribute),
+		Name:     entry.GetAttributeValue(l.NameAttribute),
+		Email:    entry.GetAttributeValue(l.EmailAttribute),
+	}
+	if l.AdminGroup != "" {
+		user.Role = model.RoleRegular
+		if isAdmin {
+			user.Role = model.RoleAdmin
+		}
+	}
+	return user, nil
+}
+
+func (l *LDAP) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(l.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to LDAP server: %w", err)
+	}
+
+	if l.StartTLS {
+		serverURL, err := url.Parse(l.URL)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if err = conn.StartTLS(&tls.Config{ServerName: serverURL.Hostname()}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error starting TLS with LDAP server: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (l *LDAP) findEntry(conn *ldap.Conn, login string) (*ldap.Entry, error) {
+	request := ldap.NewSearchRequest(
+		l.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		0,
+		false,
+		strings.ReplaceAll(l.UserFilter, "{login}", ldap.EscapeFilter(login)),
+		[]string{"dn", l.UsernameAttribute, l.NameAttribute, l.EmailAttribute, l.GroupAttribute},
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error searching LDAP user: %w", err)
+	}
+
+	// Refuse to guess when the filter matches more than one entry
+	if len(result.Entries) != 1 {
+		return nil, nil
+	}
+	return result.Entries[0], nil
+}
+
+func containsDN(dns []string, dn string) bool {
+	return slices.ContainsFunc(dns, func(candidate string) bool {
+		return strings.EqualFold(candidate, dn)
+	})
+}
@@ -1,13 +1,15 @@
 package infrastructure
 
+import "github.com/svera/coreander/v4/internal/webserver/mailer"
+
 type NoEmail struct {
 }
 
-func (s *NoEmail) Send(address, subject, body string) error {
+func (s *NoEmail) Send(address string, message mailer.Message) error {
 	return nil
 }
 
-func (s *NoEmail) SendBCC(addresses []string, subject, body string) error {
+func (s *NoEmail) SendBCC(addresses []string, message mailer.Message) error {
 	return nil
 }
 
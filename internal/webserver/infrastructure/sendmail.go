@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/wneessen/go-mail"
+)
+
+// Sendmail sends emails by piping them to a local sendmail compatible binary, such as the ones provided
+// by Postfix, Exim or msmtp, which reads the recipients from the message headers.
+type Sendmail struct {
+	// Path is the location of the binary, which defaults to /usr/sbin/sendmail
+	Path    string
+	Address string
+}
+
+func (s *Sendmail) Send(address string, message mailer.Message) error {
+	m, err := newMessage(s.Address, message, []string{address}, nil)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Sendmail) SendBCC(addresses []string, message mailer.Message) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	m, err := newMessage(s.Address, message, nil, addresses)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Sendmail) SendDocument(address, subject string, file []byte, fileName string) error {
+	m, err := newDocumentMessage(s.Address, address, subject, file, fileName)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Sendmail) deliver(m *mail.Msg) error {
+	if s.Path == "" {
+		return m.WriteToSendmail()
+	}
+	return m.WriteToSendmailWithCommand(s.Path)
+}
+
+func (s *Sendmail) From() string {
+	return s.Address
+}
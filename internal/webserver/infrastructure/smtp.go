@@ -2,17 +2,20 @@ package infrastructure
 
 import (
 	"bytes"
-	"log"
 
-	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/wneessen/go-mail"
 )
 
+// SMTP sends emails through a mail server. Sending is synchronous, so it is meant to be used as the transport
+// of a mail queue.
 type SMTP struct {
 	Server   string
 	Port     int
 	User     string
 	Password string
+	// Address is the address emails are sent from, which defaults to User
+	Address string
 }
 
 func (s *SMTP) client() (*mail.Client, error) {
@@ -24,77 +27,98 @@ func (s *SMTP) client() (*mail.Client, error) {
 	)
 }
 
-func (s *SMTP) Send(address, subject, body string) error {
-	client, err := s.client()
+func (s *SMTP) Send(address string, message mailer.Message) error {
+	m, err := newMessage(s.From(), message, []string{address}, nil)
 	if err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
-	}
-	m := mail.NewMsg()
-	m.FromFormat("Coreander", s.User)
-	if err := m.To(address); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
-	}
-	m.Subject(subject)
-	m.SetBodyString(mail.TypeTextHTML, body)
-	if err := client.DialAndSend(m); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
+		return err
 	}
-	return nil
+	return s.deliver(m)
 }
 
-func (s *SMTP) SendBCC(addresses []string, subject, body string) error {
+func (s *SMTP) SendBCC(addresses []string, message mailer.Message) error {
 	if len(addresses) == 0 {
 		return nil
 	}
-	client, err := s.client()
+	m, err := newMessage(s.From(), message, nil, addresses)
 	if err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
-	}
-	m := mail.NewMsg()
-	m.FromFormat("Coreander", s.User)
-	if err := m.Bcc(addresses...); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
+		return err
 	}
-	m.Subject(subject)
-	m.SetBodyString(mail.TypeTextHTML, body)
-	if err := client.DialAndSend(m); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
-	}
-	return nil
+	return s.deliver(m)
 }
 
 // SendDocument sends an email with the given file attached to the chosen address.
 func (s *SMTP) SendDocument(address, subject string, file []byte, fileName string) error {
+	m, err := newDocumentMessage(s.From(), address, subject, file, fileName)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *SMTP) deliver(m *mail.Msg) error {
 	client, err := s.client()
 	if err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
+		return err
+	}
+	return client.DialAndSend(m)
+}
+
+func (s *SMTP) From() string {
+	if s.Address != "" {
+		return s.Address
 	}
+	return s.User
+}
+
+// newMessage returns a message from the passed address to the to recipients, and to the bcc ones without disclosing them
+func newMessage(from string, message mailer.Message, to, bcc []string) (*mail.Msg, error) {
 	m := mail.NewMsg()
-	m.FromFormat("Coreander", s.User)
+	if err := m.FromFormat("Coreander", from); err != nil {
+		return nil, err
+	}
+	if len(to) > 0 {
+		if err := m.To(to...); err != nil {
+			return nil, err
+		}
+	}
+	if len(bcc) > 0 {
+		if err := m.Bcc(bcc...); err != nil {
+			return nil, err
+		}
+	}
+	if err := compose(m, message); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// newDocumentMessage returns a message from the passed address with file attached
+func newDocumentMessage(from, address, subject string, file []byte, fileName string) (*mail.Msg, error) {
+	m := mail.NewMsg()
+	if err := m.FromFormat("Coreander", from); err != nil {
+		return nil, err
+	}
 	if err := m.To(address); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
+		return nil, err
 	}
 	m.Subject(subject)
 	m.SetBodyString(mail.TypeTextHTML, "")
 	if err := m.AttachReader(fileName, bytes.NewReader(file)); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
+		return nil, err
 	}
-	if err := client.DialAndSend(m); err != nil {
-		log.Println(err)
-		return fiber.ErrInternalServerError
-	}
-	return nil
+	return m, nil
 }
 
-func (s *SMTP) From() string {
-	return s.User
+// compose sets the subject and body of m, sending the plain text version of the message
+// along with the HTML one so clients can choose which one to show
+func compose(m *mail.Msg, message mailer.Message) error {
+	m.Subject(message.Subject)
+	m.SetBodyString(mail.TypeTextPlain, message.Text)
+	m.AddAlternativeString(mail.TypeTextHTML, message.HTML)
+	for _, inline := range message.Inline {
+		if err := m.EmbedReader(inline.Name, bytes.NewReader(inline.Data), mail.WithFileContentID(inline.ContentID)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
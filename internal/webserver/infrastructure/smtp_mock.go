@@ -1,6 +1,10 @@
 package infrastructure
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
 
 type SMTPMock struct {
 	calledSend         bool
@@ -8,24 +12,32 @@ type SMTPMock struct {
 	mu                 sync.Mutex
 	Wg                 sync.WaitGroup
 	LastBody           string
+	LastText           string
+	LastInline         []mailer.Inline
+	LastAddress        string
+	LastFileName       string
 }
 
-func (s *SMTPMock) Send(address, subject, body string) error {
+func (s *SMTPMock) Send(address string, message mailer.Message) error {
 	defer s.Wg.Done()
 
 	s.mu.Lock()
 	s.calledSend = true
-	s.LastBody = body
+	s.LastBody = message.HTML
+	s.LastText = message.Text
+	s.LastInline = message.Inline
 	s.mu.Unlock()
 	return nil
 }
 
-func (s *SMTPMock) SendBCC(addresses []string, subject, body string) error {
+func (s *SMTPMock) SendBCC(addresses []string, message mailer.Message) error {
 	defer s.Wg.Done()
 
 	s.mu.Lock()
 	s.calledSend = true
-	s.LastBody = body
+	s.LastBody = message.HTML
+	s.LastText = message.Text
+	s.LastInline = message.Inline
 	s.mu.Unlock()
 	return nil
 }
@@ -35,6 +47,8 @@ func (s *SMTPMock) SendDocument(address, subject string, file []byte, fileName s
 
 	s.mu.Lock()
 	s.calledSendDocument = true
+	s.LastAddress = address
+	s.LastFileName = fileName
 	s.mu.Unlock()
 	return nil
 }
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/wneessen/go-mail"
+)
+
+// Spool stores emails as files in a maildir instead of sending them, so they can be inspected with a mail client
+// during development. Each email is written to the tmp subfolder first and then moved to new, so readers never
+// see incomplete messages.
+type Spool struct {
+	Dir     string
+	Address string
+}
+
+func (s *Spool) Send(address string, message mailer.Message) error {
+	m, err := newMessage(s.Address, message, []string{address}, nil)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Spool) SendBCC(addresses []string, message mailer.Message) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	m, err := newMessage(s.Address, message, nil, addresses)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Spool) SendDocument(address, subject string, file []byte, fileName string) error {
+	m, err := newDocumentMessage(s.Address, address, subject, file, fileName)
+	if err != nil {
+		return err
+	}
+	return s.deliver(m)
+}
+
+func (s *Spool) deliver(m *mail.Msg) error {
+	for _, dir := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(s.Dir, dir), os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%d.%s.coreander", time.Now().Unix(), uuid.NewString())
+	tmp := filepath.Join(s.Dir, "tmp", name)
+	if err := m.WriteToFile(tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, "new", name))
+}
+
+func (s *Spool) From() string {
+	return s.Address
+}
@@ -0,0 +1,42 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
+
+func TestSpool(t *testing.T) {
+	dir := t.TempDir()
+	sender := &infrastructure.Spool{Dir: dir, Address: "library@example.com"}
+
+	message := mailer.Message{Subject: "Spooled", HTML: "<p>Hello</p>", Text: "Hello"}
+	if err := sender.Send("reader@example.com", message); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 email in the maildir, got %d", len(files))
+	}
+	if pending, _ := os.ReadDir(filepath.Join(dir, "tmp")); len(pending) != 0 {
+		t.Error("Expected no emails left in the tmp folder")
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"To: <reader@example.com>", "Subject: Spooled", "multipart/alternative", "text/plain", "text/html"} {
+		if !strings.Contains(string(contents), expected) {
+			t.Errorf("Expected email to contain '%s', got:\n%s", expected, contents)
+		}
+	}
+}
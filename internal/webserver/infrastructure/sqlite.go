@@ -30,13 +30,44 @@ func Connect(path string, wordsPerMinute float64) *gorm.DB {
 		log.Fatal(err)
 	}
 
-	if err := db.AutoMigrate(&model.User{}, &model.Highlight{}, &model.Reading{}, &model.Invitation{}); err != nil {
+	removeOrphans(db, "reading_sessions", "read_throughs")
+	hasReadThroughs := db.Migrator().HasTable(&model.ReadThrough{})
+	if err := db.AutoMigrate(&model.User{}, &model.Highlight{}, &model.Reading{}, &model.Invitation{}, &model.Passkey{}, &model.AuditEntry{}, &model.ReadingGoal{}, &model.ReadingSession{}, &model.ReadThrough{}, &model.Review{}, &model.Shelf{}, &model.ShelfDocument{}, &model.ShelfMember{}, &model.QueuedDocument{}, &model.Activity{}, &model.Follow{}, &model.Notification{}, &model.NotificationPreference{}, &model.SeriesFollow{}, &model.Comment{}, &model.Device{}, &model.SentDocument{}, &model.OutgoingEmail{}, &model.EmailInlineImage{}, &model.EmailDelivery{}, &model.ReaderPreferences{}); err != nil {
 		log.Fatal(err)
 	}
+	if !hasReadThroughs {
+		addReadThroughs(db)
+	}
 	addDefaultAdmin(db, wordsPerMinute)
 	return db
 }
 
+// removeOrphans deletes the rows of deleted users from tables created without a foreign key to users,
+// as they would make adding it fail
+func removeOrphans(db *gorm.DB, tables ...string) {
+	for _, table := range tables {
+		if !db.Migrator().HasTable(table) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE user_id NOT IN (SELECT id FROM users)", table)).Error; err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// addReadThroughs creates the first read-through of the documents completed before read-throughs were tracked
+func addReadThroughs(db *gorm.DB) {
+	result := db.Exec(
+		`INSERT INTO read_throughs (created_at, updated_at, user_id, slug, started_on, completed_on, rating)
+		 SELECT completed_on, completed_on, user_id, slug, CASE WHEN created_at <= completed_on THEN created_at END, completed_on, 0
+		 FROM readings
+		 WHERE completed_on IS NOT NULL`,
+	)
+	if result.Error != nil {
+		log.Fatal(result.Error)
+	}
+}
+
 func addDefaultAdmin(db *gorm.DB, wordsPerMinute float64) {
 	var result int64
 	db.Table("users").Count(&result)
@@ -0,0 +1,59 @@
+package infrastructure_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+	"gorm.io/gorm"
+)
+
+func TestConnectRemovesOrphans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+
+	if db, err := infrastructure.Connect(path, 250).DB(); err == nil {
+		db.Close()
+	}
+
+	// Reading sessions and read-throughs of a database created before they were linked to their users
+	old, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, query := range []string{
+		"DROP TABLE reading_sessions",
+		"CREATE TABLE reading_sessions (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, user_id integer NOT NULL, slug text NOT NULL, started_at datetime NOT NULL, ended_at datetime NOT NULL, start_percentage integer DEFAULT 0, end_percentage integer DEFAULT 0)",
+		"INSERT INTO reading_sessions (user_id, slug, started_at, ended_at) VALUES (1, 'kept', '2024-01-01', '2024-01-01'), (2, 'orphan', '2024-01-01', '2024-01-01')",
+		"DROP TABLE read_throughs",
+		"CREATE TABLE read_throughs (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, user_id integer NOT NULL, slug text NOT NULL, started_on datetime, completed_on datetime, rating integer NOT NULL DEFAULT 0)",
+		"INSERT INTO read_throughs (user_id, slug, completed_on) VALUES (1, 'kept', '2024-01-01'), (2, 'orphan', '2024-01-01')",
+	} {
+		if err := old.Exec(query).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if db, err := old.DB(); err == nil {
+		db.Close()
+	}
+
+	db := infrastructure.Connect(path, 250)
+
+	for _, table := range []any{&model.ReadingSession{}, &model.ReadThrough{}} {
+		var slugs []string
+		db.Model(table).Pluck("slug", &slugs)
+		if len(slugs) != 1 || slugs[0] != "kept" {
+			t.Errorf("Expected only the %T of the existing user to be kept, got %v", table, slugs)
+		}
+	}
+
+	db.Where("id = ?", 1).Delete(&model.User{})
+	for _, table := range []any{&model.ReadingSession{}, &model.ReadThrough{}} {
+		var count int64
+		db.Model(table).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected %T to be deleted along with their user, got %d", table, count)
+		}
+	}
+}
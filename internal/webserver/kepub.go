@@ -0,0 +1,41 @@
+package webserver
+
+import (
+	"log"
+
+	"github.com/svera/coreander/v4/internal/conversion"
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+type kepubQueueRepository interface {
+	QueuedByKepubReaders() ([]string, error)
+}
+
+type kepubFiles interface {
+	File(slug string) (*index.IndexedFile, error)
+}
+
+// pregenerateKepubs converts to KEPUB the EPUB files in the reading queues of the users who prefer that format,
+// as those are the documents they are going to download next. Files already converted are not converted again.
+func pregenerateKepubs(conversions *conversion.Cache, queueRepository kepubQueueRepository, idx kepubFiles) {
+	slugs, err := queueRepository.QueuedByKepubReaders()
+	if err != nil {
+		return
+	}
+
+	for _, slug := range slugs {
+		file, err := idx.File(slug)
+		if err != nil {
+			continue
+		}
+		if file.ContentType == "application/epub+zip" {
+			converted, _, err := conversions.Convert(file.Document.ID, file.ModTime, file.Content, file.Size, conversion.EPUB, conversion.KEPUB)
+			if err != nil {
+				log.Printf("error converting %s to KEPUB: %s\n", slug, err)
+			} else {
+				converted.Close()
+			}
+		}
+		file.Content.Close()
+	}
+}
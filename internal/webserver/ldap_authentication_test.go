@@ -0,0 +1,302 @@
+package webserver_test
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jimlambrt/gldap"
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+const (
+	ldapBaseDN      = "dc=example,dc=com"
+	ldapServiceDN   = "cn=service," + ldapBaseDN
+	ldapAdminGroup  = "cn=admins,ou=groups," + ldapBaseDN
+	ldapReaderGroup = "cn=readers,ou=groups," + ldapBaseDN
+)
+
+type ldapEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// ldapDirectory is an in-process LDAP server which supports simple binds and
+// searches by mail, enough to exercise the search and bind authentication flow
+type ldapDirectory struct {
+	mu      sync.Mutex
+	entries map[string]ldapEntry
+	server  *gldap.Server
+	url     string
+}
+
+func startLDAPDirectory(t *testing.T, entries ...ldapEntry) *ldapDirectory {
+	t.Helper()
+
+	d := &ldapDirectory{entries: map[string]ldapEntry{
+		ldapServiceDN: {dn: ldapServiceDN, password: "service-secret"},
+	}}
+	for _, entry := range entries {
+		d.entries[entry.dn] = entry
+	}
+
+	var err error
+	if d.server, err = gldap.NewServer(); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	mux, err := gldap.NewMux()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	mux.Bind(d.bind)
+	mux.Search(d.search)
+	d.server.Router(mux)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	go d.server.Run(addr)
+	for i := 0; i < 100 && !d.server.Ready(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !d.server.Ready() {
+		t.Fatal("LDAP server did not start")
+	}
+	t.Cleanup(func() { d.server.Stop() })
+
+	d.url = "ldap://" + addr
+	return d
+}
+
+func (d *ldapDirectory) setName(dn, name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[dn].attrs["cn"] = []string{name}
+}
+
+func (d *ldapDirectory) bind(w *gldap.ResponseWriter, r *gldap.Request) {
+	resp := r.NewBindResponse(gldap.WithResponseCode(gldap.ResultInvalidCredentials))
+	defer w.Write(resp)
+
+	m, err := r.GetSimpleBindMessage()
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if entry, ok := d.entries[m.UserName]; ok && entry.password == string(m.Password) {
+		resp.SetResultCode(gldap.ResultSuccess)
+	}
+}
+
+func (d *ldapDirectory) search(w *gldap.ResponseWriter, r *gldap.Request) {
+	resp := r.NewSearchDoneResponse(gldap.WithResponseCode(gldap.ResultSuccess))
+	defer w.Write(resp)
+
+	m, err := r.GetSearchMessage()
+	if err != nil {
+		resp.SetResultCode(gldap.ResultOperationsError)
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, entry := range d.entries {
+		for _, mail := range entry.attrs["mail"] {
+			if strings.Contains(m.Filter, "(mail="+mail+")") {
+				w.Write(r.NewSearchResponseEntry(entry.dn, gldap.WithAttributes(entry.attrs)))
+			}
+		}
+	}
+}
+
+func TestLDAPAuthentication(t *testing.T) {
+	directory := startLDAPDirectory(t,
+		ldapEntry{
+			dn:       "uid=alice,ou=people," + ldapBaseDN,
+			password: "alice-secret",
+			attrs: map[string][]string{
+				"uid":      {"alice"},
+				"cn":       {"Alice Smith"},
+				"mail":     {"alice@example.com"},
+				"memberOf": {ldapAdminGroup},
+			},
+		},
+		ldapEntry{
+			dn:       "uid=bob,ou=people," + ldapBaseDN,
+			password: "bob-secret",
+			attrs: map[string][]string{
+				"uid":      {"bob"},
+				"cn":       {"Bob Jones"},
+				"mail":     {"bob@example.com"},
+				"memberOf": {ldapReaderGroup},
+			},
+		},
+		ldapEntry{
+			dn:       "uid=carol,ou=people," + ldapBaseDN,
+			password: "carol-secret",
+			attrs: map[string][]string{
+				"uid":  {"carol"},
+				"cn":   {"Carol White"},
+				"mail": {"carol@example.com"},
+			},
+		},
+		ldapEntry{
+			dn:       "uid=impostor,ou=people," + ldapBaseDN,
+			password: "impostor-secret",
+			attrs: map[string][]string{
+				"uid":      {"impostor"},
+				"cn":       {"Impostor"},
+				"mail":     {"admin@example.com"},
+				"memberOf": {ldapReaderGroup},
+			},
+		},
+	)
+
+	reset := func(t *testing.T) (*http.Cookie, func(email, password string) *http.Response, func(email string) *model.User) {
+		t.Helper()
+
+		webserverConfig := defaultTestConfig()
+		webserverConfig.LDAP = &infrastructure.LDAP{
+			URL:               directory.url,
+			BindDN:            ldapServiceDN,
+			BindPassword:      "service-secret",
+			BaseDN:            ldapBaseDN,
+			UserFilter:        "(&(objectClass=person)(mail={login}))",
+			UsernameAttribute: "uid",
+			NameAttribute:     "cn",
+			EmailAttribute:    "mail",
+			GroupAttribute:    "memberOf",
+			AdminGroup:        ldapAdminGroup,
+			UserGroup:         ldapReaderGroup,
+		}
+		db := infrastructure.Connect(":memory:", 250)
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), webserverConfig)
+
+		signIn := func(email, password string) *http.Response {
+			t.Helper()
+
+			data := url.Values{"email": {email}, "password": {password}}
+			req, err := http.NewRequest(http.MethodPost, "/sessions", strings.NewReader(data.Encode()))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+			response, err := app.Test(req)
+			if response == nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			return response
+		}
+
+		find := func(email string) *model.User {
+			t.Helper()
+
+			user, err := (&model.UserRepository{DB: db}).FindByEmail(email)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			return user
+		}
+
+		response := signIn("alice@example.com", "alice-secret")
+		if response.StatusCode != http.StatusFound && response.StatusCode != http.StatusSeeOther {
+			t.Fatalf("Expected status 302 or 303, received %d", response.StatusCode)
+		}
+		adminCookie := response.Cookies()[0]
+		usersResponse, err := getRequest(adminCookie, app, "/users", t)
+		if usersResponse == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(usersResponse, http.StatusOK, t)
+
+		return adminCookie, signIn, find
+	}
+
+	t.Run("Members of the admin group are created as admins with directory attributes", func(t *testing.T) {
+		_, _, find := reset(t)
+
+		user := find("alice@example.com")
+		if user == nil {
+			t.Fatal("Expected user to be created")
+		}
+		if user.Role != model.RoleAdmin {
+			t.Errorf("Expected admin role, got %d", user.Role)
+		}
+		if user.Name != "Alice Smith" || user.Username != "alice" {
+			t.Errorf("Expected name Alice Smith and username alice, got %s and %s", user.Name, user.Username)
+		}
+		if user.Password != "" {
+			t.Error("Directory password must not be stored")
+		}
+	})
+
+	t.Run("Members of the user group are created as regular users, and their name synced on every login", func(t *testing.T) {
+		_, signIn, find := reset(t)
+
+		mustReturnStatus(signIn("bob@example.com", "bob-secret"), http.StatusSeeOther, t)
+		if user := find("bob@example.com"); user == nil || user.Role != model.RoleRegular {
+			t.Fatal("Expected regular user to be created")
+		}
+
+		directory.setName("uid=bob,ou=people,"+ldapBaseDN, "Robert Jones")
+		defer directory.setName("uid=bob,ou=people,"+ldapBaseDN, "Bob Jones")
+
+		mustReturnStatus(signIn("bob@example.com", "bob-secret"), http.StatusSeeOther, t)
+		if user := find("bob@example.com"); user.Name != "Robert Jones" {
+			t.Errorf("Expected name to be synced to Robert Jones, got %s", user.Name)
+		}
+	})
+
+	t.Run("Wrong passwords and users outside the allowed groups are rejected", func(t *testing.T) {
+		_, signIn, find := reset(t)
+
+		mustReturnStatus(signIn("bob@example.com", "wrong"), http.StatusUnauthorized, t)
+		mustReturnStatus(signIn("carol@example.com", "carol-secret"), http.StatusUnauthorized, t)
+		if find("carol@example.com") != nil {
+			t.Error("Expected user outside allowed groups not to be created")
+		}
+	})
+
+	t.Run("Local users can still log in with their local password", func(t *testing.T) {
+		_, signIn, _ := reset(t)
+
+		mustReturnStatus(signIn("admin@example.com", "admin"), http.StatusSeeOther, t)
+	})
+
+	t.Run("Directory entries cannot sign in as local users with the same email", func(t *testing.T) {
+		_, signIn, find := reset(t)
+
+		mustReturnStatus(signIn("admin@example.com", "impostor-secret"), http.StatusUnauthorized, t)
+		user := find("admin@example.com")
+		if user.Role != model.RoleAdmin || user.Name == "Impostor" {
+			t.Errorf("Expected local user not to be updated from the directory, got role %d and name %s", user.Role, user.Name)
+		}
+		mustReturnStatus(signIn("admin@example.com", "admin"), http.StatusSeeOther, t)
+	})
+
+	t.Run("Local users can log in if the directory is unreachable", func(t *testing.T) {
+		db := infrastructure.Connect(":memory:", 250)
+		webserverConfig := defaultTestConfig()
+		webserverConfig.LDAP = &infrastructure.LDAP{URL: "ldap://127.0.0.1:1", BaseDN: ldapBaseDN}
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), webserverConfig)
+
+		cookie, err := login(app, "admin@example.com", "admin", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		if cookie.Name != "session" {
+			t.Errorf("Expected session cookie, got %s", cookie.Name)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package webserver
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+const (
+	// MailQueueInterval is how often the mail queue looks for deliveries due to be retried
+	MailQueueInterval = time.Minute
+	// MailRetryBaseDelay is the time waited before retrying a failed delivery for the first time,
+	// doubling after each new failure
+	MailRetryBaseDelay = time.Minute
+	// MailRetryMaxDelay is the longest time waited between two attempts of the same delivery
+	MailRetryMaxDelay = 12 * time.Hour
+)
+
+type mailQueueRepository interface {
+	Enqueue(email *model.OutgoingEmail, addresses []string) error
+	Claim(now time.Time, limit int) ([]model.EmailDelivery, error)
+	MarkSent(delivery model.EmailDelivery, sentAt time.Time) error
+	MarkFailed(delivery model.EmailDelivery, reason string, nextAttemptAt *time.Time) error
+	ResetInterrupted() error
+}
+
+// MailQueue is a sender which stores emails in the database and delivers them in the background through
+// another sender, so requests do not wait for the mail server and emails are not lost when it fails.
+// Each recipient gets their own copy of the email, so addresses are never disclosed to other recipients.
+// Failed deliveries are retried with an exponential backoff until maxAttempts is reached, being marked
+// as failed after that.
+type MailQueue struct {
+	repository  mailQueueRepository
+	transport   Sender
+	workers     int
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewMailQueue returns a queue delivering emails through transport using the passed number of workers.
+// At least one worker and one attempt are used.
+func NewMailQueue(repository mailQueueRepository, transport Sender, workers, maxAttempts int) *MailQueue {
+	return &MailQueue{
+		repository:  repository,
+		transport:   transport,
+		workers:     max(workers, 1),
+		maxAttempts: max(maxAttempts, 1),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (q *MailQueue) Send(address string, message mailer.Message) error {
+	return q.enqueue(outgoingEmail(message), []string{address})
+}
+
+func (q *MailQueue) SendBCC(addresses []string, message mailer.Message) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	return q.enqueue(outgoingEmail(message), addresses)
+}
+
+func (q *MailQueue) SendDocument(address, subject string, file []byte, fileName string) error {
+	_, err := q.QueueDocument(address, subject, file, fileName)
+	return err
+}
+
+// QueueDocument queues a document like SendDocument does, returning the ID of its delivery so its status
+// can be followed
+func (q *MailQueue) QueueDocument(address, subject string, file []byte, fileName string) (uint, error) {
+	email := &model.OutgoingEmail{Subject: subject, Attachment: file, AttachmentName: fileName}
+	if err := q.enqueue(email, []string{address}); err != nil {
+		return 0, err
+	}
+	return email.Deliveries[0].ID, nil
+}
+
+func (q *MailQueue) From() string {
+	return q.transport.From()
+}
+
+func outgoingEmail(message mailer.Message) *model.OutgoingEmail {
+	email := &model.OutgoingEmail{Subject: message.Subject, Body: message.HTML, Text: message.Text}
+	for _, inline := range message.Inline {
+		email.InlineImages = append(email.InlineImages, model.EmailInlineImage{
+			ContentID: inline.ContentID,
+			Name:      inline.Name,
+			Data:      inline.Data,
+		})
+	}
+	return email
+}
+
+func (q *MailQueue) enqueue(email *model.OutgoingEmail, addresses []string) error {
+	if err := q.repository.Enqueue(email, addresses); err != nil {
+		return err
+	}
+	// Deliver right away instead of waiting for the next tick, unless a delivery round is already pending
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start resumes the deliveries interrupted when the process stopped, and delivers queued emails
+// until the process exits
+func (q *MailQueue) Start() {
+	q.repository.ResetInterrupted()
+	go q.run()
+}
+
+func (q *MailQueue) run() {
+	ticker := time.NewTicker(MailQueueInterval)
+	defer ticker.Stop()
+	for {
+		q.Deliver(time.Now())
+		select {
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// Deliver sends the deliveries due at the passed time, returning once all of them have been attempted
+func (q *MailQueue) Deliver(now time.Time) {
+	for {
+		deliveries, err := q.repository.Claim(now, q.workers)
+		if err != nil || len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				q.deliver(delivery)
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func (q *MailQueue) deliver(delivery model.EmailDelivery) {
+	email := delivery.OutgoingEmail
+
+	var err error
+	if email.AttachmentName != "" {
+		err = q.transport.SendDocument(delivery.Address, email.Subject, email.Attachment, email.AttachmentName)
+	} else {
+		err = q.transport.Send(delivery.Address, message(email))
+	}
+
+	if err == nil {
+		q.repository.MarkSent(delivery, time.Now())
+		return
+	}
+
+	log.Printf("error sending email to %s: %s\n", delivery.Address, err)
+	attempts := delivery.Attempts + 1
+	if attempts >= q.maxAttempts {
+		q.repository.MarkFailed(delivery, err.Error(), nil)
+		return
+	}
+	next := time.Now().Add(RetryDelay(attempts))
+	q.repository.MarkFailed(delivery, err.Error(), &next)
+}
+
+func message(email model.OutgoingEmail) mailer.Message {
+	message := mailer.Message{Subject: email.Subject, HTML: email.Body, Text: email.Text}
+	for _, image := range email.InlineImages {
+		message.Embed(image.ContentID, image.Name, image.Data)
+	}
+	return message
+}
+
+// RetryDelay returns the time to wait before trying a delivery again after the passed number of failed attempts
+func RetryDelay(attempts int) time.Duration {
+	delay := MailRetryBaseDelay
+	for i := 1; i < attempts && delay < MailRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, MailRetryMaxDelay)
+}
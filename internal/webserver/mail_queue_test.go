@@ -0,0 +1,282 @@
+package webserver_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// flakyTransport fails to send emails to the addresses in failing
+type flakyTransport struct {
+	mu      sync.Mutex
+	failing map[string]bool
+	sent    []string
+	last    mailer.Message
+}
+
+func (f *flakyTransport) send(address string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing[address] {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, address)
+	return nil
+}
+
+func (f *flakyTransport) Send(address string, message mailer.Message) error {
+	f.mu.Lock()
+	f.last = message
+	f.mu.Unlock()
+	return f.send(address)
+}
+
+func (f *flakyTransport) SendBCC(addresses []string, message mailer.Message) error {
+	return errors.New("deliveries must be sent one by one")
+}
+
+func (f *flakyTransport) SendDocument(address, subject string, file []byte, fileName string) error {
+	return f.send(address)
+}
+
+func (f *flakyTransport) From() string {
+	return "library@example.com"
+}
+
+func TestMailQueue(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	transport := &flakyTransport{failing: map[string]bool{"unavailable@example.com": true}}
+	queue := webserver.NewMailQueue(&model.EmailRepository{DB: db}, transport, 2, 3)
+	app := bootstrapApp(db, queue, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	delivery := func(t *testing.T, address string) model.EmailDelivery {
+		t.Helper()
+
+		var delivery model.EmailDelivery
+		if err := db.Where("address = ?", address).Order("id DESC").First(&delivery).Error; err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return delivery
+	}
+
+	t.Run("Emails are queued and delivered to each recipient", func(t *testing.T) {
+		message := mailer.Message{Subject: "Subject", HTML: `<p>Body</p><img src="cid:cover">`, Text: "Body"}
+		message.Embed("cover", "cover.jpg", []byte("image"))
+		if err := queue.SendBCC([]string{"a@example.com", "b@example.com"}, message); err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		if len(transport.sent) != 0 {
+			t.Fatal("Expected emails not to be sent until delivered")
+		}
+
+		queue.Deliver(time.Now())
+		if len(transport.sent) != 2 {
+			t.Fatalf("Expected 2 emails sent, got %d", len(transport.sent))
+		}
+		for _, address := range []string{"a@example.com", "b@example.com"} {
+			if d := delivery(t, address); d.Status != model.EmailStatusSent || d.SentAt == nil {
+				t.Errorf("Expected delivery to %s to be sent, got %s", address, d.Status)
+			}
+		}
+		if transport.last.Text != "Body" || len(transport.last.Inline) != 1 || string(transport.last.Inline[0].Data) != "image" {
+			t.Errorf("Expected plain text and inline images to be delivered, got %+v", transport.last)
+		}
+
+		var images int64
+		db.Model(&model.EmailInlineImage{}).Count(&images)
+		if images != 0 {
+			t.Error("Expected inline images to be removed once delivered")
+		}
+	})
+
+	t.Run("Attachments are removed once delivered", func(t *testing.T) {
+		queue.SendDocument("a@example.com", "Document", []byte("contents"), "document.epub")
+		queue.Deliver(time.Now())
+
+		var email model.OutgoingEmail
+		db.Where("attachment_name = ?", "document.epub").First(&email)
+		if len(email.Attachment) != 0 {
+			t.Error("Expected attachment to be removed")
+		}
+	})
+
+	t.Run("Failed deliveries are retried later until they reach the maximum attempts", func(t *testing.T) {
+		queue.Send("unavailable@example.com", mailer.Message{Subject: "Subject", HTML: "Body", Text: "Body"})
+		now := time.Now()
+
+		queue.Deliver(now)
+		if d := delivery(t, "unavailable@example.com"); d.Status != model.EmailStatusPending || d.Attempts != 1 || !d.NextAttemptAt.After(now) {
+			t.Fatalf("Expected delivery to be retried later, got %+v", d)
+		}
+
+		queue.Deliver(now.Add(24 * time.Hour))
+		d := delivery(t, "unavailable@example.com")
+		if d.Status != model.EmailStatusFailed || d.Attempts != 3 || d.LastError != "mailbox unavailable" {
+			t.Errorf("Expected delivery to fail after 3 attempts, got %+v", d)
+		}
+	})
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	t.Run("Admins can see the status of deliveries", func(t *testing.T) {
+		response, err := getRequest(adminCookie, app, "/emails?status=failed", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		if rows := doc.Find("#email-deliveries tbody tr"); rows.Length() != 1 || !strings.Contains(rows.Text(), "unavailable@example.com") {
+			t.Errorf("Expected the failed delivery to be listed, got '%s'", rows.Text())
+		}
+	})
+
+	t.Run("Admins can retry failed deliveries", func(t *testing.T) {
+		response, err := postRequest(nil, adminCookie, app, fmt.Sprintf("/emails/%d/retry", delivery(t, "a@example.com").ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNotFound, t)
+
+		transport.failing = nil
+		response, err = postRequest(nil, adminCookie, app, fmt.Sprintf("/emails/%d/retry", delivery(t, "unavailable@example.com").ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		queue.Deliver(time.Now())
+		if d := delivery(t, "unavailable@example.com"); d.Status != model.EmailStatusSent {
+			t.Errorf("Expected retried delivery to be sent, got %s", d.Status)
+		}
+	})
+
+	t.Run("Admins can preview email templates", func(t *testing.T) {
+		response, err := getRequest(adminCookie, app, "/emails/preview?template=share&lang=fr", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		preview, _ := doc.Find("#email-preview iframe").Attr("srcdoc")
+		if !strings.Contains(preview, `lang="fr"`) || !strings.Contains(preview, "data:image/jpeg;base64,") {
+			t.Errorf("Expected a french preview with the cover embedded, got '%s'", preview)
+		}
+		if text := doc.Find("#email-text").Text(); !strings.Contains(text, "Moby Dick (") {
+			t.Errorf("Expected the plain text version to be shown, got '%s'", text)
+		}
+
+		response, err = getRequest(adminCookie, app, "/emails/preview?template=unknown", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusBadRequest, t)
+	})
+
+	t.Run("Admins can send a test email", func(t *testing.T) {
+		response, err := postRequest(url.Values{"email": {"test@example.com"}}, adminCookie, app, "/emails/test", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusSeeOther, t)
+
+		queue.Deliver(time.Now())
+		if d := delivery(t, "test@example.com"); d.Status != model.EmailStatusSent {
+			t.Errorf("Expected test email to be sent, got %s", d.Status)
+		}
+		if !strings.Contains(transport.last.Text, "This is a test email sent from Coreander.") {
+			t.Errorf("Expected test email contents, got '%s'", transport.last.Text)
+		}
+
+		response, err = postRequest(url.Values{"email": {"not an address"}}, adminCookie, app, "/emails/test", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusBadRequest, t)
+	})
+
+	t.Run("Regular users cannot see deliveries", func(t *testing.T) {
+		addRegularUser(t, app, adminCookie)
+		regularCookie, err := login(app, "regular@example.com", "regular", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		for _, path := range []string{"/emails", "/emails/preview"} {
+			response, err := getRequest(regularCookie, app, path, t)
+			if response == nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			mustReturnStatus(response, http.StatusForbidden, t)
+		}
+		response, err := postRequest(url.Values{"email": {"test@example.com"}}, regularCookie, app, "/emails/test", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+
+	t.Run("Sent documents show the status of their delivery", func(t *testing.T) {
+		transport.failing = map[string]bool{"unavailable@example.com": true}
+		for _, address := range []string{"reader@example.com", "unavailable@example.com"} {
+			response, err := postRequest(url.Values{"email": {address}}, adminCookie, app, "/documents/"+testDocSlug+"/send", t)
+			if response == nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			mustReturnStatus(response, http.StatusOK, t)
+		}
+
+		statuses := func(t *testing.T) string {
+			t.Helper()
+
+			response, err := getRequest(adminCookie, app, "/users/admin/devices", t)
+			if response == nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			mustReturnStatus(response, http.StatusOK, t)
+			doc, err := goquery.NewDocumentFromReader(response.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+			return strings.Join(doc.Find("#sent-documents .sent-status").Map(func(_ int, s *goquery.Selection) string {
+				return s.Text()
+			}), ",")
+		}
+
+		if got := statuses(t); got != "Pending,Pending" {
+			t.Errorf("Expected queued documents to be shown as pending, got '%s'", got)
+		}
+
+		queue.Deliver(time.Now().Add(24 * time.Hour))
+		if got := statuses(t); got != "Failed,Sent" {
+			t.Errorf("Expected the status of each delivery to be shown, got '%s'", got)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	if webserver.RetryDelay(1) != webserver.MailRetryBaseDelay || webserver.RetryDelay(3) != 4*webserver.MailRetryBaseDelay {
+		t.Error("Expected retry delay to double after each attempt")
+	}
+	if webserver.RetryDelay(100) != webserver.MailRetryMaxDelay {
+		t.Error("Expected retry delay to be capped")
+	}
+}
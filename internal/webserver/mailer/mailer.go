@@ -0,0 +1,100 @@
+// Package mailer builds the emails sent by the application from the templates in the mail views folder,
+// translated to the language of their recipients and including a plain text version of their contents.
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/i18n"
+)
+
+// Layout is the template every email is rendered into
+const Layout = "mail/layout"
+
+// Templates are the names of the available email templates, relative to the mail views folder
+var Templates = []string{"invitation", "recovery", "share", "notification", "goal-reminder", "test"}
+
+// Inline is a file shown in the HTML body of an email, which refers to it as cid:<ContentID>
+type Inline struct {
+	ContentID string
+	Name      string
+	Data      []byte
+}
+
+// Message is an email ready to be sent
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string
+	Inline  []Inline
+}
+
+// Embed adds a file to be shown inline in the HTML body of the message
+func (m *Message) Embed(contentID, name string, data []byte) {
+	m.Inline = append(m.Inline, Inline{ContentID: contentID, Name: name, Data: data})
+}
+
+// Preview returns the HTML body of the message with its inline files embedded as data URIs,
+// so it can be shown in a browser
+func (m Message) Preview() string {
+	body := m.HTML
+	for _, inline := range m.Inline {
+		uri := "data:" + http.DetectContentType(inline.Data) + ";base64," + base64.StdEncoding.EncodeToString(inline.Data)
+		body = strings.ReplaceAll(body, "cid:"+inline.ContentID, uri)
+	}
+	return body
+}
+
+// Composer renders email templates
+type Composer struct {
+	views      fiber.Views
+	translator i18n.Translator
+}
+
+func NewComposer(views fiber.Views, translator i18n.Translator) *Composer {
+	return &Composer{
+		views:      views,
+		translator: translator,
+	}
+}
+
+// Language returns the language emails to a user who chose lang are written in,
+// which is english if lang is not supported
+func (c *Composer) Language(lang string) string {
+	if !slices.Contains(c.translator.SupportedLanguages(), lang) {
+		return "en"
+	}
+	return lang
+}
+
+// Compose renders the mail/<name> template inside the mail layout in the passed language, which is made available
+// to the template as Lang. The subject is a translation key formatted with values.
+func (c *Composer) Compose(name, lang string, vars fiber.Map, subject string, values ...any) (Message, error) {
+	lang = c.Language(lang)
+	message := Message{Subject: c.translator.T(lang, subject, values...)}
+
+	binding := fiber.Map{}
+	for key, value := range vars {
+		binding[key] = value
+	}
+	binding["Lang"] = lang
+	binding["Subject"] = message.Subject
+
+	var body bytes.Buffer
+	if err := c.views.Render(&body, "mail/"+name, binding, Layout); err != nil {
+		return Message{}, err
+	}
+	message.HTML = body.String()
+
+	text, err := PlainText(message.HTML)
+	if err != nil {
+		return Message{}, err
+	}
+	message.Text = text
+	return message, nil
+}
@@ -0,0 +1,108 @@
+package mailer
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// blocks are the elements whose contents are written in their own paragraph
+var blocks = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.Table: true, atom.Tr: true, atom.Ul: true, atom.Ol: true,
+}
+
+// PlainText returns the text of an HTML email body, to be sent as its plain text alternative.
+// Links are followed by their address between parentheses, and quotes are prefixed with "> ".
+func PlainText(body string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	writeText(&b, doc)
+	return tidy(b.String()), nil
+}
+
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(whitespace.ReplaceAllString(n.Data, " "))
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Head, atom.Style, atom.Script:
+			return
+		case atom.Br:
+			b.WriteString("\n")
+			return
+		case atom.Img:
+			b.WriteString(attribute(n, "alt"))
+			return
+		case atom.Li:
+			b.WriteString("\n- ")
+			writeChildren(b, n)
+			b.WriteString("\n")
+			return
+		case atom.A:
+			var label strings.Builder
+			writeChildren(&label, n)
+			b.WriteString(label.String())
+			href := attribute(n, "href")
+			if href != "" && href != strings.TrimSpace(label.String()) && !strings.HasPrefix(href, "cid:") {
+				b.WriteString(" (" + href + ")")
+			}
+			return
+		case atom.Blockquote:
+			var quote strings.Builder
+			writeChildren(&quote, n)
+			b.WriteString("\n\n")
+			for _, line := range strings.Split(tidy(quote.String()), "\n") {
+				b.WriteString("> " + line + "\n")
+			}
+			b.WriteString("\n")
+			return
+		}
+		if blocks[n.DataAtom] {
+			b.WriteString("\n\n")
+			writeChildren(b, n)
+			b.WriteString("\n\n")
+			return
+		}
+	}
+	writeChildren(b, n)
+}
+
+func writeChildren(b *strings.Builder, n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(b, child)
+	}
+}
+
+func attribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// tidy trims the lines of text and leaves at most one empty line between paragraphs
+func tidy(text string) string {
+	lines := strings.Split(text, "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" && (len(result) == 0 || result[len(result)-1] == "") {
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
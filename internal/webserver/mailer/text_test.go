@@ -0,0 +1,57 @@
+package mailer_test
+
+import (
+	"testing"
+
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+)
+
+func TestPlainText(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "Paragraphs are separated by an empty line",
+			body:     "<html><head><title>Subject</title><style>p {}</style></head><body><p>First\n    paragraph</p><p>Second</p></body></html>",
+			expected: "First paragraph\n\nSecond",
+		},
+		{
+			name:     "Links are followed by their address",
+			body:     `<p>Open <a href="http://example.com/documents/a">the document</a> or <a href="http://example.com">http://example.com</a></p>`,
+			expected: "Open the document (http://example.com/documents/a) or http://example.com",
+		},
+		{
+			name:     "Quotes are prefixed",
+			body:     "<p>John shared it</p><blockquote>Nice<br>read</blockquote><p>Bye</p>",
+			expected: "John shared it\n\n> Nice\n> read\n\nBye",
+		},
+		{
+			name:     "Inline images are replaced by their alternative text",
+			body:     `<p><img src="cid:cover" alt="Cover">Title</p>`,
+			expected: "CoverTitle",
+		},
+	}
+
+	for _, tcase := range cases {
+		t.Run(tcase.name, func(t *testing.T) {
+			text, err := mailer.PlainText(tcase.body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if text != tcase.expected {
+				t.Errorf("Expected '%s', got '%s'", tcase.expected, text)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	message := mailer.Message{HTML: `<img src="cid:cover">`}
+	message.Embed("cover", "cover.jpg", []byte("\xff\xd8\xff"))
+
+	if expected := `<img src="data:image/jpeg;base64,/9j/">`; message.Preview() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, message.Preview())
+	}
+}
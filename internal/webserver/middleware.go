@@ -87,8 +87,8 @@ func SetProgress(progress ProgressInfo) func(fiber.Ctx) error {
 }
 
 // AllowIfNotLoggedIn only allows processing the request if there is no session
-func AllowIfNotLoggedIn(jwtSecret []byte) func(fiber.Ctx) error {
-	return jwtware.New(jwtware.Config{
+func AllowIfNotLoggedIn(jwtSecret []byte, proxyAuthenticator *ProxyAuthenticator) func(fiber.Ctx) error {
+	jwtAuthentication := jwtware.New(jwtware.Config{
 		SigningKey: jwtware.SigningKey{JWTAlg: "HS256", Key: jwtSecret},
 		Extractor:  extractors.FromCookie("session"),
 		SuccessHandler: func(c fiber.Ctx) error {
@@ -98,12 +98,19 @@ func AllowIfNotLoggedIn(jwtSecret []byte) func(fiber.Ctx) error {
 			return c.Next()
 		},
 	})
+
+	return func(c fiber.Ctx) error {
+		if _, ok, _ := proxyAuthenticator.Session(c); ok {
+			return c.Redirect().To("/")
+		}
+		return jwtAuthentication(c)
+	}
 }
 
 // AlwaysRequireAuthentication returns forbidden and renders the login page
 // if the user trying to access has not logged in
-func AlwaysRequireAuthentication(jwtSecret []byte, sender Sender, translator i18n.Translator, usersRepository *model.UserRepository, versionChecker *versioncheck.Checker) func(fiber.Ctx) error {
-	return jwtware.New(jwtware.Config{
+func AlwaysRequireAuthentication(jwtSecret []byte, sender Sender, translator i18n.Translator, usersRepository *model.UserRepository, versionChecker *versioncheck.Checker, proxyAuthenticator *ProxyAuthenticator) func(fiber.Ctx) error {
+	jwtAuthentication := jwtware.New(jwtware.Config{
 		SigningKey: jwtware.SigningKey{JWTAlg: "HS256", Key: jwtSecret},
 		Extractor:  extractors.FromCookie("session"),
 		SuccessHandler: func(c fiber.Ctx) error {
@@ -114,21 +121,20 @@ func AlwaysRequireAuthentication(jwtSecret []byte, sender Sender, translator i18
 				}
 				return err
 			}
-			c.Locals("Session", session)
-			usersRepository.UpdateLastRequest(session.ID)
-			updateUserLanguage(c, usersRepository, session)
-			applyVersionUpdateNotice(c, versionChecker)
+			startSession(c, usersRepository, session, versionChecker)
 			return c.Next()
 		},
 		ErrorHandler: func(c fiber.Ctx, err error) error {
 			return forbidden(c, sender, translator, err)
 		},
 	})
+
+	return withProxyAuthentication(proxyAuthenticator, usersRepository, versionChecker, jwtAuthentication)
 }
 
 // ConfigurableAuthentication allows to enable or disable authentication on routes which may or may not require it
-func ConfigurableAuthentication(jwtSecret []byte, sender Sender, translator i18n.Translator, requireAuth bool, usersRepository *model.UserRepository, versionChecker *versioncheck.Checker) func(fiber.Ctx) error {
-	return jwtware.New(jwtware.Config{
+func ConfigurableAuthentication(jwtSecret []byte, sender Sender, translator i18n.Translator, requireAuth bool, usersRepository *model.UserRepository, versionChecker *versioncheck.Checker, proxyAuthenticator *ProxyAuthenticator) func(fiber.Ctx) error {
+	jwtAuthentication := jwtware.New(jwtware.Config{
 		SigningKey: jwtware.SigningKey{JWTAlg: "HS256", Key: jwtSecret},
 		Extractor:  extractors.FromCookie("session"),
 		SuccessHandler: func(c fiber.Ctx) error {
@@ -142,10 +148,7 @@ func ConfigurableAuthentication(jwtSecret []byte, sender Sender, translator i18n
 				}
 				return err
 			}
-			c.Locals("Session", session)
-			usersRepository.UpdateLastRequest(session.ID)
-			updateUserLanguage(c, usersRepository, session)
-			applyVersionUpdateNotice(c, versionChecker)
+			startSession(c, usersRepository, session, versionChecker)
 			return c.Next()
 		},
 		ErrorHandler: func(c fiber.Ctx, err error) error {
@@ -155,6 +158,32 @@ func ConfigurableAuthentication(jwtSecret []byte, sender Sender, translator i18n
 			return c.Next()
 		},
 	})
+
+	return withProxyAuthentication(proxyAuthenticator, usersRepository, versionChecker, jwtAuthentication)
+}
+
+// withProxyAuthentication starts a session straight from the reverse proxy headers if the request
+// comes from a trusted proxy, skipping the session cookie flow handled by jwtAuthentication
+func withProxyAuthentication(proxyAuthenticator *ProxyAuthenticator, usersRepository *model.UserRepository, versionChecker *versioncheck.Checker, jwtAuthentication fiber.Handler) func(fiber.Ctx) error {
+	return func(c fiber.Ctx) error {
+		session, ok, err := proxyAuthenticator.Session(c)
+		if err != nil {
+			log.Println(err)
+			return fiber.ErrInternalServerError
+		}
+		if !ok {
+			return jwtAuthentication(c)
+		}
+		startSession(c, usersRepository, session, versionChecker)
+		return c.Next()
+	}
+}
+
+func startSession(c fiber.Ctx, usersRepository *model.UserRepository, session model.Session, versionChecker *versioncheck.Checker) {
+	c.Locals("Session", session)
+	usersRepository.UpdateLastRequest(session.ID)
+	updateUserLanguage(c, usersRepository, session)
+	applyVersionUpdateNotice(c, versionChecker)
 }
 
 var errSessionRejected = errors.New("session rejected")
@@ -325,6 +354,10 @@ func SetActionPreferences(sender Sender) func(fiber.Ctx) error {
 			if !emailSendingConfigured || !canShare {
 				actualAction = "download"
 			}
+		case "queue":
+			if session.ID == 0 {
+				actualAction = "download"
+			}
 		}
 
 		// Compute preferred EPUB type
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// Kinds of activity shown in the feed of the users following someone
+const (
+	ActivityStarted     = "started"
+	ActivityFinished    = "finished"
+	ActivityHighlighted = "highlighted"
+	ActivityReviewed    = "reviewed"
+	ActivityShared      = "shared"
+)
+
+// Activity is something a user did with a document. It is only recorded for users who opted in
+// to share their activity and do not have a private profile.
+type Activity struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"index"`
+	UserID    int       `gorm:"index; not null"`
+	Type      string    `gorm:"not null"`
+	Slug      string    `gorm:"index; not null"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ActivityEntry is an activity along with the document it refers to
+type ActivityEntry struct {
+	Activity
+	Document index.Document
+}
+
+// Follow means that a user wants to see the activity of another one in their feed
+type Follow struct {
+	CreatedAt  time.Time
+	FollowerID int  `gorm:"primaryKey"`
+	FolloweeID int  `gorm:"primaryKey; index"`
+	Follower   User `gorm:"foreignKey:FollowerID; constraint:OnDelete:CASCADE"`
+	Followee   User `gorm:"foreignKey:FolloweeID; constraint:OnDelete:CASCADE"`
+}
+
+// SharesActivity tells whether the user's activity can be seen by other users
+func (u User) SharesActivity() bool {
+	return u.ShareActivity && u.PrivateProfile == 0
+}
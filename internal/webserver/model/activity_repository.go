@@ -0,0 +1,167 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/result"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activityRepeatWindow is the time during which repeating the same activity on a document is not recorded again,
+// so toggling something back and forth does not flood the feed
+const activityRepeatWindow = 24 * time.Hour
+
+// sharingActivity is the condition users must meet to have their activity shown to others
+const sharingActivity = `"User"."share_activity" = 1 AND "User"."private_profile" = 0`
+
+type ActivityRepository struct {
+	DB  *gorm.DB
+	Idx idxReader
+}
+
+// Record saves an activity of a user, as long as they share their activity with others.
+// Failures are logged but not returned, as activities are not essential to the actions that generate them.
+func (u *ActivityRepository) Record(userID int, activityType, documentSlug string) {
+	var count int64
+	err := u.DB.Model(&User{}).Where("id = ? AND share_activity = ? AND private_profile = 0", userID, true).Count(&count).Error
+	if err != nil || count == 0 {
+		return
+	}
+
+	err = u.DB.Model(&Activity{}).
+		Where("user_id = ? AND type = ? AND slug = ? AND created_at > ?", userID, activityType, documentSlug, time.Now().Add(-activityRepeatWindow)).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return
+	}
+
+	activity := Activity{UserID: userID, Type: activityType, Slug: documentSlug}
+	if err := u.DB.Create(&activity).Error; err != nil {
+		log.Printf("error recording activity: %s\n", err)
+	}
+}
+
+// Feed returns the activity of the users followed by a user, newest first
+func (u *ActivityRepository) Feed(followerID int, page int, resultsPerPage int) (result.Paginated[[]ActivityEntry], error) {
+	followees := u.DB.Model(&Follow{}).Select("followee_id").Where("follower_id = ?", followerID)
+	return u.paginated(u.DB.Where("activities.user_id IN (?)", followees), page, resultsPerPage)
+}
+
+// UserActivity returns the activity of a user, newest first. Nothing is returned if the user does not share it.
+func (u *ActivityRepository) UserActivity(userID int, page int, resultsPerPage int) (result.Paginated[[]ActivityEntry], error) {
+	return u.paginated(u.DB.Where("activities.user_id = ?", userID), page, resultsPerPage)
+}
+
+func (u *ActivityRepository) paginated(q *gorm.DB, page int, resultsPerPage int) (result.Paginated[[]ActivityEntry], error) {
+	if u.Idx == nil {
+		return result.Paginated[[]ActivityEntry]{}, errors.New("activity repository: idx required for activities")
+	}
+
+	q = q.Model(&Activity{}).Joins("User").Where(sharingActivity).Session(&gorm.Session{})
+
+	// Activities on documents no longer in the library are left out of both the page and the total
+	var slugs []string
+	if err := q.Distinct().Pluck("activities.slug", &slugs).Error; err != nil {
+		log.Printf("error listing activities: %s\n", err)
+		return result.Paginated[[]ActivityEntry]{}, err
+	}
+	docBySlug, err := u.Idx.Documents(slugs)
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return result.Paginated[[]ActivityEntry]{}, err
+	}
+	missing := []string{}
+	for _, slug := range slugs {
+		if _, ok := docBySlug[slug]; !ok {
+			missing = append(missing, slug)
+		}
+	}
+	if len(missing) > 0 {
+		q = q.Where("activities.slug NOT IN ?", missing)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		log.Printf("error counting activities: %s\n", err)
+		return result.Paginated[[]ActivityEntry]{}, err
+	}
+
+	var activities []Activity
+	if err := q.Scopes(Paginate(page, resultsPerPage)).Order("activities.created_at DESC, activities.id DESC").Find(&activities).Error; err != nil {
+		log.Printf("error listing activities: %s\n", err)
+		return result.Paginated[[]ActivityEntry]{}, err
+	}
+
+	entries := make([]ActivityEntry, 0, len(activities))
+	for _, a := range activities {
+		entries = append(entries, ActivityEntry{Activity: a, Document: docBySlug[a.Slug]})
+	}
+
+	return result.NewPaginated(resultsPerPage, page, int(total), entries), nil
+}
+
+func (u *ActivityRepository) Follow(followerID, followeeID int) error {
+	follow := Follow{FollowerID: followerID, FolloweeID: followeeID}
+	err := u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+	if err != nil {
+		log.Printf("error following user: %s\n", err)
+	}
+	return err
+}
+
+func (u *ActivityRepository) Unfollow(followerID, followeeID int) error {
+	err := u.DB.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&Follow{}).Error
+	if err != nil {
+		log.Printf("error unfollowing user: %s\n", err)
+	}
+	return err
+}
+
+// Follows tells whether a user follows another one
+func (u *ActivityRepository) Follows(followerID, followeeID int) bool {
+	var count int64
+	u.DB.Model(&Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count)
+	return count > 0
+}
+
+// Following returns the users followed by a user who do not have a private profile, sorted by name
+func (u *ActivityRepository) Following(followerID int) ([]User, error) {
+	users := []User{}
+	err := u.DB.Where("private_profile = 0 AND id IN (?)", u.DB.Model(&Follow{}).Select("followee_id").Where("follower_id = ?", followerID)).
+		Order("name").Find(&users).Error
+	if err != nil {
+		log.Printf("error listing followed users: %s\n", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// Followers returns how many users follow a user
+func (u *ActivityRepository) Followers(followeeID int) (int64, error) {
+	var count int64
+	if err := u.DB.Model(&Follow{}).Where("followee_id = ?", followeeID).Count(&count).Error; err != nil {
+		log.Printf("error counting followers: %s\n", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// Suggestions returns the users sharing their activity who are not followed yet by a user, sorted by name
+func (u *ActivityRepository) Suggestions(followerID int) ([]User, error) {
+	users := []User{}
+	err := u.DB.Where("share_activity = ? AND private_profile = 0 AND id <> ?", true, followerID).
+		Where("id NOT IN (?)", u.DB.Model(&Follow{}).Select("followee_id").Where("follower_id = ?", followerID)).
+		Order("name").Find(&users).Error
+	if err != nil {
+		log.Printf("error listing users sharing their activity: %s\n", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+func (u *ActivityRepository) RemoveDocument(documentSlug string) error {
+	return u.DB.Where("slug = ?", documentSlug).Delete(&Activity{}).Error
+}
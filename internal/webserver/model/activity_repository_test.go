@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/svera/coreander/v4/internal/index"
+	"gorm.io/gorm"
+)
+
+func TestUserActivitySkipsMissingDocumentsFromResultsAndTotal(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&User{}, &Activity{}); err != nil {
+		t.Fatal(err)
+	}
+	user := User{Name: "Reader", Username: "reader", Email: "reader@example.com", ShareActivity: true}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	repo := &ActivityRepository{DB: db, Idx: &latestInProgressIdxStub{docs: map[string]index.Document{
+		"a": {Slug: "a"},
+		"b": {Slug: "b"},
+	}}}
+
+	createdAt := time.Now().Add(-72 * time.Hour)
+	for _, slug := range []string{"a", "ghost", "b", "ghost"} {
+		createdAt = createdAt.Add(time.Hour)
+		activity := Activity{UserID: int(user.ID), Type: ActivityFinished, Slug: slug, CreatedAt: createdAt}
+		if err := db.Create(&activity).Error; err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	first, err := repo.UserActivity(int(user.ID), 1, 1)
+	if err != nil {
+		t.Fatalf("UserActivity: %v", err)
+	}
+	if first.TotalHits() != 2 {
+		t.Fatalf("total %d, want 2", first.TotalHits())
+	}
+	if hits := first.Hits(); len(hits) != 1 || hits[0].Slug != "b" || hits[0].Document.Slug != "b" {
+		t.Fatalf("first page %+v, want the activity on b", hits)
+	}
+
+	second, err := repo.UserActivity(int(user.ID), 2, 1)
+	if err != nil {
+		t.Fatalf("UserActivity: %v", err)
+	}
+	if hits := second.Hits(); len(hits) != 1 || hits[0].Slug != "a" {
+		t.Fatalf("second page %+v, want the activity on a", hits)
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Audit actions
+const (
+	AuditLogin          = "login"
+	AuditLoginFailed    = "login-failed"
+	AuditDocumentUpload = "document-upload"
+	AuditDocumentDelete = "document-delete"
+	AuditAuthorUpdate   = "author-update"
+	AuditAuthorImage    = "author-image-upload"
+	AuditUserCreate     = "user-create"
+	AuditUserUpdate     = "user-update"
+	AuditUserDelete     = "user-delete"
+	AuditUserInvite     = "user-invite"
+	AuditCommentHide    = "comment-hide"
+	AuditCommentUnhide  = "comment-unhide"
+)
+
+// AuditActions lists all the actions which are recorded in the audit log
+var AuditActions = []string{
+	AuditLogin,
+	AuditLoginFailed,
+	AuditDocumentUpload,
+	AuditDocumentDelete,
+	AuditAuthorUpdate,
+	AuditAuthorImage,
+	AuditUserCreate,
+	AuditUserUpdate,
+	AuditUserDelete,
+	AuditUserInvite,
+	AuditCommentHide,
+	AuditCommentUnhide,
+}
+
+// AuditEntry records an administrative or sensitive action. Actor and target are stored
+// as plain text instead of foreign keys, so entries are kept after users or documents are removed.
+type AuditEntry struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	CreatedAt time.Time `gorm:"index" json:"timestamp"`
+	Actor     string    `gorm:"index" json:"actor"`
+	Action    string    `gorm:"index; not null" json:"action"`
+	Target    string    `json:"target"`
+	Details   string    `json:"details,omitempty"`
+	IP        string    `json:"ip"`
+}
+
+// AuditFilter narrows down the audit entries returned by AuditRepository
+type AuditFilter struct {
+	Actor  string
+	Action string
+	From   time.Time
+	To     time.Time
+}
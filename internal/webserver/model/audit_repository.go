@@ -0,0 +1,74 @@
+package model
+
+import (
+	"log"
+
+	"github.com/svera/coreander/v4/internal/result"
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	DB *gorm.DB
+}
+
+func (a *AuditRepository) Create(entry *AuditEntry) error {
+	if result := a.DB.Create(entry); result.Error != nil {
+		log.Printf("error creating audit entry: %s\n", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// List returns the audit entries matching filter, newest first
+func (a *AuditRepository) List(filter AuditFilter, page int, resultsPerPage int) (result.Paginated[[]AuditEntry], error) {
+	var (
+		entries []AuditEntry
+		total   int64
+	)
+
+	if res := a.query(filter).Model(&AuditEntry{}).Count(&total); res.Error != nil {
+		log.Printf("error counting audit entries: %s\n", res.Error)
+		return result.Paginated[[]AuditEntry]{}, res.Error
+	}
+
+	res := a.query(filter).Scopes(Paginate(page, resultsPerPage)).Order("created_at DESC, id DESC").Find(&entries)
+	if res.Error != nil {
+		log.Printf("error listing audit entries: %s\n", res.Error)
+		return result.Paginated[[]AuditEntry]{}, res.Error
+	}
+
+	return result.NewPaginated(
+		resultsPerPage,
+		page,
+		int(total),
+		entries,
+	), nil
+}
+
+// All returns every audit entry matching filter, newest first
+func (a *AuditRepository) All(filter AuditFilter) ([]AuditEntry, error) {
+	var entries []AuditEntry
+
+	if res := a.query(filter).Order("created_at DESC, id DESC").Find(&entries); res.Error != nil {
+		log.Printf("error exporting audit entries: %s\n", res.Error)
+		return nil, res.Error
+	}
+	return entries, nil
+}
+
+func (a *AuditRepository) query(filter AuditFilter) *gorm.DB {
+	query := a.DB
+	if filter.Actor != "" {
+		query = query.Where("actor LIKE ?", "%"+filter.Actor+"%")
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	return query
+}
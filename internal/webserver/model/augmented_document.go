@@ -12,6 +12,7 @@ type AugmentedDocument struct {
 	Highlight         Highlight
 	CompletedOn       *time.Time
 	ReadingPercentage int
+	Rating            DocumentRating
 }
 
 func AugmentedDocumentsFromDocuments(results result.Paginated[[]index.Document]) result.Paginated[[]AugmentedDocument] {
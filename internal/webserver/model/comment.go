@@ -0,0 +1,59 @@
+package model
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CommentTextMaxLength is the maximum number of characters of a comment
+const CommentTextMaxLength = 2000
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_\-.])@([A-Za-z0-9_\-.]+)`)
+
+// Comment is a message a user writes in the discussion of a document. Comments can be replied to,
+// but replies cannot, so discussions are at most two levels deep.
+type Comment struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Slug      string `gorm:"index; not null"`
+	UserID    int    `gorm:"index; not null"`
+	// ParentID is the comment this one replies to, if any
+	ParentID *uint  `gorm:"index"`
+	Text     string `gorm:"type:text; not null"`
+	// Hidden comments have been moderated by an administrator, and their text is only shown to administrators
+	Hidden  bool      `gorm:"default:false; not null"`
+	User    User      `gorm:"constraint:OnDelete:CASCADE"`
+	Replies []Comment `gorm:"foreignKey:ParentID; constraint:OnDelete:CASCADE"`
+}
+
+// Validate checks all comment's fields to ensure they are in the required format
+func (c Comment) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if strings.TrimSpace(c.Text) == "" {
+		errs["text"] = "Comment cannot be empty"
+	}
+
+	if utf8.RuneCountInString(c.Text) > CommentTextMaxLength {
+		errs["text"] = "Comment cannot be longer than 2000 characters"
+	}
+
+	return errs
+}
+
+// Mentions returns the usernames mentioned in the comment with an @ before them, without duplicates
+func (c Comment) Mentions() []string {
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(c.Text, -1) {
+		// Mentions at the end of a sentence are followed by a full stop which is not part of the username
+		username := strings.TrimRight(match[1], ".")
+		if username != "" && !slices.Contains(mentions, username) {
+			mentions = append(mentions, username)
+		}
+	}
+	return mentions
+}
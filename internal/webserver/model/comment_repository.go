@@ -0,0 +1,73 @@
+package model
+
+import (
+	"errors"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	DB *gorm.DB
+}
+
+// Thread returns the comments of a document along with their replies, oldest first
+func (u *CommentRepository) Thread(documentSlug string) ([]Comment, error) {
+	comments := []Comment{}
+	err := u.DB.Preload("User").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		}).
+		Preload("Replies.User").
+		Where("slug = ? AND parent_id IS NULL", documentSlug).
+		Order("created_at, id").
+		Find(&comments).Error
+	if err != nil {
+		log.Printf("error listing comments: %s\n", err)
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Get returns a comment, or nil if it does not exist
+func (u *CommentRepository) Get(commentID uint) (*Comment, error) {
+	var comment Comment
+	if err := u.DB.First(&comment, commentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("error getting comment: %s\n", err)
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (u *CommentRepository) Create(comment *Comment) error {
+	if err := u.DB.Create(comment).Error; err != nil {
+		log.Printf("error creating comment: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+// Delete removes a comment along with its replies
+func (u *CommentRepository) Delete(commentID uint) error {
+	err := u.DB.Delete(&Comment{}, commentID).Error
+	if err != nil {
+		log.Printf("error deleting comment: %s\n", err)
+	}
+	return err
+}
+
+// Hide sets whether a comment's text is hidden from users other than administrators
+func (u *CommentRepository) Hide(commentID uint, hidden bool) error {
+	err := u.DB.Model(&Comment{}).Where("id = ?", commentID).Update("hidden", hidden).Error
+	if err != nil {
+		log.Printf("error hiding comment: %s\n", err)
+	}
+	return err
+}
+
+func (u *CommentRepository) RemoveDocument(documentSlug string) error {
+	return u.DB.Where("slug = ?", documentSlug).Delete(&Comment{}).Error
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommentValidate(t *testing.T) {
+	if errs := (Comment{Text: "Great"}).Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if errs := (Comment{Text: " \n "}).Validate(); errs["text"] == "" {
+		t.Error("Expected blank comments to be invalid")
+	}
+	if errs := (Comment{Text: strings.Repeat("ñ", CommentTextMaxLength)}).Validate(); len(errs) != 0 {
+		t.Errorf("Expected text length to be counted in characters, got %v", errs)
+	}
+	if errs := (Comment{Text: strings.Repeat("a", CommentTextMaxLength+1)}).Validate(); errs["text"] != "Comment cannot be longer than 2000 characters" {
+		t.Errorf("Expected too long comments to be invalid with the limit in the message, got '%s'", errs["text"])
+	}
+}
+
+func TestCommentMentions(t *testing.T) {
+	for _, tcase := range []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"No mentions", "Nice book", []string{}},
+		{"Mention at the start", "@john what do you think?", []string{"john"}},
+		{"Mention at the end of a sentence", "I agree with @mary.jane.", []string{"mary.jane"}},
+		{"Repeated mentions", "@john and @ann, @john again", []string{"john", "ann"}},
+		{"Email addresses are not mentions", "Write to john@example.com", []string{}},
+	} {
+		t.Run(tcase.name, func(t *testing.T) {
+			if got := (Comment{Text: tcase.text}).Mentions(); !slices.Equal(got, tcase.expected) {
+				t.Errorf("Expected %v, got %v", tcase.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DeviceNameMaxLength is the maximum number of characters of a device's name
+const DeviceNameMaxLength = 50
+
+// DeviceFormats are the formats documents can be sent in to a device. An empty format sends documents
+// in their original one.
+var DeviceFormats = []string{"", "epub", "kepub", "pdf", "azw3", "mobi"}
+
+// Device is an e-reader, such as a Kindle or a Kobo, users send documents to by email
+type Device struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uint   `gorm:"uniqueIndex:idx_device_name; not null"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	Name      string `gorm:"type:text collate nocase; uniqueIndex:idx_device_name; not null"`
+	Email     string `gorm:"not null"`
+	// Format documents are converted to before being sent, if possible
+	Format string `gorm:"not null; default:''"`
+	// MaxAttachmentSize is the size in megabytes of the largest file the device accepts, 0 meaning no limit
+	MaxAttachmentSize int `gorm:"not null; default:0"`
+}
+
+// Validate checks all device's fields to ensure they are in the required format
+func (d Device) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if strings.TrimSpace(d.Name) == "" {
+		errs["name"] = "Name cannot be empty"
+	}
+
+	if utf8.RuneCountInString(d.Name) > DeviceNameMaxLength {
+		errs["name"] = "Name cannot be longer than 50 characters"
+	}
+
+	if _, err := mail.ParseAddress(d.Email); err != nil {
+		errs["email"] = "Incorrect email address"
+	}
+
+	if len(d.Email) > 100 {
+		errs["email"] = "Email cannot be longer than 100 characters"
+	}
+
+	if !slices.Contains(DeviceFormats, d.Format) {
+		errs["format"] = "Invalid format"
+	}
+
+	if d.MaxAttachmentSize < 0 {
+		errs["maxattachmentsize"] = "Maximum attachment size cannot be negative"
+	}
+
+	return errs
+}
+
+// Fits tells whether a file of the passed size in bytes can be sent to the device
+func (d Device) Fits(size int) bool {
+	return d.MaxAttachmentSize == 0 || size <= d.MaxAttachmentSize*1024*1024
+}
+
+// SentDocument records a document sent by email by a user. The title, device name and address are copied,
+// so the history keeps making sense after documents or devices are removed. Documents sent through the mail
+// queue are linked to their delivery, which tells whether they actually reached the recipient.
+type SentDocument struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	UserID     uint   `gorm:"index; not null"`
+	User       User   `gorm:"constraint:OnDelete:CASCADE"`
+	DeviceID   *uint  `gorm:"index"`
+	DeviceName string `gorm:"not null; default:''"`
+	Email      string `gorm:"not null"`
+	Slug       string `gorm:"index; not null"`
+	Title      string `gorm:"not null"`
+	Format     string `gorm:"not null"`
+
+	EmailDeliveryID *uint          `gorm:"index"`
+	EmailDelivery   *EmailDelivery `gorm:"constraint:OnDelete:SET NULL"`
+}
+
+// Status returns the status of the delivery of the document. Documents not sent through the mail queue
+// were delivered right away.
+func (s SentDocument) Status() string {
+	if s.EmailDelivery == nil {
+		return EmailStatusSent
+	}
+	if s.EmailDelivery.Status == EmailStatusSending {
+		return EmailStatusPending
+	}
+	return s.EmailDelivery.Status
+}
+
+// DeviceChoice is one of the devices of a user, telling when a document was last sent to it, if ever
+type DeviceChoice struct {
+	Device
+	SentAt *time.Time
+}
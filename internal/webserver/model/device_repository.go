@@ -0,0 +1,120 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository struct {
+	DB *gorm.DB
+}
+
+// ByUser returns the devices of a user, sorted by name
+func (d *DeviceRepository) ByUser(userID uint) ([]Device, error) {
+	devices := []Device{}
+	if err := d.DB.Where("user_id = ?", userID).Order("name").Find(&devices).Error; err != nil {
+		log.Printf("error listing devices: %s\n", err)
+		return nil, err
+	}
+	return devices, nil
+}
+
+// Get returns the device with the passed ID as long as it belongs to the passed user, or nil otherwise
+func (d *DeviceRepository) Get(userID, deviceID uint) (*Device, error) {
+	var device Device
+	err := d.DB.Where("user_id = ? AND id = ?", userID, deviceID).First(&device).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("error getting device: %s\n", err)
+		return nil, err
+	}
+	return &device, nil
+}
+
+// Save creates a device, or replaces the user's existing one with the same name
+func (d *DeviceRepository) Save(device *Device) error {
+	result := d.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email", "format", "max_attachment_size", "updated_at"}),
+	}).Create(device)
+	if result.Error != nil {
+		log.Printf("error saving device: %s\n", result.Error)
+	}
+	return result.Error
+}
+
+// Delete removes the device with the passed ID, as long as it belongs to the passed user.
+// Documents already sent to it stay in the history.
+func (d *DeviceRepository) Delete(userID, deviceID uint) error {
+	return d.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND id = ?", userID, deviceID).Delete(&Device{})
+		if result.Error != nil {
+			log.Printf("error deleting device: %s\n", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&SentDocument{}).Where("device_id = ?", deviceID).Update("device_id", nil).Error; err != nil {
+			log.Printf("error detaching sent documents from device: %s\n", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// Choices returns the devices of a user, telling when the passed document was last sent to each one
+func (d *DeviceRepository) Choices(userID uint, documentSlug string) ([]DeviceChoice, error) {
+	devices, err := d.ByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent []SentDocument
+	err = d.DB.Where("user_id = ? AND slug = ? AND device_id IS NOT NULL", userID, documentSlug).
+		Where("email_delivery_id IS NULL OR email_delivery_id NOT IN (?)", d.DB.Model(&EmailDelivery{}).Select("id").Where("status = ?", EmailStatusFailed)).
+		Order("created_at DESC").
+		Find(&sent).Error
+	if err != nil {
+		log.Printf("error getting sent documents: %s\n", err)
+		return nil, err
+	}
+
+	sentAt := map[uint]*time.Time{}
+	for _, s := range sent {
+		if _, ok := sentAt[*s.DeviceID]; !ok {
+			sentAt[*s.DeviceID] = &s.CreatedAt
+		}
+	}
+
+	choices := make([]DeviceChoice, len(devices))
+	for i, device := range devices {
+		choices[i] = DeviceChoice{Device: device, SentAt: sentAt[device.ID]}
+	}
+	return choices, nil
+}
+
+// RecordSent adds a document to the history of documents sent by a user
+func (d *DeviceRepository) RecordSent(sent *SentDocument) error {
+	if err := d.DB.Create(sent).Error; err != nil {
+		log.Printf("error recording sent document: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+// Sent returns the last documents sent by a user along with their deliveries, newest first
+func (d *DeviceRepository) Sent(userID uint, limit int) ([]SentDocument, error) {
+	sent := []SentDocument{}
+	if err := d.DB.Preload("EmailDelivery").Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&sent).Error; err != nil {
+		log.Printf("error listing sent documents: %s\n", err)
+		return nil, err
+	}
+	return sent, nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+func TestDeviceValidate(t *testing.T) {
+	valid := Device{Name: "Kobo", Email: "kobo@example.com", Format: "kepub"}
+
+	var cases = []struct {
+		name          string
+		device        func(Device) Device
+		expectedError string
+	}{
+		{"Valid device", func(d Device) Device { return d }, ""},
+		{"Empty name", func(d Device) Device { d.Name = " "; return d }, "name"},
+		{"Wrong email", func(d Device) Device { d.Email = "kobo"; return d }, "email"},
+		{"Unknown format", func(d Device) Device { d.Format = "docx"; return d }, "format"},
+		{"Negative maximum attachment size", func(d Device) Device { d.MaxAttachmentSize = -1; return d }, "maxattachmentsize"},
+	}
+
+	for _, tcase := range cases {
+		t.Run(tcase.name, func(t *testing.T) {
+			errs := tcase.device(valid).Validate()
+			if tcase.expectedError == "" && len(errs) > 0 {
+				t.Errorf("Expected no errors, got %v", errs)
+			}
+			if tcase.expectedError != "" && errs[tcase.expectedError] == "" {
+				t.Errorf("Expected an error in %s, got %v", tcase.expectedError, errs)
+			}
+		})
+	}
+}
+
+func TestDeviceFits(t *testing.T) {
+	if !(Device{}).Fits(100 * 1024 * 1024) {
+		t.Error("Expected devices without limit to accept any file")
+	}
+	device := Device{MaxAttachmentSize: 1}
+	if !device.Fits(1024*1024) || device.Fits(1024*1024+1) {
+		t.Error("Expected files up to 1 MB to fit")
+	}
+}
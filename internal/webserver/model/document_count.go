@@ -0,0 +1,8 @@
+package model
+
+// DocumentCount holds how many times a document appears in an aggregation, such as
+// the number of users reading it
+type DocumentCount struct {
+	Slug  string
+	Count int
+}
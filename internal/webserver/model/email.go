@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Delivery statuses of outgoing emails
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// EmailStatuses are the delivery statuses admins can filter deliveries by
+var EmailStatuses = []string{EmailStatusPending, EmailStatusSent, EmailStatusFailed}
+
+// OutgoingEmail is a message queued to be sent to one or more recipients, with an HTML body and its plain text
+// version. Attachments and inline images are removed once the email has been delivered to all of them.
+type OutgoingEmail struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	Subject        string `gorm:"not null"`
+	Body           string `gorm:"not null"`
+	Text           string
+	AttachmentName string
+	Attachment     []byte
+	InlineImages   []EmailInlineImage `gorm:"constraint:OnDelete:CASCADE"`
+	Deliveries     []EmailDelivery    `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// EmailInlineImage is an image shown in the body of an outgoing email, which refers to it as cid:<ContentID>
+type EmailInlineImage struct {
+	ID              uint   `gorm:"primarykey"`
+	OutgoingEmailID uint   `gorm:"index; not null"`
+	ContentID       string `gorm:"not null"`
+	Name            string `gorm:"not null"`
+	Data            []byte
+}
+
+// EmailDelivery is the delivery of an outgoing email to one of its recipients. Each delivery is retried
+// on its own, so a failing address does not prevent the others from receiving the email.
+type EmailDelivery struct {
+	ID              uint `gorm:"primarykey"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	OutgoingEmailID uint `gorm:"index; not null"`
+	OutgoingEmail   OutgoingEmail
+	Address         string    `gorm:"not null"`
+	Status          string    `gorm:"index:idx_email_delivery_due; not null; default:pending"`
+	Attempts        int       `gorm:"not null; default:0"`
+	NextAttemptAt   time.Time `gorm:"index:idx_email_delivery_due"`
+	LastError       string
+	SentAt          *time.Time
+}
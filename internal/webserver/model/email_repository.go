@@ -0,0 +1,177 @@
+package model
+
+import (
+	"log"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/result"
+	"gorm.io/gorm"
+)
+
+type EmailRepository struct {
+	DB *gorm.DB
+}
+
+// Enqueue stores an email to be delivered to each of the passed addresses as soon as possible
+func (e *EmailRepository) Enqueue(email *OutgoingEmail, addresses []string) error {
+	now := time.Now()
+	email.Deliveries = make([]EmailDelivery, len(addresses))
+	for i, address := range addresses {
+		email.Deliveries[i] = EmailDelivery{
+			Address:       address,
+			Status:        EmailStatusPending,
+			NextAttemptAt: now,
+		}
+	}
+
+	if err := e.DB.Create(email).Error; err != nil {
+		log.Printf("error queueing email: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+// Claim marks as being sent up to limit pending deliveries whose next attempt is due, and returns them
+// along with their emails
+func (e *EmailRepository) Claim(now time.Time, limit int) ([]EmailDelivery, error) {
+	var deliveries []EmailDelivery
+
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(&EmailDelivery{}).
+			Where("status = ? AND next_attempt_at <= ?", EmailStatusPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := tx.Model(&EmailDelivery{}).Where("id IN ?", ids).Update("status", EmailStatusSending).Error; err != nil {
+			return err
+		}
+		return tx.Preload("OutgoingEmail.InlineImages").Where("id IN ?", ids).Order("next_attempt_at, id").Find(&deliveries).Error
+	})
+	if err != nil {
+		log.Printf("error claiming email deliveries: %s\n", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// MarkSent records a successful delivery, removing the attachment and inline images of its email if it has been
+// delivered to all its recipients
+func (e *EmailRepository) MarkSent(delivery EmailDelivery, sentAt time.Time) error {
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&EmailDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+			"status":     EmailStatusSent,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": "",
+			"sent_at":    sentAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var undelivered int64
+		err = tx.Model(&EmailDelivery{}).
+			Where("outgoing_email_id = ? AND status <> ?", delivery.OutgoingEmailID, EmailStatusSent).
+			Count(&undelivered).Error
+		if err != nil || undelivered > 0 {
+			return err
+		}
+		if err := tx.Where("outgoing_email_id = ?", delivery.OutgoingEmailID).Delete(&EmailInlineImage{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&OutgoingEmail{}).Where("id = ?", delivery.OutgoingEmailID).Update("attachment", nil).Error
+	})
+	if err != nil {
+		log.Printf("error updating email delivery: %s\n", err)
+	}
+	return err
+}
+
+// MarkFailed records a failed delivery attempt. The delivery is tried again at nextAttemptAt,
+// or given up if it is nil.
+func (e *EmailRepository) MarkFailed(delivery EmailDelivery, reason string, nextAttemptAt *time.Time) error {
+	values := map[string]any{
+		"status":     EmailStatusFailed,
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": reason,
+	}
+	if nextAttemptAt != nil {
+		values["status"] = EmailStatusPending
+		values["next_attempt_at"] = *nextAttemptAt
+	}
+
+	if err := e.DB.Model(&EmailDelivery{}).Where("id = ?", delivery.ID).Updates(values).Error; err != nil {
+		log.Printf("error updating email delivery: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+// ResetInterrupted makes pending again the deliveries left being sent when the process stopped
+func (e *EmailRepository) ResetInterrupted() error {
+	if err := e.DB.Model(&EmailDelivery{}).Where("status = ?", EmailStatusSending).Update("status", EmailStatusPending).Error; err != nil {
+		log.Printf("error resetting interrupted email deliveries: %s\n", err)
+		return err
+	}
+	return nil
+}
+
+// Retry queues again a failed delivery, starting its attempts from scratch
+func (e *EmailRepository) Retry(deliveryID uint) (bool, error) {
+	result := e.DB.Model(&EmailDelivery{}).
+		Where("id = ? AND status = ?", deliveryID, EmailStatusFailed).
+		Updates(map[string]any{
+			"status":          EmailStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Printf("error retrying email delivery: %s\n", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List returns the deliveries with the passed status, or all of them if it is empty, newest first.
+// Email bodies and attachments are not loaded.
+func (e *EmailRepository) List(status string, page int, resultsPerPage int) (result.Paginated[[]EmailDelivery], error) {
+	var (
+		deliveries []EmailDelivery
+		total      int64
+	)
+
+	if res := e.query(status).Count(&total); res.Error != nil {
+		log.Printf("error counting email deliveries: %s\n", res.Error)
+		return result.Paginated[[]EmailDelivery]{}, res.Error
+	}
+
+	res := e.query(status).
+		Preload("OutgoingEmail", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "created_at", "subject", "attachment_name")
+		}).
+		Scopes(Paginate(page, resultsPerPage)).
+		Order("created_at DESC, id DESC").
+		Find(&deliveries)
+	if res.Error != nil {
+		log.Printf("error listing email deliveries: %s\n", res.Error)
+		return result.Paginated[[]EmailDelivery]{}, res.Error
+	}
+
+	return result.NewPaginated(
+		resultsPerPage,
+		page,
+		int(total),
+		deliveries,
+	), nil
+}
+
+func (e *EmailRepository) query(status string) *gorm.DB {
+	query := e.DB.Model(&EmailDelivery{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	return query
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// Reading goal periods
+const (
+	GoalPeriodYear  = "year"
+	GoalPeriodMonth = "month"
+)
+
+// Reading goal units
+const (
+	GoalUnitDocuments = "documents"
+	GoalUnitWords     = "words"
+)
+
+var (
+	GoalPeriods = []string{GoalPeriodYear, GoalPeriodMonth}
+	GoalUnits   = []string{GoalUnitDocuments, GoalUnitWords}
+)
+
+// ReadingGoal is a target of documents or words to be read every year or month. Users can have one goal per period.
+type ReadingGoal struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uint   `gorm:"uniqueIndex:idx_reading_goal_period; not null"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+	Period    string `gorm:"uniqueIndex:idx_reading_goal_period; not null"`
+	Unit      string `gorm:"not null"`
+	Target    int    `gorm:"not null"`
+	// Reminders enables sending emails to the user when falling behind the pace needed to reach the goal
+	Reminders  bool `gorm:"default:false; not null"`
+	RemindedAt *time.Time
+}
+
+// Validate checks all goal's fields to ensure they are in the required format
+func (g ReadingGoal) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if !slices.Contains(GoalPeriods, g.Period) {
+		errs["period"] = "Invalid period"
+	}
+
+	if !slices.Contains(GoalUnits, g.Unit) {
+		errs["unit"] = "Invalid unit"
+	}
+
+	if g.Target < 1 {
+		errs["target"] = "Target must be greater than 0"
+	}
+
+	return errs
+}
+
+// Bounds returns the start and end of the goal's period which includes the passed time
+func (g ReadingGoal) Bounds(now time.Time) (time.Time, time.Time) {
+	now = now.Local()
+	if g.Period == GoalPeriodMonth {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// GoalProgress holds how much of a goal has been achieved in the current period
+type GoalProgress struct {
+	ReadingGoal
+	Start    time.Time
+	End      time.Time
+	Progress float64
+	// Expected is what should have been read by now to reach the target at a steady pace
+	Expected   float64
+	Percentage int
+}
+
+// Behind tells whether the user is not keeping up with the pace needed to reach the goal
+func (g GoalProgress) Behind() bool {
+	return g.Progress < math.Floor(g.Expected)
+}
+
+// Achieved tells whether the goal target has been reached
+func (g GoalProgress) Achieved() bool {
+	return g.Progress >= float64(g.Target)
+}
+
+// NewGoalProgress calculates the progress of a goal at the given time from the completed read-throughs of its user.
+// Read-throughs completed outside the current period of the goal are ignored, as well as those whose document
+// is no longer in the library when the goal unit is words.
+func NewGoalProgress(goal ReadingGoal, readThroughs []ReadThrough, documents map[string]index.Document, now time.Time) GoalProgress {
+	start, end := goal.Bounds(now)
+	progress := GoalProgress{
+		ReadingGoal: goal,
+		Start:       start,
+		End:         end,
+		Expected:    float64(goal.Target) * float64(now.Sub(start)) / float64(end.Sub(start)),
+	}
+
+	for _, readThrough := range readThroughs {
+		if readThrough.CompletedOn == nil || readThrough.CompletedOn.Before(start) || !readThrough.CompletedOn.Before(end) {
+			continue
+		}
+		if goal.Unit == GoalUnitDocuments {
+			progress.Progress++
+			continue
+		}
+		if doc, ok := documents[readThrough.Slug]; ok {
+			progress.Progress += doc.Words
+		}
+	}
+
+	progress.Percentage = min(100, int(progress.Progress*100/float64(goal.Target)))
+	return progress
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GoalRepository struct {
+	DB  *gorm.DB
+	Idx idxReader
+}
+
+func (g *GoalRepository) ByUser(userID uint) ([]ReadingGoal, error) {
+	var goals []ReadingGoal
+
+	result := g.DB.Where("user_id = ?", userID).Order("period DESC").Find(&goals)
+	if result.Error != nil {
+		log.Printf("error listing reading goals: %s\n", result.Error)
+		return nil, result.Error
+	}
+	return goals, nil
+}
+
+// Save creates a goal, or replaces the user's existing one for the same period
+func (g *GoalRepository) Save(goal *ReadingGoal) error {
+	result := g.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "period"}},
+		DoUpdates: clause.AssignmentColumns([]string{"unit", "target", "reminders", "reminded_at", "updated_at"}),
+	}).Create(goal)
+	if result.Error != nil {
+		log.Printf("error saving reading goal: %s\n", result.Error)
+	}
+	return result.Error
+}
+
+// Delete removes the goal with the passed ID, as long as it belongs to the passed user
+func (g *GoalRepository) Delete(userID, goalID uint) error {
+	result := g.DB.Where("user_id = ? AND id = ?", userID, goalID).Delete(&ReadingGoal{})
+	if result.Error != nil {
+		log.Printf("error deleting reading goal: %s\n", result.Error)
+	}
+	return result.Error
+}
+
+// Progress returns the progress of all the user's goals at the given time
+func (g *GoalRepository) Progress(userID uint, now time.Time) ([]GoalProgress, error) {
+	goals, err := g.ByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return g.progress(goals, now)
+}
+
+// PendingReminders returns the progress of the goals with reminders enabled whose users are behind pace
+// and have not been reminded in the last interval. Goals are returned with their user loaded.
+func (g *GoalRepository) PendingReminders(now time.Time, interval time.Duration) ([]GoalProgress, error) {
+	var goals []ReadingGoal
+
+	result := g.DB.Preload("User").
+		Where("reminders = ? AND (reminded_at IS NULL OR reminded_at < ?)", true, now.Add(-interval)).
+		Order("user_id ASC").
+		Find(&goals)
+	if result.Error != nil {
+		log.Printf("error listing reading goals with reminders: %s\n", result.Error)
+		return nil, result.Error
+	}
+
+	progress, err := g.progress(goals, now)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(progress, func(p GoalProgress) bool { return !p.Behind() }), nil
+}
+
+func (g *GoalRepository) MarkReminded(goalID uint, remindedAt time.Time) error {
+	result := g.DB.Model(&ReadingGoal{}).Where("id = ?", goalID).UpdateColumn("reminded_at", remindedAt)
+	if result.Error != nil {
+		log.Printf("error updating reading goal reminder date: %s\n", result.Error)
+	}
+	return result.Error
+}
+
+func (g *GoalRepository) progress(goals []ReadingGoal, now time.Time) ([]GoalProgress, error) {
+	progress := make([]GoalProgress, 0, len(goals))
+	readThroughs := map[uint][]ReadThrough{}
+
+	for _, goal := range goals {
+		if _, ok := readThroughs[goal.UserID]; !ok {
+			// A year period always includes the month one, so a single query per user is enough
+			start, end := ReadingGoal{Period: GoalPeriodYear}.Bounds(now)
+			var userReadThroughs []ReadThrough
+			result := g.DB.Where("user_id = ? AND completed_on >= ? AND completed_on < ?", goal.UserID, start, end).Find(&userReadThroughs)
+			if result.Error != nil {
+				log.Printf("error getting completed read-throughs: %s\n", result.Error)
+				return nil, result.Error
+			}
+			readThroughs[goal.UserID] = userReadThroughs
+		}
+
+		docBySlug, err := g.documents(goal, readThroughs[goal.UserID])
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, NewGoalProgress(goal, readThroughs[goal.UserID], docBySlug, now))
+	}
+
+	return progress, nil
+}
+
+// documents returns the documents needed to count the words read towards a goal
+func (g *GoalRepository) documents(goal ReadingGoal, readThroughs []ReadThrough) (map[string]index.Document, error) {
+	if goal.Unit != GoalUnitWords || len(readThroughs) == 0 {
+		return nil, nil
+	}
+	if g.Idx == nil {
+		return nil, errors.New("goal repository: idx required for words goals")
+	}
+
+	slugs := make([]string, 0, len(readThroughs))
+	for _, r := range readThroughs {
+		slugs = append(slugs, r.Slug)
+	}
+	slices.Sort(slugs)
+	docBySlug, err := g.Idx.Documents(slices.Compact(slugs))
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+	}
+	return docBySlug, err
+}
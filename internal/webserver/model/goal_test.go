@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/metadata"
+)
+
+func TestNewGoalProgress(t *testing.T) {
+	documents := map[string]index.Document{
+		"a": {Slug: "a", Metadata: metadata.Metadata{Words: 1000}},
+		"b": {Slug: "b", Metadata: metadata.Metadata{Words: 3000}},
+	}
+	readThroughs := []ReadThrough{
+		completedReadThrough("a", date(2025, time.January, 1), date(2025, time.January, 20)),
+		completedReadThrough("b", date(2025, time.February, 1), date(2025, time.March, 2)),
+		completedReadThrough("c", date(2024, time.May, 1), date(2024, time.December, 2)),
+	}
+	now := date(2025, time.March, 16)
+
+	t.Run("Yearly goal of documents", func(t *testing.T) {
+		progress := NewGoalProgress(ReadingGoal{Period: GoalPeriodYear, Unit: GoalUnitDocuments, Target: 4}, readThroughs, documents, now)
+
+		if progress.Progress != 2 || progress.Percentage != 50 {
+			t.Errorf("Expected 2 documents read (50%%), got %.0f (%d%%)", progress.Progress, progress.Percentage)
+		}
+		if progress.Behind() || progress.Achieved() {
+			t.Error("Expected goal to be on track")
+		}
+	})
+
+	t.Run("Monthly goal of words", func(t *testing.T) {
+		progress := NewGoalProgress(ReadingGoal{Period: GoalPeriodMonth, Unit: GoalUnitWords, Target: 10000}, readThroughs, documents, now)
+
+		if progress.Progress != 3000 || progress.Percentage != 30 {
+			t.Errorf("Expected 3000 words read (30%%), got %.0f (%d%%)", progress.Progress, progress.Percentage)
+		}
+		if !progress.Behind() {
+			t.Errorf("Expected goal to be behind pace, should have read %.0f words", progress.Expected)
+		}
+	})
+
+	t.Run("Achieved goal", func(t *testing.T) {
+		progress := NewGoalProgress(ReadingGoal{Period: GoalPeriodMonth, Unit: GoalUnitDocuments, Target: 1}, readThroughs, documents, now)
+
+		if !progress.Achieved() || progress.Behind() || progress.Percentage != 100 {
+			t.Errorf("Expected goal to be achieved, got %+v", progress)
+		}
+	})
+}
+
+func TestReadingGoalValidate(t *testing.T) {
+	errs := ReadingGoal{Period: "week", Unit: "pages", Target: 0}.Validate()
+	for _, field := range []string{"period", "unit", "target"} {
+		if _, ok := errs[field]; !ok {
+			t.Errorf("Expected an error for %s", field)
+		}
+	}
+	if errs := (ReadingGoal{Period: GoalPeriodYear, Unit: GoalUnitWords, Target: 1}).Validate(); len(errs) > 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
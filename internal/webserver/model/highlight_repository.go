@@ -90,6 +90,19 @@ func (u *HighlightRepository) Total(userID int) (int, error) {
 	return int(total), nil
 }
 
+// MostHighlighted returns the slugs of the documents highlighted by more users.
+// Documents shared with a user are not taken into account.
+func (u *HighlightRepository) MostHighlighted(limit int) ([]DocumentCount, error) {
+	var counts []DocumentCount
+
+	res := u.DB.Table("highlights").Select("slug, COUNT(*) AS count").Where("shared_by_id IS NULL").Group("slug").Order("count DESC, slug ASC").Limit(limit).Scan(&counts)
+	if res.Error != nil {
+		log.Printf("error counting highlights: %s\n", res.Error)
+		return nil, res.Error
+	}
+	return counts, nil
+}
+
 func (u *HighlightRepository) HighlightedPaginatedResult(userID int, results result.Paginated[[]AugmentedDocument]) result.Paginated[[]AugmentedDocument] {
 	highlightsBySlug := map[string]Highlight{}
 	slugs := make([]string, 0, len(results.Hits()))
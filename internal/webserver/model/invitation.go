@@ -8,4 +8,6 @@ type Invitation struct {
 	Email      string `gorm:"uniqueIndex; not null"`
 	UUID       string `gorm:"uniqueIndex; not null"`
 	ValidUntil time.Time
+	// InviterID is the user who sent the invitation, who is notified when it is accepted
+	InviterID int
 }
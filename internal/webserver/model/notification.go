@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+)
+
+// Kinds of notifications users can receive
+const (
+	NotificationShared             = "shared"
+	NotificationInvitationAccepted = "invitation-accepted"
+	NotificationNewInSeries        = "new-in-series"
+	NotificationNewVersion         = "new-version"
+	NotificationMentioned          = "mentioned"
+)
+
+// NotificationTypes lists all kinds of notifications, in the order they are shown in the preferences form
+var NotificationTypes = []string{
+	NotificationShared,
+	NotificationMentioned,
+	NotificationNewInSeries,
+	NotificationInvitationAccepted,
+	NotificationNewVersion,
+}
+
+// notificationEmailDefaults tells which kinds of notifications are also sent by email to users who did not
+// set their preferences. Shares were emailed before notifications existed, so they keep being emailed.
+var notificationEmailDefaults = map[string]bool{
+	NotificationShared: true,
+}
+
+// Notification tells a user about something that happened in the library
+type Notification struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"index"`
+	UserID    int       `gorm:"index; not null"`
+	Type      string    `gorm:"not null"`
+	// Actor is the name of the user who caused the notification, if any
+	Actor string
+	// Subject is the series name, version number or username the notification is about, depending on its type
+	Subject string
+	// Slug and Title identify the document the notification is about, if any
+	Slug  string
+	Title string
+	// Link is where the user is taken when opening the notification
+	Link   string `gorm:"not null"`
+	ReadAt *time.Time
+	// EmailPending is true while the notification waits to be sent by email
+	EmailPending bool `gorm:"index; default:false; not null"`
+	User         User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// NotificationPreference tells whether a user wants to receive a kind of notification by email
+// besides seeing it in the notification center
+type NotificationPreference struct {
+	UserID int    `gorm:"primaryKey"`
+	Type   string `gorm:"primaryKey"`
+	Email  bool   `gorm:"not null"`
+	User   User   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// SeriesFollow means that a user wants to be notified when new documents of a series are added to the library
+type SeriesFollow struct {
+	CreatedAt time.Time
+	UserID    int    `gorm:"primaryKey"`
+	Series    string `gorm:"primaryKey; index"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Unread tells whether the user has not read the notification yet
+func (n Notification) Unread() bool {
+	return n.ReadAt == nil
+}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/result"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+// Notify sends a notification to every passed user. The notification is queued to be emailed to the users
+// who want to receive its kind by email, unless it has already been emailed by the caller. Failures are only logged.
+func (u *NotificationRepository) Notify(userIDs []int, notification Notification, emailed bool) {
+	for _, userID := range userIDs {
+		n := notification
+		n.UserID = userID
+		n.EmailPending = !emailed && u.WantsEmail(userID, n.Type)
+		if err := u.DB.Create(&n).Error; err != nil {
+			log.Printf("error creating notification: %s\n", err)
+		}
+	}
+}
+
+// NotifyAdmins sends a notification to all administrators who have not received one of the same kind
+// and subject yet
+func (u *NotificationRepository) NotifyAdmins(notification Notification) {
+	var adminIDs []int
+	err := u.DB.Model(&User{}).
+		Where("role = ? AND id NOT IN (?)", RoleAdmin, u.DB.Model(&Notification{}).Select("user_id").Where("type = ? AND subject = ?", notification.Type, notification.Subject)).
+		Pluck("id", &adminIDs).Error
+	if err != nil {
+		log.Printf("error getting administrators to notify: %s\n", err)
+		return
+	}
+	u.Notify(adminIDs, notification, false)
+}
+
+// WantsEmail tells whether a user wants to receive a kind of notification by email
+func (u *NotificationRepository) WantsEmail(userID int, notificationType string) bool {
+	var preference NotificationPreference
+	err := u.DB.Where("user_id = ? AND type = ?", userID, notificationType).First(&preference).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("error getting notification preference: %s\n", err)
+		}
+		return notificationEmailDefaults[notificationType]
+	}
+	return preference.Email
+}
+
+// EmailPreferences returns whether a user wants to receive each kind of notification by email, indexed by kind
+func (u *NotificationRepository) EmailPreferences(userID int) (map[string]bool, error) {
+	preferences := make(map[string]bool, len(NotificationTypes))
+	for _, notificationType := range NotificationTypes {
+		preferences[notificationType] = notificationEmailDefaults[notificationType]
+	}
+
+	var rows []NotificationPreference
+	if err := u.DB.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		log.Printf("error getting notification preferences: %s\n", err)
+		return preferences, err
+	}
+	for _, row := range rows {
+		preferences[row.Type] = row.Email
+	}
+	return preferences, nil
+}
+
+// SaveEmailPreferences stores which kinds of notifications a user wants to receive by email.
+// Kinds not present in the passed map are not sent by email.
+func (u *NotificationRepository) SaveEmailPreferences(userID int, email map[string]bool) error {
+	preferences := make([]NotificationPreference, 0, len(NotificationTypes))
+	for _, notificationType := range NotificationTypes {
+		preferences = append(preferences, NotificationPreference{UserID: userID, Type: notificationType, Email: email[notificationType]})
+	}
+
+	err := u.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"email"}),
+	}).Create(&preferences).Error
+	if err != nil {
+		log.Printf("error saving notification preferences: %s\n", err)
+	}
+	return err
+}
+
+// Notifications returns the notifications of a user, newest first
+func (u *NotificationRepository) Notifications(userID int, page int, resultsPerPage int) (result.Paginated[[]Notification], error) {
+	var notifications []Notification
+	err := u.DB.Scopes(Paginate(page, resultsPerPage)).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&notifications).Error
+	if err != nil {
+		log.Printf("error listing notifications: %s\n", err)
+		return result.Paginated[[]Notification]{}, err
+	}
+
+	var total int64
+	if err := u.DB.Model(&Notification{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		log.Printf("error counting notifications: %s\n", err)
+		return result.Paginated[[]Notification]{}, err
+	}
+
+	return result.NewPaginated(resultsPerPage, page, int(total), notifications), nil
+}
+
+// Get returns a notification of a user, or nil if it does not exist
+func (u *NotificationRepository) Get(userID int, notificationID uint) (*Notification, error) {
+	var notification Notification
+	err := u.DB.Where("user_id = ? AND id = ?", userID, notificationID).First(&notification).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("error getting notification: %s\n", err)
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// Unread returns how many notifications a user has not read yet
+func (u *NotificationRepository) Unread(userID int) int64 {
+	var count int64
+	if err := u.DB.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error; err != nil {
+		log.Printf("error counting unread notifications: %s\n", err)
+	}
+	return count
+}
+
+func (u *NotificationRepository) MarkRead(userID int, notificationID uint) error {
+	err := u.DB.Model(&Notification{}).Where("user_id = ? AND id = ? AND read_at IS NULL", userID, notificationID).Update("read_at", time.Now().UTC()).Error
+	if err != nil {
+		log.Printf("error marking notification as read: %s\n", err)
+	}
+	return err
+}
+
+func (u *NotificationRepository) MarkAllRead(userID int) error {
+	err := u.DB.Model(&Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", time.Now().UTC()).Error
+	if err != nil {
+		log.Printf("error marking notifications as read: %s\n", err)
+	}
+	return err
+}
+
+// PendingEmails returns the notifications waiting to be sent by email, along with their users
+func (u *NotificationRepository) PendingEmails() ([]Notification, error) {
+	var notifications []Notification
+	if err := u.DB.Preload("User").Where("email_pending = ?", true).Order("id").Find(&notifications).Error; err != nil {
+		log.Printf("error getting pending notification emails: %s\n", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (u *NotificationRepository) MarkEmailed(notificationID uint) error {
+	return u.DB.Model(&Notification{}).Where("id = ?", notificationID).Update("email_pending", false).Error
+}
+
+// FollowSeries makes a user be notified about new documents of a series
+func (u *NotificationRepository) FollowSeries(userID int, series string) error {
+	follow := SeriesFollow{UserID: userID, Series: series}
+	err := u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+	if err != nil {
+		log.Printf("error following series: %s\n", err)
+	}
+	return err
+}
+
+func (u *NotificationRepository) UnfollowSeries(userID int, series string) error {
+	err := u.DB.Where("user_id = ? AND series = ?", userID, series).Delete(&SeriesFollow{}).Error
+	if err != nil {
+		log.Printf("error unfollowing series: %s\n", err)
+	}
+	return err
+}
+
+// FollowsSeries tells whether a user is notified about new documents of a series
+func (u *NotificationRepository) FollowsSeries(userID int, series string) bool {
+	var count int64
+	u.DB.Model(&SeriesFollow{}).Where("user_id = ? AND series = ?", userID, series).Count(&count)
+	return count > 0
+}
+
+// SeriesFollowers returns the IDs of the users who follow a series
+func (u *NotificationRepository) SeriesFollowers(series string) ([]int, error) {
+	var userIDs []int
+	if err := u.DB.Model(&SeriesFollow{}).Where("series = ?", series).Pluck("user_id", &userIDs).Error; err != nil {
+		log.Printf("error getting series followers: %s\n", err)
+		return nil, err
+	}
+	return userIDs, nil
+}
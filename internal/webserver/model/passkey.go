@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Passkey stores a WebAuthn credential registered by a user. Credential holds the
+// JSON encoded credential as returned by the WebAuthn library, including its public key
+// and sign counter.
+type Passkey struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uint   `gorm:"index; not null"`
+	Name         string `gorm:"not null"`
+	CredentialID []byte `gorm:"uniqueIndex; not null"`
+	Credential   []byte `gorm:"not null"`
+	LastUsedAt   time.Time
+}
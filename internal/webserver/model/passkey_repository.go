@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type PasskeyRepository struct {
+	DB *gorm.DB
+}
+
+func (p *PasskeyRepository) ByUser(userID uint) ([]Passkey, error) {
+	var passkeys []Passkey
+
+	result := p.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&passkeys)
+	if result.Error != nil {
+		log.Printf("error listing passkeys: %s\n", result.Error)
+		return nil, result.Error
+	}
+	return passkeys, nil
+}
+
+func (p *PasskeyRepository) FindByCredentialID(credentialID []byte) (*Passkey, error) {
+	var passkey Passkey
+
+	result := p.DB.Where("credential_id = ?", credentialID).First(&passkey)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &passkey, result.Error
+}
+
+func (p *PasskeyRepository) Create(passkey *Passkey) error {
+	if result := p.DB.Create(passkey); result.Error != nil {
+		log.Printf("error creating passkey: %s\n", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (p *PasskeyRepository) Update(passkey *Passkey) error {
+	if result := p.DB.Save(passkey); result.Error != nil {
+		log.Printf("error updating passkey: %s\n", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// Delete removes the passkey with the passed ID, as long as it belongs to the passed user
+func (p *PasskeyRepository) Delete(userID, passkeyID uint) error {
+	result := p.DB.Where("user_id = ? AND id = ?", userID, passkeyID).Delete(&Passkey{})
+	if result.Error != nil {
+		log.Printf("error deleting passkey: %s\n", result.Error)
+	}
+	return result.Error
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// QueuedDocument is a document a user wants to read. Position sets its priority in the user's queue, starting at 0.
+type QueuedDocument struct {
+	CreatedAt time.Time
+	UserID    int    `gorm:"primaryKey"`
+	Slug      string `gorm:"primaryKey; index"`
+	Position  int    `gorm:"not null"`
+}
+
+// UpNext is the document suggested to be read after finishing another one, either because it is the first one
+// in the user's queue or because it follows the finished one in its series
+type UpNext struct {
+	index.Document
+	Reason string
+}
+
+const (
+	UpNextFromQueue  = "queue"
+	UpNextFromSeries = "series"
+)
+
+// moveSlug returns the passed slugs with one of them placed at a new position. Positions out of range
+// move it to the start or the end. The second returned value is false if the slug is not in the list.
+func moveSlug(slugs []string, slug string, position int) ([]string, bool) {
+	current := slices.Index(slugs, slug)
+	if current == -1 {
+		return slugs, false
+	}
+	moved := slices.Delete(slices.Clone(slugs), current, current+1)
+	position = min(max(position, 0), len(moved))
+	return slices.Insert(moved, position, slug), true
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"errors"
+	"log"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QueueRepository struct {
+	DB  *gorm.DB
+	Idx idxReader
+}
+
+// Add places a document at the end of a user's queue. Documents already in the queue are left where they are.
+func (u *QueueRepository) Add(userID int, documentSlug string) error {
+	var last int
+	err := u.DB.Model(&QueuedDocument{}).Select("COALESCE(MAX(position) + 1, 0)").Where("user_id = ?", userID).Scan(&last).Error
+	if err != nil {
+		log.Printf("error adding document to queue: %s\n", err)
+		return err
+	}
+
+	queued := QueuedDocument{UserID: userID, Slug: documentSlug, Position: last}
+	if err = u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&queued).Error; err != nil {
+		log.Printf("error adding document to queue: %s\n", err)
+	}
+	return err
+}
+
+func (u *QueueRepository) Remove(userID int, documentSlug string) error {
+	err := u.DB.Where("user_id = ? AND slug = ?", userID, documentSlug).Delete(&QueuedDocument{}).Error
+	if err != nil {
+		log.Printf("error removing document from queue: %s\n", err)
+	}
+	return err
+}
+
+// Move places a document of a user's queue at a new position, shifting the rest of documents accordingly.
+// Positions out of range move the document to the start or the end of the queue.
+func (u *QueueRepository) Move(userID int, documentSlug string, position int) error {
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		var slugs []string
+		if err := tx.Model(&QueuedDocument{}).Where("user_id = ?", userID).Order("position, created_at").Pluck("slug", &slugs).Error; err != nil {
+			return err
+		}
+
+		slugs, ok := moveSlug(slugs, documentSlug, position)
+		if !ok {
+			return nil
+		}
+
+		for i, slug := range slugs {
+			if err := tx.Model(&QueuedDocument{}).Where("user_id = ? AND slug = ?", userID, slug).UpdateColumn("position", i).Error; err != nil {
+				log.Printf("error moving document in queue: %s\n", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Slugs returns the slugs of the documents in a user's queue, by priority
+func (u *QueueRepository) Slugs(userID int) ([]string, error) {
+	slugs := []string{}
+	if err := u.DB.Model(&QueuedDocument{}).Where("user_id = ?", userID).Order("position").Pluck("slug", &slugs).Error; err != nil {
+		log.Printf("error getting queued documents: %s\n", err)
+		return nil, err
+	}
+	return slugs, nil
+}
+
+// Queued tells whether a document is in a user's queue
+func (u *QueueRepository) Queued(userID int, documentSlug string) bool {
+	var count int64
+	u.DB.Model(&QueuedDocument{}).Where("user_id = ? AND slug = ?", userID, documentSlug).Count(&count)
+	return count > 0
+}
+
+// Documents returns the documents in a user's queue, by priority. Documents missing from the index are omitted.
+func (u *QueueRepository) Documents(userID int) ([]index.Document, error) {
+	if u.Idx == nil {
+		return nil, errors.New("queue repository: idx required for Documents")
+	}
+
+	slugs, err := u.Slugs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	docBySlug, err := u.Idx.Documents(slugs)
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return nil, err
+	}
+
+	documents := make([]index.Document, 0, len(slugs))
+	for _, slug := range slugs {
+		if doc, ok := docBySlug[slug]; ok {
+			documents = append(documents, doc)
+		}
+	}
+	return documents, nil
+}
+
+func (u *QueueRepository) RemoveDocument(documentSlug string) error {
+	return u.DB.Where("slug = ?", documentSlug).Delete(&QueuedDocument{}).Error
+}
+
+// QueuedByKepubReaders returns the slugs of the documents in the queues of users who download EPUB files
+// converted to KEPUB
+func (u *QueueRepository) QueuedByKepubReaders() ([]string, error) {
+	var slugs []string
+	err := u.DB.Model(&QueuedDocument{}).
+		Distinct("queued_documents.slug").
+		Joins("JOIN users ON users.id = queued_documents.user_id").
+		Where("users.preferred_epub_type = ?", "kepub").
+		Pluck("queued_documents.slug", &slugs).Error
+	if err != nil {
+		log.Printf("error getting documents queued by KEPUB readers: %s\n", err)
+		return nil, err
+	}
+	return slugs, nil
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMoveSlug(t *testing.T) {
+	slugs := []string{"a", "b", "c"}
+
+	for _, tcase := range []struct {
+		slug     string
+		position int
+		expected []string
+		found    bool
+	}{
+		{"c", 0, []string{"c", "a", "b"}, true},
+		{"a", 1, []string{"b", "a", "c"}, true},
+		{"a", 10, []string{"b", "c", "a"}, true},
+		{"b", -1, []string{"b", "a", "c"}, true},
+		{"d", 0, []string{"a", "b", "c"}, false},
+	} {
+		t.Run(fmt.Sprintf("%s to %d", tcase.slug, tcase.position), func(t *testing.T) {
+			got, found := moveSlug(slugs, tcase.slug, tcase.position)
+			if found != tcase.found || fmt.Sprint(got) != fmt.Sprint(tcase.expected) {
+				t.Errorf("Expected %v (%t), got %v (%t)", tcase.expected, tcase.found, got, found)
+			}
+		})
+	}
+
+	if fmt.Sprint(slugs) != fmt.Sprint([]string{"a", "b", "c"}) {
+		t.Errorf("Expected passed slugs to be left untouched, got %v", slugs)
+	}
+}
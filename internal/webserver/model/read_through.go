@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// MaxRating is the highest rating that can be given to a read-through
+const MaxRating = 5
+
+// ReadThrough is one complete reading of a document by a user, from its start to its completion.
+// A document can be read several times, so a reading can have many read-throughs. Only the last one
+// can be in progress, that is, without a completion date.
+type ReadThrough struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    int    `gorm:"index:idx_read_through_document; not null"`
+	Slug      string `gorm:"index:idx_read_through_document; not null"`
+	// StartedOn is nil when unknown, for example when a document read before being added to the library is marked as completed
+	StartedOn   *time.Time
+	CompletedOn *time.Time
+	// Rating goes from 1 to MaxRating, or 0 if the read-through has not been rated
+	Rating int `gorm:"default:0; not null"`
+}
+
+// Validate checks all read-through's fields to ensure they are in the required format
+func (r ReadThrough) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if r.Rating < 0 || r.Rating > MaxRating {
+		errs["rating"] = "Invalid rating"
+	}
+
+	return errs
+}
+
+// Duration returns the time it took to complete the read-through, or 0 if unknown
+func (r ReadThrough) Duration() time.Duration {
+	if r.StartedOn == nil || r.CompletedOn == nil || r.CompletedOn.Before(*r.StartedOn) {
+		return 0
+	}
+	return r.CompletedOn.Sub(*r.StartedOn)
+}
+
+// HistoryEntry is a completed read-through as shown in the reading history timeline
+type HistoryEntry struct {
+	ReadThrough
+	Document index.Document
+	// Number is the position of the read-through among all the read-throughs of the same document by the user
+	Number int
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Values accepted by the reader preferences
+var (
+	// ReaderFontFamilies are the fonts documents can be shown with. "publisher" keeps the fonts chosen by the document.
+	ReaderFontFamilies = []string{"publisher", "serif", "sans-serif", "monospace"}
+	ReaderThemes       = []string{"auto", "light", "dark"}
+	ReaderFlows        = []string{"paginated", "scrolled"}
+)
+
+// Limits of the numeric reader preferences
+const (
+	ReaderFontSizeMin   = 75
+	ReaderFontSizeMax   = 200
+	ReaderLineHeightMin = 1.0
+	ReaderLineHeightMax = 3.0
+	ReaderMarginMax     = 20
+	ReaderColumnsMax    = 2
+)
+
+// ReaderPreferences holds how a user likes documents to be shown in the reader, so they look
+// the same in every device
+type ReaderPreferences struct {
+	ID         uint      `gorm:"primarykey" json:"-"`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+	UserID     uint      `gorm:"uniqueIndex; not null" json:"-"`
+	User       User      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	FontFamily string    `gorm:"not null" json:"font_family"`
+	// FontSize is a percentage of the document's font size
+	FontSize   int     `gorm:"not null" json:"font_size"`
+	LineHeight float64 `gorm:"not null" json:"line_height"`
+	// Margin is the space between columns and around pages, as a percentage of the page width
+	Margin  int    `gorm:"not null" json:"margin"`
+	Justify bool   `gorm:"not null" json:"justify"`
+	Theme   string `gorm:"not null" json:"theme"`
+	Flow    string `gorm:"not null" json:"flow"`
+	// Columns is the maximum number of columns shown side by side when there is enough room
+	Columns int `gorm:"not null" json:"columns"`
+}
+
+// DefaultReaderPreferences returns the preferences used until a user changes them
+func DefaultReaderPreferences() ReaderPreferences {
+	return ReaderPreferences{
+		FontFamily: "serif",
+		FontSize:   100,
+		LineHeight: 1.4,
+		Margin:     7,
+		Justify:    true,
+		Theme:      "auto",
+		Flow:       "paginated",
+		Columns:    2,
+	}
+}
+
+// Validate checks all preferences to ensure they are in the accepted ranges
+func (r ReaderPreferences) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if !slices.Contains(ReaderFontFamilies, r.FontFamily) {
+		errs["font_family"] = "Invalid font"
+	}
+
+	if r.FontSize < ReaderFontSizeMin || r.FontSize > ReaderFontSizeMax {
+		errs["font_size"] = "Invalid font size"
+	}
+
+	if r.LineHeight < ReaderLineHeightMin || r.LineHeight > ReaderLineHeightMax {
+		errs["line_height"] = "Invalid line height"
+	}
+
+	if r.Margin < 0 || r.Margin > ReaderMarginMax {
+		errs["margin"] = "Invalid margin"
+	}
+
+	if !slices.Contains(ReaderThemes, r.Theme) {
+		errs["theme"] = "Invalid theme"
+	}
+
+	if !slices.Contains(ReaderFlows, r.Flow) {
+		errs["flow"] = "Invalid layout"
+	}
+
+	if r.Columns < 1 || r.Columns > ReaderColumnsMax {
+		errs["columns"] = "Invalid number of columns"
+	}
+
+	return errs
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"errors"
+	"log"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReaderPreferencesRepository struct {
+	DB *gorm.DB
+}
+
+// Get returns the reader preferences of a user, or the default ones if they have not been changed yet,
+// in which case UpdatedAt is zero
+func (r *ReaderPreferencesRepository) Get(userID uint) (ReaderPreferences, error) {
+	var preferences ReaderPreferences
+	err := r.DB.Where("user_id = ?", userID).Take(&preferences).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preferences = DefaultReaderPreferences()
+		preferences.UserID = userID
+		return preferences, nil
+	}
+	if err != nil {
+		log.Printf("error getting reader preferences: %s\n", err)
+		return ReaderPreferences{}, err
+	}
+	return preferences, nil
+}
+
+// Save stores the reader preferences of a user, replacing the previous ones
+func (r *ReaderPreferencesRepository) Save(preferences *ReaderPreferences) error {
+	result := r.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"font_family", "font_size", "line_height", "margin", "justify", "theme", "flow", "columns", "updated_at",
+		}),
+	}).Create(preferences)
+	if result.Error != nil {
+		log.Printf("error saving reader preferences: %s\n", result.Error)
+	}
+	return result.Error
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -95,6 +96,32 @@ func (u *ReadingRepository) Update(userID int, documentSlug, position string, pe
 	}).Create(&row).Error
 }
 
+// UpdateAt stores a reading position recorded at the given time, unless a later one is already stored.
+// Positions saved while offline reach the server late, and must not overwrite those saved meanwhile from other devices.
+func (u *ReadingRepository) UpdateAt(userID int, documentSlug, position string, percentage *int, recordedAt time.Time) error {
+	row := Reading{
+		UserID:    userID,
+		Slug:      documentSlug,
+		Position:  position,
+		UpdatedAt: recordedAt,
+	}
+	columns := []string{"position", "updated_at"}
+	if position == "" {
+		row.Percentage = 0
+		columns = append(columns, "percentage")
+	} else if percentage != nil {
+		row.Percentage = ClampReadingPercentage(*percentage)
+		columns = append(columns, "percentage")
+	}
+	return u.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "readings.updated_at IS NULL OR julianday(readings.updated_at) < julianday(excluded.updated_at)"},
+		}},
+	}).Create(&row).Error
+}
+
 // Touch creates a reading record if it doesn't exist, but doesn't update it if it does.
 // This is used to track that a document has been opened without overwriting existing positions.
 // Sets updated_at to NULL initially - it will only be set when the reading position is actually updated.
@@ -113,14 +140,208 @@ func (u *ReadingRepository) Touch(userID int, documentSlug string) error {
 	return u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&progress).Error
 }
 
+// MostRead returns the slugs of the documents read by more users, including those who already completed them
+func (u *ReadingRepository) MostRead(limit int) ([]DocumentCount, error) {
+	var counts []DocumentCount
+
+	res := u.DB.Model(&Reading{}).Select("slug, COUNT(*) AS count").Group("slug").Order("count DESC, slug ASC").Limit(limit).Scan(&counts)
+	if res.Error != nil {
+		log.Printf("error counting readings: %s\n", res.Error)
+		return nil, res.Error
+	}
+	return counts, nil
+}
+
 func (u *ReadingRepository) RemoveDocument(documentSlug string) error {
+	if err := u.DB.Where("slug = ?", documentSlug).Delete(&ReadingSession{}).Error; err != nil {
+		return err
+	}
+	if err := u.DB.Where("slug = ?", documentSlug).Delete(&ReadThrough{}).Error; err != nil {
+		return err
+	}
 	return u.DB.Where("slug = ?", documentSlug).Delete(&Reading{}).Error
 }
 
+func (u *ReadingRepository) AddSession(session *ReadingSession) error {
+	result := u.DB.Create(session)
+	if result.Error != nil {
+		log.Printf("error saving reading session: %s\n", result.Error)
+	}
+	return result.Error
+}
+
+// TimeSpent returns the total time the user spent reading a document in the reader
+func (u *ReadingRepository) TimeSpent(userID int, documentSlug string) (time.Duration, error) {
+	var sessions []ReadingSession
+	if err := u.DB.Where("user_id = ? AND slug = ?", userID, documentSlug).Find(&sessions).Error; err != nil {
+		log.Printf("error getting reading sessions: %s\n", err)
+		return 0, err
+	}
+
+	var timeSpent time.Duration
+	for _, session := range sessions {
+		timeSpent += session.Duration()
+	}
+	return timeSpent, nil
+}
+
+// MeasuredWordsPerMinute returns the reading speed of the user measured from their reading sessions,
+// or 0 if there is not enough data yet. Requires Idx to be set.
+func (u *ReadingRepository) MeasuredWordsPerMinute(userID int) (float64, error) {
+	if u.Idx == nil {
+		return 0, errors.New("reading repository: idx required for MeasuredWordsPerMinute")
+	}
+	var sessions []ReadingSession
+	if err := u.DB.Where("user_id = ? AND end_percentage > start_percentage", userID).Find(&sessions).Error; err != nil {
+		log.Printf("error getting reading sessions: %s\n", err)
+		return 0, err
+	}
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	slugs := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		slugs = append(slugs, s.Slug)
+	}
+	slices.Sort(slugs)
+	docBySlug, err := u.Idx.Documents(slices.Compact(slugs))
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return 0, err
+	}
+	return MeasuredWordsPerMinute(sessions, docBySlug), nil
+}
+
+// UpdateCompletionDate sets the completion date of the current read-through of a document, creating it if this is
+// the first time the document is completed. Passing a nil date undoes the completion, so the read-through is in progress again.
 func (u *ReadingRepository) UpdateCompletionDate(userID int, documentSlug string, completedAt *time.Time) error {
-	return u.DB.Model(&Reading{}).
-		Where("user_id = ? AND slug = ?", userID, documentSlug).
-		UpdateColumn("completed_on", completedAt).Error
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		var reading Reading
+		result := tx.Where("user_id = ? AND slug = ?", userID, documentSlug).Limit(1).Find(&reading)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var current ReadThrough
+		result = tx.Where("user_id = ? AND slug = ?", userID, documentSlug).Order("id DESC").Limit(1).Find(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		switch {
+		case result.RowsAffected == 0 && completedAt != nil:
+			current = ReadThrough{UserID: userID, Slug: documentSlug, CompletedOn: completedAt}
+			if !reading.CreatedAt.After(*completedAt) {
+				current.StartedOn = &reading.CreatedAt
+			}
+			if err := tx.Create(&current).Error; err != nil {
+				return err
+			}
+		case result.RowsAffected > 0:
+			updates := map[string]any{"completed_on": completedAt}
+			if completedAt != nil && current.StartedOn != nil && current.StartedOn.After(*completedAt) {
+				updates["started_on"] = nil
+			}
+			if err := tx.Model(&current).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&Reading{}).
+			Where("user_id = ? AND slug = ?", userID, documentSlug).
+			UpdateColumn("completed_on", completedAt).Error
+	})
+}
+
+// Reread starts a new read-through of a completed document, keeping the previous ones in the reading history
+func (u *ReadingRepository) Reread(userID int, documentSlug string) error {
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Reading{}).
+			Where("user_id = ? AND slug = ? AND completed_on IS NOT NULL", userID, documentSlug).
+			UpdateColumns(map[string]any{"completed_on": nil, "position": "", "percentage": 0})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		now := time.Now()
+		return tx.Create(&ReadThrough{UserID: userID, Slug: documentSlug, StartedOn: &now}).Error
+	})
+}
+
+// ReadThroughs returns the number of completed read-throughs of a document by the user
+func (u *ReadingRepository) ReadThroughs(userID int, documentSlug string) (int64, error) {
+	var count int64
+	err := u.DB.Model(&ReadThrough{}).Where("user_id = ? AND slug = ? AND completed_on IS NOT NULL", userID, documentSlug).Count(&count).Error
+	return count, err
+}
+
+// History returns the completed read-throughs of the user, latest first, along with their documents.
+// Read-throughs whose document is no longer in the library are left out, both from the results and from the
+// total, so every page is full. Requires Idx to be set.
+func (u *ReadingRepository) History(userID int, page int, resultsPerPage int) (result.Paginated[[]HistoryEntry], error) {
+	if u.Idx == nil {
+		return result.Paginated[[]HistoryEntry]{}, errors.New("reading repository: idx required for History")
+	}
+
+	var slugs []string
+	if err := u.DB.Model(&ReadThrough{}).Where("user_id = ? AND completed_on IS NOT NULL", userID).Distinct().Pluck("slug", &slugs).Error; err != nil {
+		log.Printf("error listing read-throughs: %s\n", err)
+		return result.Paginated[[]HistoryEntry]{}, err
+	}
+	docBySlug, err := u.Idx.Documents(slugs)
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return result.Paginated[[]HistoryEntry]{}, err
+	}
+	missing := []string{}
+	for _, slug := range slugs {
+		if _, ok := docBySlug[slug]; !ok {
+			missing = append(missing, slug)
+		}
+	}
+
+	// Read-throughs are numbered among all the ones of the same document before leaving out the missing documents
+	numbered := u.DB.Model(&ReadThrough{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY id) AS number").
+		Where("user_id = ? AND completed_on IS NOT NULL", userID)
+	inLibrary := func(db *gorm.DB) *gorm.DB {
+		db = db.Table("(?) AS numbered", numbered)
+		if len(missing) > 0 {
+			db = db.Where("slug NOT IN ?", missing)
+		}
+		return db
+	}
+
+	var rows []struct {
+		ReadThrough
+		Number int
+	}
+	if err := u.DB.Scopes(inLibrary, Paginate(page, resultsPerPage)).Order("completed_on DESC, id DESC").Find(&rows).Error; err != nil {
+		log.Printf("error listing read-throughs: %s\n", err)
+		return result.Paginated[[]HistoryEntry]{}, err
+	}
+	var total int64
+	if err := u.DB.Scopes(inLibrary).Count(&total).Error; err != nil {
+		log.Printf("error counting read-throughs: %s\n", err)
+		return result.Paginated[[]HistoryEntry]{}, err
+	}
+
+	entries := make([]HistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, HistoryEntry{ReadThrough: row.ReadThrough, Document: docBySlug[row.Slug], Number: row.Number})
+	}
+
+	return result.NewPaginated(resultsPerPage, page, int(total), entries), nil
+}
+
+// Rate sets the rating of a read-through, as long as it belongs to the user
+func (u *ReadingRepository) Rate(userID int, readThroughID uint, rating int) error {
+	result := u.DB.Model(&ReadThrough{}).Where("user_id = ? AND id = ?", userID, readThroughID).UpdateColumn("rating", rating)
+	if result.Error != nil {
+		log.Printf("error rating read-through: %s\n", result.Error)
+	}
+	return result.Error
 }
 
 func (u *ReadingRepository) CompletedOn(userID int, documentSlug string) (*time.Time, error) {
@@ -336,6 +557,33 @@ func (u *ReadingRepository) CompletedStatsByYear(userID int, wordsPerMinute floa
 	return stats, nil
 }
 
+// Stats returns the reading statistics of a user for the given year, or for all time if year is 0.
+// wordsPerMinute is used to compute the estimated reading time. Requires Idx to be set.
+func (u *ReadingRepository) Stats(userID, year int, wordsPerMinute float64) (ReadingStats, error) {
+	if u.Idx == nil {
+		return ReadingStats{}, errors.New("reading repository: idx required for Stats")
+	}
+	// All completions are needed even when filtering by year, to calculate the current streak
+	var readThroughs []ReadThrough
+	if err := u.DB.Where("user_id = ? AND completed_on IS NOT NULL", userID).Order("completed_on ASC").Find(&readThroughs).Error; err != nil {
+		log.Printf("error getting completed read-throughs: %s\n", err)
+		return ReadingStats{}, err
+	}
+
+	slugs := make([]string, 0, len(readThroughs))
+	for _, r := range readThroughs {
+		slugs = append(slugs, r.Slug)
+	}
+	slices.Sort(slugs)
+	docBySlug, err := u.Idx.Documents(slices.Compact(slugs))
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return ReadingStats{}, err
+	}
+
+	return NewReadingStats(readThroughs, docBySlug, year, wordsPerMinute, time.Now()), nil
+}
+
 func wordsToReadingTime(words, wordsPerMinute float64) string {
 	if words <= 0 || wordsPerMinute <= 0 {
 		return ""
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/glebarez/sqlite"
 	"github.com/svera/coreander/v4/internal/index"
@@ -14,7 +15,7 @@ func newTestReadingRepo(t *testing.T) *ReadingRepository {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := db.AutoMigrate(&Reading{}); err != nil {
+	if err := db.AutoMigrate(&Reading{}, &ReadThrough{}); err != nil {
 		t.Fatal(err)
 	}
 	return &ReadingRepository{DB: db}
@@ -161,3 +162,150 @@ func TestLatestRequiresIdx(t *testing.T) {
 		t.Fatal("expected error when Idx is nil")
 	}
 }
+
+func TestReadingRepositoryKeepsReadThroughs(t *testing.T) {
+	repo := newTestReadingRepo(t)
+	if err := repo.Touch(1, "slug-reread"); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	mustUpdateReading(t, repo, 1, "slug-reread", "cfi-end", intPtr(100))
+
+	completedReadThroughs := func(t *testing.T, want int64) {
+		t.Helper()
+		got, err := repo.ReadThroughs(1, "slug-reread")
+		if err != nil {
+			t.Fatalf("ReadThroughs: %v", err)
+		}
+		if got != want {
+			t.Fatalf("ReadThroughs = %d, want %d", got, want)
+		}
+	}
+
+	firstCompletion := time.Now().Add(-48 * time.Hour)
+	if err := repo.UpdateCompletionDate(1, "slug-reread", &firstCompletion); err != nil {
+		t.Fatalf("UpdateCompletionDate: %v", err)
+	}
+	completedReadThroughs(t, 1)
+
+	if err := repo.Reread(1, "slug-reread"); err != nil {
+		t.Fatalf("Reread: %v", err)
+	}
+	got := firstReading(t, repo.DB, 1, "slug-reread")
+	if got.CompletedOn != nil || got.Position != "" || got.Percentage != 0 {
+		t.Fatalf("Reading = %+v, want it to be in progress from the start", got)
+	}
+	completedReadThroughs(t, 1)
+
+	secondCompletion := time.Now()
+	if err := repo.UpdateCompletionDate(1, "slug-reread", &secondCompletion); err != nil {
+		t.Fatalf("UpdateCompletionDate: %v", err)
+	}
+	completedReadThroughs(t, 2)
+
+	// Undoing a completion only affects the last read-through
+	if err := repo.UpdateCompletionDate(1, "slug-reread", nil); err != nil {
+		t.Fatalf("UpdateCompletionDate: %v", err)
+	}
+	completedReadThroughs(t, 1)
+
+	var readThroughs []ReadThrough
+	repo.DB.Where("user_id = ? AND slug = ?", 1, "slug-reread").Order("id ASC").Find(&readThroughs)
+	if len(readThroughs) != 2 || readThroughs[0].CompletedOn == nil || readThroughs[1].CompletedOn != nil || readThroughs[1].StartedOn == nil {
+		t.Fatalf("ReadThroughs = %+v, want a completed one followed by one in progress", readThroughs)
+	}
+}
+
+func TestReadingRepositoryCompletionBeforeStartLeavesStartUnknown(t *testing.T) {
+	repo := newTestReadingRepo(t)
+	if err := repo.Touch(1, "slug-read-long-ago"); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+
+	completedOn := time.Now().AddDate(-5, 0, 0)
+	if err := repo.UpdateCompletionDate(1, "slug-read-long-ago", &completedOn); err != nil {
+		t.Fatalf("UpdateCompletionDate: %v", err)
+	}
+
+	var readThrough ReadThrough
+	if err := repo.DB.Where("user_id = ? AND slug = ?", 1, "slug-read-long-ago").First(&readThrough).Error; err != nil {
+		t.Fatalf("First: %v", err)
+	}
+	if readThrough.StartedOn != nil {
+		t.Fatalf("StartedOn = %v, want nil", readThrough.StartedOn)
+	}
+}
+
+func TestReadingRepositoryUpdateAtKeepsLaterPositions(t *testing.T) {
+	repo := newTestReadingRepo(t)
+	mustUpdateReading(t, repo, 3, "slug-offline", "cfi-online", intPtr(40))
+
+	if err := repo.UpdateAt(3, "slug-offline", "cfi-offline", intPtr(20), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("UpdateAt: %v", err)
+	}
+	got := firstReading(t, repo.DB, 3, "slug-offline")
+	if got.Position != "cfi-online" || got.Percentage != 40 {
+		t.Fatalf("Position = %q (%d%%), want cfi-online (40%%) kept as it is later", got.Position, got.Percentage)
+	}
+
+	recordedAt := time.Now().Add(time.Minute).UTC()
+	if err := repo.UpdateAt(3, "slug-offline", "cfi-later", intPtr(70), recordedAt); err != nil {
+		t.Fatalf("UpdateAt: %v", err)
+	}
+	got = firstReading(t, repo.DB, 3, "slug-offline")
+	if got.Position != "cfi-later" || got.Percentage != 70 {
+		t.Fatalf("Position = %q (%d%%), want cfi-later (70%%)", got.Position, got.Percentage)
+	}
+	if !got.UpdatedAt.Equal(recordedAt) {
+		t.Fatalf("UpdatedAt = %v, want %v", got.UpdatedAt, recordedAt)
+	}
+}
+
+func TestReadingRepositoryUpdateAtCreatesPosition(t *testing.T) {
+	repo := newTestReadingRepo(t)
+	if err := repo.UpdateAt(3, "slug-offline-new", "cfi-offline", intPtr(20), time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("UpdateAt: %v", err)
+	}
+	got := firstReading(t, repo.DB, 3, "slug-offline-new")
+	if got.Position != "cfi-offline" {
+		t.Fatalf("Position = %q, want cfi-offline", got.Position)
+	}
+}
+
+func TestHistorySkipsMissingDocumentsFromResultsAndTotal(t *testing.T) {
+	const uid = 703
+	repo := newTestReadingRepo(t)
+	repo.Idx = &latestInProgressIdxStub{docs: map[string]index.Document{
+		"a": {Slug: "a"},
+		"b": {Slug: "b"},
+	}}
+
+	completedOn := time.Now().Add(-72 * time.Hour)
+	for _, slug := range []string{"a", "ghost", "b", "a", "ghost"} {
+		completedOn = completedOn.Add(time.Hour)
+		on := completedOn
+		if err := repo.DB.Create(&ReadThrough{UserID: uid, Slug: slug, CompletedOn: &on}).Error; err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	first, err := repo.History(uid, 1, 2)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if first.TotalHits() != 3 {
+		t.Fatalf("total %d, want 3", first.TotalHits())
+	}
+	hits := first.Hits()
+	if len(hits) != 2 || hits[0].Slug != "a" || hits[0].Number != 2 || hits[1].Slug != "b" || hits[1].Number != 1 {
+		t.Fatalf("first page %+v, want the second read-through of a and the first of b", hits)
+	}
+
+	second, err := repo.History(uid, 2, 2)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	hits = second.Hits()
+	if len(hits) != 1 || hits[0].Slug != "a" || hits[0].Number != 1 || hits[0].Document.Slug != "a" {
+		t.Fatalf("second page %+v, want the first read-through of a", hits)
+	}
+}
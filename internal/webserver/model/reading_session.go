@@ -0,0 +1,68 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+const (
+	// ReadingSessionMaxDuration is the longest reading session accepted, to discard sessions where the reader was left open
+	ReadingSessionMaxDuration = 12 * time.Hour
+	// MinMeasuredReadingTime is the reading time needed before a personal reading speed can be measured
+	MinMeasuredReadingTime = 30 * time.Minute
+)
+
+// ReadingSession is a continuous period of time a user spent reading a document in the reader
+type ReadingSession struct {
+	ID              uint `gorm:"primarykey"`
+	CreatedAt       time.Time
+	UserID          int       `gorm:"index:idx_reading_session_document; not null"`
+	Slug            string    `gorm:"index:idx_reading_session_document; not null"`
+	StartedAt       time.Time `gorm:"not null"`
+	EndedAt         time.Time `gorm:"not null"`
+	StartPercentage int       `gorm:"default:0"`
+	EndPercentage   int       `gorm:"default:0"`
+}
+
+// Validate checks that the session has a valid duration
+func (s ReadingSession) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if !s.EndedAt.After(s.StartedAt) {
+		errs["ended_at"] = "Session end must be after its start"
+	} else if s.Duration() > ReadingSessionMaxDuration {
+		errs["ended_at"] = "Session is too long"
+	}
+
+	return errs
+}
+
+// Duration returns the time spent reading in the session
+func (s ReadingSession) Duration() time.Duration {
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+// MeasuredWordsPerMinute calculates the reading speed from the progress made in reading sessions.
+// Sessions without forward progress or whose document is no longer in the library are ignored.
+// Returns 0 if the remaining sessions add up to less than MinMeasuredReadingTime.
+func MeasuredWordsPerMinute(sessions []ReadingSession, documents map[string]index.Document) float64 {
+	var words float64
+	var readingTime time.Duration
+
+	for _, session := range sessions {
+		doc, ok := documents[session.Slug]
+		if !ok || doc.Words == 0 || session.EndPercentage <= session.StartPercentage {
+			continue
+		}
+		words += doc.Words * float64(session.EndPercentage-session.StartPercentage) / 100
+		readingTime += session.Duration()
+	}
+
+	if readingTime < MinMeasuredReadingTime {
+		return 0
+	}
+	// Keep the value within the range accepted for users' reading speed
+	return min(999, max(1, math.Round(words/readingTime.Minutes())))
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/metadata"
+)
+
+func readingSession(slug string, startedAt time.Time, duration time.Duration, startPercentage, endPercentage int) ReadingSession {
+	return ReadingSession{
+		Slug:            slug,
+		StartedAt:       startedAt,
+		EndedAt:         startedAt.Add(duration),
+		StartPercentage: startPercentage,
+		EndPercentage:   endPercentage,
+	}
+}
+
+func TestReadingSessionValidate(t *testing.T) {
+	start := date(2025, time.March, 16)
+
+	if errs := readingSession("a", start, time.Hour, 0, 10).Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if errs := readingSession("a", start, 0, 0, 10).Validate(); errs["ended_at"] == "" {
+		t.Error("Expected sessions without duration to be invalid")
+	}
+	if errs := readingSession("a", start, ReadingSessionMaxDuration+time.Minute, 0, 10).Validate(); errs["ended_at"] == "" {
+		t.Error("Expected too long sessions to be invalid")
+	}
+}
+
+func TestMeasuredWordsPerMinute(t *testing.T) {
+	documents := map[string]index.Document{
+		"a": {Slug: "a", Metadata: metadata.Metadata{Words: 100000}},
+		"b": {Slug: "b", Metadata: metadata.Metadata{Words: 50000}},
+	}
+	start := date(2025, time.March, 16)
+
+	t.Run("Speed is calculated from the progress made in all sessions", func(t *testing.T) {
+		sessions := []ReadingSession{
+			// 10000 words in 40 minutes
+			readingSession("a", start, 40*time.Minute, 10, 20),
+			// 5000 words in 20 minutes
+			readingSession("b", start.Add(time.Hour), 20*time.Minute, 50, 60),
+			// Ignored, as there was no forward progress or the document is not in the library
+			readingSession("a", start.Add(2*time.Hour), 30*time.Minute, 20, 15),
+			readingSession("c", start.Add(3*time.Hour), 30*time.Minute, 0, 50),
+		}
+
+		if got := MeasuredWordsPerMinute(sessions, documents); got != 250 {
+			t.Errorf("Expected 250 words per minute, got %.0f", got)
+		}
+	})
+
+	t.Run("Speed is not measured without enough reading time", func(t *testing.T) {
+		sessions := []ReadingSession{readingSession("a", start, MinMeasuredReadingTime-time.Minute, 0, 10)}
+
+		if got := MeasuredWordsPerMinute(sessions, documents); got != 0 {
+			t.Errorf("Expected no measured speed, got %.0f", got)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/metadata"
+)
+
+// StatsRankingSize is the number of entries shown in each of the reading statistics rankings
+const StatsRankingSize = 5
+
+// PeriodStats holds the documents completed in a month, or in a year when showing all time statistics.
+type PeriodStats struct {
+	Start     time.Time
+	Documents int
+	Words     float64
+	// Percentage of documents relative to the period with most completions, used to draw the chart
+	Percentage int
+}
+
+// ReadingStats holds the reading statistics of a user for a year, or for all time when Year is 0.
+type ReadingStats struct {
+	Year        int
+	Documents   int
+	Words       float64
+	Pages       float64
+	ReadingTime string
+	Periods     []PeriodStats
+	// Streaks are measured in consecutive months with at least one completed document
+	LongestStreak int
+	CurrentStreak int
+	// AverageCompletionTime is the average time between a document being opened for the first time and its completion
+	AverageCompletionTime string
+	Authors               []index.Facet
+	Subjects              []index.Facet
+	Languages             []index.Facet
+	Completed             []index.Document
+}
+
+// NewReadingStats computes the reading statistics for the given year (0 for all time) from the completed read-throughs of a user,
+// which must be sorted by completion date. Read-throughs whose document is no longer in the library are ignored.
+func NewReadingStats(readThroughs []ReadThrough, documents map[string]index.Document, year int, wordsPerMinute float64, now time.Time) ReadingStats {
+	stats := ReadingStats{Year: year}
+	allMonths := map[int]bool{}
+	months := map[int]bool{}
+	authors, subjects, languages := map[string]int{}, map[string]int{}, map[string]int{}
+	var completionTime time.Duration
+	var timedCompletions int
+
+	for _, readThrough := range readThroughs {
+		if readThrough.CompletedOn == nil {
+			continue
+		}
+		doc, ok := documents[readThrough.Slug]
+		if !ok || doc.Slug == "" {
+			continue
+		}
+		completedOn := readThrough.CompletedOn.Local()
+		allMonths[monthNumber(completedOn)] = true
+		if year != 0 && completedOn.Year() != year {
+			continue
+		}
+
+		months[monthNumber(completedOn)] = true
+		stats.Documents++
+		stats.Words += doc.Words
+		stats.Pages += doc.Pages
+		stats.Completed = append(stats.Completed, doc)
+		stats.addToPeriod(completedOn, doc.Words)
+		for _, author := range doc.Authors {
+			authors[author]++
+		}
+		for _, subject := range doc.Subjects {
+			subjects[subject]++
+		}
+		if doc.Language != "" {
+			languages[doc.Language]++
+		}
+		if readThrough.StartedOn != nil && !readThrough.CompletedOn.Before(*readThrough.StartedOn) {
+			completionTime += readThrough.Duration()
+			timedCompletions++
+		}
+	}
+
+	stats.fillPeriods()
+	stats.ReadingTime = wordsToReadingTime(stats.Words, wordsPerMinute)
+	stats.LongestStreak = longestStreak(months)
+	stats.CurrentStreak = currentStreak(allMonths, now)
+	if timedCompletions > 0 {
+		stats.AverageCompletionTime = metadata.FmtDuration(completionTime / time.Duration(timedCompletions))
+	}
+	stats.Authors = ranking(authors)
+	stats.Subjects = ranking(subjects)
+	stats.Languages = ranking(languages)
+	return stats
+}
+
+// addToPeriod accounts a completed document in its month, or in its year when showing all time statistics
+func (s *ReadingStats) addToPeriod(completedOn time.Time, words float64) {
+	start := time.Date(completedOn.Year(), completedOn.Month(), 1, 0, 0, 0, 0, time.Local)
+	if s.Year == 0 {
+		start = time.Date(completedOn.Year(), time.January, 1, 0, 0, 0, 0, time.Local)
+	}
+	for i := range s.Periods {
+		if s.Periods[i].Start.Equal(start) {
+			s.Periods[i].Documents++
+			s.Periods[i].Words += words
+			return
+		}
+	}
+	s.Periods = append(s.Periods, PeriodStats{Start: start, Documents: 1, Words: words})
+}
+
+// fillPeriods adds the periods without completions, so the chart shows all months of the year,
+// or all years since the first completion, and calculates their percentages
+func (s *ReadingStats) fillPeriods() {
+	var periods []PeriodStats
+	switch {
+	case s.Year != 0:
+		for month := time.January; month <= time.December; month++ {
+			periods = append(periods, PeriodStats{Start: time.Date(s.Year, month, 1, 0, 0, 0, 0, time.Local)})
+		}
+	case len(s.Periods) > 0:
+		for year := s.Periods[0].Start.Year(); year <= s.Periods[len(s.Periods)-1].Start.Year(); year++ {
+			periods = append(periods, PeriodStats{Start: time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)})
+		}
+	}
+
+	maxDocuments := 0
+	for i := range periods {
+		for _, period := range s.Periods {
+			if period.Start.Equal(periods[i].Start) {
+				periods[i] = period
+			}
+		}
+		maxDocuments = max(maxDocuments, periods[i].Documents)
+	}
+	for i := range periods {
+		if maxDocuments > 0 {
+			periods[i].Percentage = periods[i].Documents * 100 / maxDocuments
+		}
+	}
+	s.Periods = periods
+}
+
+func monthNumber(t time.Time) int {
+	return t.Year()*12 + int(t.Month()) - 1
+}
+
+func longestStreak(months map[int]bool) int {
+	longest := 0
+	for month := range months {
+		// Only count from the first month of each streak
+		if months[month-1] {
+			continue
+		}
+		length := 1
+		for months[month+length] {
+			length++
+		}
+		longest = max(longest, length)
+	}
+	return longest
+}
+
+// currentStreak counts the consecutive months with completions up to now. The current month does not
+// break the streak if nothing has been completed yet, as it is still ongoing.
+func currentStreak(months map[int]bool, now time.Time) int {
+	month := monthNumber(now.Local())
+	if !months[month] {
+		month--
+	}
+	streak := 0
+	for months[month-streak] {
+		streak++
+	}
+	return streak
+}
+
+// ranking returns the terms with the highest counts, sorted by count and then alphabetically
+func ranking(counts map[string]int) []index.Facet {
+	facets := make([]index.Facet, 0, len(counts))
+	for term, count := range counts {
+		facets = append(facets, index.Facet{Term: term, Count: count})
+	}
+	slices.SortFunc(facets, func(a, b index.Facet) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Term, b.Term))
+	})
+	if len(facets) > StatsRankingSize {
+		facets = facets[:StatsRankingSize]
+	}
+	return facets
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"github.com/svera/coreander/v4/internal/metadata"
+)
+
+func completedReadThrough(slug string, startedOn, completedOn time.Time) ReadThrough {
+	return ReadThrough{Slug: slug, StartedOn: &startedOn, CompletedOn: &completedOn}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 12, 0, 0, 0, time.Local)
+}
+
+func TestNewReadingStats(t *testing.T) {
+	documents := map[string]index.Document{
+		"a": {Slug: "a", Metadata: metadata.Metadata{Authors: []string{"Author A"}, Subjects: []string{"Fiction"}, Language: "en", Words: 1000}},
+		"b": {Slug: "b", Metadata: metadata.Metadata{Authors: []string{"Author B"}, Subjects: []string{"Fiction", "History"}, Language: "es", Words: 2000}},
+		"c": {Slug: "c", Metadata: metadata.Metadata{Authors: []string{"Author A"}, Language: "en", Pages: 300}},
+		"d": {Slug: "d", Metadata: metadata.Metadata{Authors: []string{"Author C"}, Language: "en", Words: 500}},
+	}
+	readThroughs := []ReadThrough{
+		completedReadThrough("d", date(2024, time.December, 1), date(2024, time.December, 20)),
+		completedReadThrough("a", date(2025, time.January, 1), date(2025, time.January, 3)),
+		completedReadThrough("b", date(2025, time.January, 10), date(2025, time.February, 10)),
+		// Completion dates set by hand can be earlier than the start of the read-through
+		completedReadThrough("c", date(2025, time.May, 10), date(2025, time.April, 2)),
+		completedReadThrough("removed", date(2025, time.June, 1), date(2025, time.June, 2)),
+	}
+	now := date(2025, time.May, 1)
+
+	t.Run("Statistics for a year", func(t *testing.T) {
+		stats := NewReadingStats(readThroughs, documents, 2025, 250, now)
+
+		if stats.Documents != 3 || stats.Words != 3000 || stats.Pages != 300 {
+			t.Errorf("Expected 3 documents, 3000 words and 300 pages, got %d, %.0f and %.0f", stats.Documents, stats.Words, stats.Pages)
+		}
+		if stats.ReadingTime != "0h 12m" {
+			t.Errorf("Expected reading time to be '0h 12m', got '%s'", stats.ReadingTime)
+		}
+		if len(stats.Periods) != 12 || stats.Periods[0].Documents != 1 || stats.Periods[0].Percentage != 100 || stats.Periods[2].Documents != 0 {
+			t.Errorf("Unexpected monthly stats %+v", stats.Periods)
+		}
+		if stats.LongestStreak != 2 {
+			t.Errorf("Expected longest streak to be 2 months, got %d", stats.LongestStreak)
+		}
+		if stats.CurrentStreak != 1 {
+			t.Errorf("Expected current streak to be 1 month, got %d", stats.CurrentStreak)
+		}
+		if stats.AverageCompletionTime != "16d 12h 0m" {
+			t.Errorf("Expected average completion time to be '16d 12h 0m', got '%s'", stats.AverageCompletionTime)
+		}
+		if len(stats.Authors) != 2 || stats.Authors[0] != (index.Facet{Term: "Author A", Count: 2}) {
+			t.Errorf("Unexpected top authors %+v", stats.Authors)
+		}
+		if len(stats.Subjects) != 2 || stats.Subjects[0] != (index.Facet{Term: "Fiction", Count: 2}) {
+			t.Errorf("Unexpected top subjects %+v", stats.Subjects)
+		}
+		if len(stats.Languages) != 2 || stats.Languages[0] != (index.Facet{Term: "en", Count: 2}) {
+			t.Errorf("Unexpected top languages %+v", stats.Languages)
+		}
+	})
+
+	t.Run("Statistics for all time", func(t *testing.T) {
+		stats := NewReadingStats(readThroughs, documents, 0, 250, now)
+
+		if stats.Documents != 4 {
+			t.Errorf("Expected 4 documents, got %d", stats.Documents)
+		}
+		if len(stats.Periods) != 2 || stats.Periods[0].Documents != 1 || stats.Periods[1].Documents != 3 || stats.Periods[0].Percentage != 33 {
+			t.Errorf("Unexpected yearly stats %+v", stats.Periods)
+		}
+		if stats.LongestStreak != 3 {
+			t.Errorf("Expected longest streak to be 3 months, got %d", stats.LongestStreak)
+		}
+	})
+
+	t.Run("Statistics without completions", func(t *testing.T) {
+		stats := NewReadingStats(nil, documents, 0, 250, now)
+
+		if stats.Documents != 0 || len(stats.Periods) != 0 || stats.LongestStreak != 0 || stats.AverageCompletionTime != "" {
+			t.Errorf("Expected empty stats, got %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/svera/coreander/v4/internal/index"
+)
+
+// ReviewTextMaxLength is the maximum number of characters of a review's text
+const ReviewTextMaxLength = 2000
+
+// Review is the rating and optional text a user writes about a document. A user can only have one review per document.
+type Review struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    int    `gorm:"uniqueIndex:idx_review_document; not null"`
+	Slug      string `gorm:"uniqueIndex:idx_review_document; index; not null"`
+	// Rating goes from 1 to MaxRating
+	Rating int    `gorm:"not null"`
+	Text   string `gorm:"type:text"`
+	User   User
+}
+
+// Validate checks all review's fields to ensure they are in the required format
+func (r Review) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if r.Rating < 1 || r.Rating > MaxRating {
+		errs["rating"] = "Invalid rating"
+	}
+
+	if utf8.RuneCountInString(r.Text) > ReviewTextMaxLength {
+		errs["text"] = "Review cannot be longer than 2000 characters"
+	}
+
+	return errs
+}
+
+// DocumentRating is the aggregated rating of a document from the reviews of all users
+type DocumentRating struct {
+	Slug    string
+	Average float64
+	Reviews int
+}
+
+// ReviewEntry is a review as shown in the list of reviews written by a user
+type ReviewEntry struct {
+	Review
+	Document index.Document
+}
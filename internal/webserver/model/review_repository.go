@@ -0,0 +1,173 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/svera/coreander/v4/internal/result"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewRepository struct {
+	DB  *gorm.DB
+	Idx idxReader
+}
+
+// Get returns the review a user wrote about a document, or an empty review if there is none
+func (u *ReviewRepository) Get(userID int, documentSlug string) (Review, error) {
+	var review Review
+	err := u.DB.Where("user_id = ? AND slug = ?", userID, documentSlug).First(&review).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("error getting review: %s\n", err)
+		return Review{}, err
+	}
+	return review, nil
+}
+
+// Save creates the user's review of a document, or replaces it if it already exists
+func (u *ReviewRepository) Save(review *Review) error {
+	err := u.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "text", "updated_at"}),
+	}).Create(review).Error
+	if err != nil {
+		log.Printf("error saving review: %s\n", err)
+	}
+	return err
+}
+
+func (u *ReviewRepository) Delete(userID int, documentSlug string) error {
+	err := u.DB.Where("user_id = ? AND slug = ?", userID, documentSlug).Delete(&Review{}).Error
+	if err != nil {
+		log.Printf("error deleting review: %s\n", err)
+	}
+	return err
+}
+
+func (u *ReviewRepository) RemoveDocument(documentSlug string) error {
+	return u.DB.Where("slug = ?", documentSlug).Delete(&Review{}).Error
+}
+
+// DocumentReviews returns the reviews of a document that the viewer is allowed to see, newest first.
+// The viewer's own review is not included. Reviews from users with a private profile are only
+// returned if includePrivate is true.
+func (u *ReviewRepository) DocumentReviews(documentSlug string, viewerID int, includePrivate bool) ([]Review, error) {
+	reviews := []Review{}
+	q := u.DB.Joins("User").Where("reviews.slug = ? AND reviews.user_id <> ?", documentSlug, viewerID)
+	if !includePrivate {
+		q = q.Where(`"User"."private_profile" = 0`)
+	}
+	if err := q.Order("reviews.updated_at DESC").Find(&reviews).Error; err != nil {
+		log.Printf("error listing reviews: %s\n", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Ratings returns the aggregated rating of the passed documents, indexed by slug.
+// Documents without reviews are not included. Ratings from all users are taken into account,
+// as averages do not disclose who rated a document.
+func (u *ReviewRepository) Ratings(documentSlugs []string) (map[string]DocumentRating, error) {
+	ratings := make(map[string]DocumentRating, len(documentSlugs))
+	if len(documentSlugs) == 0 {
+		return ratings, nil
+	}
+
+	var rows []DocumentRating
+	err := u.DB.Model(&Review{}).
+		Select("slug, AVG(rating) AS average, COUNT(*) AS reviews").
+		Where("slug IN ?", documentSlugs).
+		Group("slug").
+		Scan(&rows).Error
+	if err != nil {
+		log.Printf("error getting ratings: %s\n", err)
+		return ratings, err
+	}
+
+	for _, row := range rows {
+		ratings[row.Slug] = row
+	}
+	return ratings, nil
+}
+
+// Rated returns the aggregated rating of the documents with reviews, from the best to the worst rated and
+// with the most reviewed first on ties, skipping the first offset ones and returning up to limit of them
+func (u *ReviewRepository) Rated(offset, limit int) ([]DocumentRating, error) {
+	var rows []DocumentRating
+	err := u.DB.Model(&Review{}).
+		Select("slug, AVG(rating) AS average, COUNT(*) AS reviews").
+		Group("slug").
+		Order("average DESC, reviews DESC, slug").
+		Offset(offset).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		log.Printf("error getting ratings: %s\n", err)
+	}
+	return rows, err
+}
+
+// RatedPaginatedResult adds the aggregated rating to each of the passed documents
+func (u *ReviewRepository) RatedPaginatedResult(results result.Paginated[[]AugmentedDocument]) result.Paginated[[]AugmentedDocument] {
+	slugs := make([]string, 0, len(results.Hits()))
+	for _, searchResult := range results.Hits() {
+		slugs = append(slugs, searchResult.Slug)
+	}
+
+	ratings, _ := u.Ratings(slugs)
+
+	searchResults := make([]AugmentedDocument, len(results.Hits()))
+	for i, searchResult := range results.Hits() {
+		searchResult.Rating = ratings[searchResult.Slug]
+		searchResults[i] = searchResult
+	}
+
+	return result.NewPaginated(
+		ResultsPerPage,
+		results.Page(),
+		results.TotalHits(),
+		searchResults,
+	)
+}
+
+// Reviews returns the reviews written by a user, newest first. Reviews whose documents are missing
+// from the index are omitted from Hits() but still count toward TotalHits.
+func (u *ReviewRepository) Reviews(userID int, page int, resultsPerPage int) (result.Paginated[[]ReviewEntry], error) {
+	if u.Idx == nil {
+		return result.Paginated[[]ReviewEntry]{}, errors.New("review repository: idx required for Reviews")
+	}
+
+	var reviews []Review
+	res := u.DB.Scopes(Paginate(page, resultsPerPage)).Where("user_id = ?", userID).Order("updated_at DESC, id DESC").Find(&reviews)
+	if res.Error != nil {
+		log.Printf("error listing reviews: %s\n", res.Error)
+		return result.Paginated[[]ReviewEntry]{}, res.Error
+	}
+	var total int64
+	if err := u.DB.Model(&Review{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		log.Printf("error counting reviews: %s\n", err)
+		return result.Paginated[[]ReviewEntry]{}, err
+	}
+
+	slugs := make([]string, 0, len(reviews))
+	for _, r := range reviews {
+		slugs = append(slugs, r.Slug)
+	}
+	slices.Sort(slugs)
+	docBySlug, err := u.Idx.Documents(slices.Compact(slugs))
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return result.Paginated[[]ReviewEntry]{}, err
+	}
+
+	entries := make([]ReviewEntry, 0, len(reviews))
+	for _, r := range reviews {
+		if doc, ok := docBySlug[r.Slug]; ok {
+			entries = append(entries, ReviewEntry{Review: r, Document: doc})
+		}
+	}
+
+	return result.NewPaginated(resultsPerPage, page, int(total), entries), nil
+}
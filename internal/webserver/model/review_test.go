@@ -0,0 +1,74 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestReviewValidate(t *testing.T) {
+	if errs := (Review{Rating: 4, Text: "Great"}).Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if errs := (Review{Rating: 0}).Validate(); errs["rating"] == "" {
+		t.Error("Expected reviews without rating to be invalid")
+	}
+	if errs := (Review{Rating: MaxRating + 1}).Validate(); errs["rating"] == "" {
+		t.Error("Expected ratings above the maximum to be invalid")
+	}
+	if errs := (Review{Rating: 3, Text: strings.Repeat("ñ", ReviewTextMaxLength)}).Validate(); len(errs) != 0 {
+		t.Errorf("Expected text length to be counted in characters, got %v", errs)
+	}
+	if errs := (Review{Rating: 3, Text: strings.Repeat("a", ReviewTextMaxLength+1)}).Validate(); errs["text"] != "Review cannot be longer than 2000 characters" {
+		t.Errorf("Expected too long reviews to be invalid with the limit in the message, got '%s'", errs["text"])
+	}
+}
+
+func TestReviewRepositoryRated(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&Review{}); err != nil {
+		t.Fatal(err)
+	}
+	repo := &ReviewRepository{DB: db}
+
+	reviews := []Review{
+		{UserID: 1, Slug: "average", Rating: 3},
+		{UserID: 1, Slug: "best", Rating: 5},
+		{UserID: 1, Slug: "more-reviewed", Rating: 4},
+		{UserID: 2, Slug: "more-reviewed", Rating: 4},
+		{UserID: 1, Slug: "less-reviewed", Rating: 4},
+	}
+	if err := db.Create(&reviews).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ratings, err := repo.Rated(0, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"best", "more-reviewed", "less-reviewed", "average"}
+	if len(ratings) != len(expected) {
+		t.Fatalf("Expected %d ratings, got %d", len(expected), len(ratings))
+	}
+	for i, slug := range expected {
+		if ratings[i].Slug != slug {
+			t.Errorf("Expected '%s' in position %d, got '%s'", slug, i, ratings[i].Slug)
+		}
+	}
+	if ratings[1].Average != 4 || ratings[1].Reviews != 2 {
+		t.Errorf("Expected average 4 with 2 reviews, got %v with %d", ratings[1].Average, ratings[1].Reviews)
+	}
+
+	ratings, err = repo.Rated(1, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ratings) != 2 || ratings[0].Slug != "more-reviewed" || ratings[1].Slug != "less-reviewed" {
+		t.Errorf("Expected the second and third best rated documents, got %v", ratings)
+	}
+}
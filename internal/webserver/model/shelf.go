@@ -0,0 +1,80 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ShelfNameMaxLength is the maximum number of characters of a shelf's name
+const ShelfNameMaxLength = 50
+
+// ErrShelfNameTaken is returned when a user tries to have two shelves with the same name
+var ErrShelfNameTaken = errors.New("shelf name already in use")
+
+// Shelf is a named collection of documents created by a user, in the order chosen by its owner.
+// Shelves are private by default, but can be shared with other users or made public.
+type Shelf struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    int    `gorm:"uniqueIndex:idx_shelf_name; not null"`
+	Name      string `gorm:"type:text collate nocase; uniqueIndex:idx_shelf_name; not null"`
+	// Public shelves can be seen by anyone with access to the library
+	Public    bool `gorm:"default:false; not null"`
+	User      User
+	Documents []ShelfDocument `gorm:"constraint:OnDelete:CASCADE"`
+	Members   []ShelfMember   `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ShelfDocument is a document placed in a shelf. Position sets its order inside the shelf, starting at 0.
+type ShelfDocument struct {
+	CreatedAt time.Time
+	ShelfID   uint   `gorm:"primaryKey"`
+	Slug      string `gorm:"primaryKey; index"`
+	Position  int    `gorm:"not null"`
+}
+
+// ShelfMember is a user a shelf has been shared with, who can see it but not modify it
+type ShelfMember struct {
+	CreatedAt time.Time
+	ShelfID   uint `gorm:"primaryKey"`
+	UserID    int  `gorm:"primaryKey"`
+	User      User `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ShelfChoice is one of the shelves of a user, telling whether a document is placed in it
+type ShelfChoice struct {
+	Shelf
+	Contains bool
+}
+
+// Validate checks all shelf's fields to ensure they are in the required format
+func (s Shelf) Validate() map[string]string {
+	errs := map[string]string{}
+
+	if strings.TrimSpace(s.Name) == "" {
+		errs["name"] = "Name cannot be empty"
+	}
+
+	if utf8.RuneCountInString(s.Name) > ShelfNameMaxLength {
+		errs["name"] = "Name cannot be longer than 50 characters"
+	}
+
+	return errs
+}
+
+// CanView tells whether a user can see the shelf, that is, if they own it, it has been shared with them
+// or it is public and its owner does not have a private profile. Shelf owner and members must be loaded.
+func (s Shelf) CanView(userID int) bool {
+	if (s.Public && s.User.PrivateProfile == 0) || (userID > 0 && s.UserID == userID) {
+		return true
+	}
+	for _, member := range s.Members {
+		if userID > 0 && member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
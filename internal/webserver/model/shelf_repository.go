@@ -0,0 +1,226 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/svera/coreander/v4/internal/index"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShelfRepository struct {
+	DB  *gorm.DB
+	Idx idxReader
+}
+
+// Get returns a shelf along with its owner and members, or nil if it does not exist
+func (u *ShelfRepository) Get(shelfID uint) (*Shelf, error) {
+	var shelf Shelf
+	err := u.DB.Preload("User").Preload("Members.User").Preload("Documents").First(&shelf, shelfID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("error getting shelf: %s\n", err)
+		return nil, err
+	}
+	return &shelf, nil
+}
+
+// Owned returns the shelves created by a user, sorted by name
+func (u *ShelfRepository) Owned(userID int) ([]Shelf, error) {
+	shelves := []Shelf{}
+	if err := u.DB.Preload("Documents").Where("user_id = ?", userID).Order("name").Find(&shelves).Error; err != nil {
+		log.Printf("error listing shelves: %s\n", err)
+		return nil, err
+	}
+	return shelves, nil
+}
+
+// SharedWith returns the shelves other users shared with a user, sorted by name
+func (u *ShelfRepository) SharedWith(userID int) ([]Shelf, error) {
+	shelves := []Shelf{}
+	err := u.DB.Preload("User").Preload("Documents").
+		Where("id IN (?)", u.DB.Model(&ShelfMember{}).Select("shelf_id").Where("user_id = ?", userID)).
+		Order("name").Find(&shelves).Error
+	if err != nil {
+		log.Printf("error listing shared shelves: %s\n", err)
+		return nil, err
+	}
+	return shelves, nil
+}
+
+// PublicShelves returns the public shelves created by users other than the passed one, sorted by name.
+// Shelves of users with a private profile are left out, even if they were made public before.
+func (u *ShelfRepository) PublicShelves(exceptUserID int) ([]Shelf, error) {
+	shelves := []Shelf{}
+	err := u.DB.Preload("User").Preload("Documents").
+		Where("public = ? AND user_id <> ?", true, exceptUserID).
+		Where("user_id IN (?)", u.DB.Model(&User{}).Select("id").Where("private_profile = ?", 0)).
+		Order("name").Find(&shelves).Error
+	if err != nil {
+		log.Printf("error listing public shelves: %s\n", err)
+		return nil, err
+	}
+	return shelves, nil
+}
+
+// Choices returns the shelves of a user, telling which ones contain the passed document
+func (u *ShelfRepository) Choices(userID int, documentSlug string) ([]ShelfChoice, error) {
+	shelves, err := u.Owned(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	choices := make([]ShelfChoice, len(shelves))
+	for i, shelf := range shelves {
+		choices[i] = ShelfChoice{
+			Shelf: shelf,
+			Contains: slices.ContainsFunc(shelf.Documents, func(doc ShelfDocument) bool {
+				return doc.Slug == documentSlug
+			}),
+		}
+	}
+	return choices, nil
+}
+
+// Save creates or updates a shelf, as long as its owner does not have another one with the same name
+func (u *ShelfRepository) Save(shelf *Shelf) error {
+	var count int64
+	if err := u.DB.Model(&Shelf{}).Where("user_id = ? AND name = ? AND id <> ?", shelf.UserID, shelf.Name, shelf.ID).Count(&count).Error; err != nil {
+		log.Printf("error checking shelf name: %s\n", err)
+		return err
+	}
+	if count > 0 {
+		return ErrShelfNameTaken
+	}
+
+	var err error
+	if shelf.ID == 0 {
+		err = u.DB.Create(shelf).Error
+	} else {
+		err = u.DB.Model(shelf).Select("name", "public").Updates(shelf).Error
+	}
+	if err != nil {
+		log.Printf("error saving shelf: %s\n", err)
+	}
+	return err
+}
+
+// Delete removes a shelf, as long as it belongs to the user
+func (u *ShelfRepository) Delete(userID int, shelfID uint) error {
+	err := u.DB.Where("user_id = ? AND id = ?", userID, shelfID).Delete(&Shelf{}).Error
+	if err != nil {
+		log.Printf("error deleting shelf: %s\n", err)
+	}
+	return err
+}
+
+// AddToShelf places a document at the end of a shelf. Documents already in the shelf are left where they are.
+func (u *ShelfRepository) AddToShelf(shelfID uint, documentSlug string) error {
+	var last int
+	err := u.DB.Model(&ShelfDocument{}).Select("COALESCE(MAX(position) + 1, 0)").Where("shelf_id = ?", shelfID).Scan(&last).Error
+	if err != nil {
+		log.Printf("error adding document to shelf: %s\n", err)
+		return err
+	}
+
+	shelfDocument := ShelfDocument{ShelfID: shelfID, Slug: documentSlug, Position: last}
+	if err = u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&shelfDocument).Error; err != nil {
+		log.Printf("error adding document to shelf: %s\n", err)
+	}
+	return err
+}
+
+func (u *ShelfRepository) RemoveFromShelf(shelfID uint, documentSlug string) error {
+	err := u.DB.Where("shelf_id = ? AND slug = ?", shelfID, documentSlug).Delete(&ShelfDocument{}).Error
+	if err != nil {
+		log.Printf("error removing document from shelf: %s\n", err)
+	}
+	return err
+}
+
+// Move places a document of a shelf at a new position, shifting the rest of documents accordingly.
+// Positions out of range move the document to the start or the end of the shelf.
+func (u *ShelfRepository) Move(shelfID uint, documentSlug string, position int) error {
+	return u.DB.Transaction(func(tx *gorm.DB) error {
+		var slugs []string
+		if err := tx.Model(&ShelfDocument{}).Where("shelf_id = ?", shelfID).Order("position, created_at").Pluck("slug", &slugs).Error; err != nil {
+			return err
+		}
+
+		slugs, ok := moveSlug(slugs, documentSlug, position)
+		if !ok {
+			return nil
+		}
+
+		for i, slug := range slugs {
+			if err := tx.Model(&ShelfDocument{}).Where("shelf_id = ? AND slug = ?", shelfID, slug).UpdateColumn("position", i).Error; err != nil {
+				log.Printf("error moving document in shelf: %s\n", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Slugs returns the slugs of the documents in a shelf
+func (u *ShelfRepository) Slugs(shelfID uint) ([]string, error) {
+	slugs := []string{}
+	if err := u.DB.Model(&ShelfDocument{}).Where("shelf_id = ?", shelfID).Order("position").Pluck("slug", &slugs).Error; err != nil {
+		log.Printf("error getting shelf documents: %s\n", err)
+		return nil, err
+	}
+	return slugs, nil
+}
+
+// Documents returns the documents in a shelf, in the order set by its owner.
+// Documents missing from the index are omitted.
+func (u *ShelfRepository) Documents(shelfID uint) ([]index.Document, error) {
+	if u.Idx == nil {
+		return nil, errors.New("shelf repository: idx required for Documents")
+	}
+
+	slugs, err := u.Slugs(shelfID)
+	if err != nil {
+		return nil, err
+	}
+
+	docBySlug, err := u.Idx.Documents(slugs)
+	if err != nil {
+		log.Printf("error getting documents: %s\n", err)
+		return nil, err
+	}
+
+	documents := make([]index.Document, 0, len(slugs))
+	for _, slug := range slugs {
+		if doc, ok := docBySlug[slug]; ok {
+			documents = append(documents, doc)
+		}
+	}
+	return documents, nil
+}
+
+// Share lets another user see a shelf
+func (u *ShelfRepository) Share(shelfID uint, userID int) error {
+	member := ShelfMember{ShelfID: shelfID, UserID: userID}
+	err := u.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&member).Error
+	if err != nil {
+		log.Printf("error sharing shelf: %s\n", err)
+	}
+	return err
+}
+
+func (u *ShelfRepository) Unshare(shelfID uint, userID int) error {
+	err := u.DB.Where("shelf_id = ? AND user_id = ?", shelfID, userID).Delete(&ShelfMember{}).Error
+	if err != nil {
+		log.Printf("error unsharing shelf: %s\n", err)
+	}
+	return err
+}
+
+func (u *ShelfRepository) RemoveDocument(documentSlug string) error {
+	return u.DB.Where("slug = ?", documentSlug).Delete(&ShelfDocument{}).Error
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShelfValidate(t *testing.T) {
+	if errs := (Shelf{Name: "To read"}).Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+	if errs := (Shelf{Name: "  "}).Validate(); errs["name"] == "" {
+		t.Error("Expected shelves without name to be invalid")
+	}
+	if errs := (Shelf{Name: strings.Repeat("a", ShelfNameMaxLength+1)}).Validate(); errs["name"] == "" {
+		t.Error("Expected too long names to be invalid")
+	}
+}
+
+func TestShelfCanView(t *testing.T) {
+	shelf := Shelf{UserID: 1, Members: []ShelfMember{{UserID: 2}}}
+
+	for userID, expected := range map[int]bool{0: false, 1: true, 2: true, 3: false} {
+		if got := shelf.CanView(userID); got != expected {
+			t.Errorf("Expected CanView(%d) to be %t, got %t", userID, expected, got)
+		}
+	}
+
+	shelf.Public = true
+	if !shelf.CanView(0) {
+		t.Error("Expected public shelves to be visible to anyone")
+	}
+
+	shelf.User.PrivateProfile = 1
+	if shelf.CanView(0) || shelf.CanView(3) {
+		t.Error("Expected public shelves of users with a private profile to be hidden")
+	}
+	if !shelf.CanView(1) || !shelf.CanView(2) {
+		t.Error("Expected owners and members to still see shelves of users with a private profile")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"slices"
 	"time"
+	"unicode/utf8"
 )
 
 // User roles identifiers
@@ -15,7 +16,9 @@ const (
 
 const UsernamePattern = `^[A-z0-9_\-.]+$`
 
-var AllowedDefaultActions = []string{"download", "send", "share", "copy"}
+const UsernameMaxLength = 20
+
+var AllowedDefaultActions = []string{"download", "send", "share", "copy", "queue"}
 
 type User struct {
 	ID                 uint `gorm:"primarykey"`
@@ -31,16 +34,32 @@ type User struct {
 	WordsPerMinute     float64
 	RecoveryUUID       string
 	RecoveryValidUntil time.Time
-	Highlights         []Highlight `gorm:"constraint:OnDelete:CASCADE"`
-	Readings           []Reading   `gorm:"constraint:OnDelete:CASCADE"`
+	Highlights         []Highlight      `gorm:"constraint:OnDelete:CASCADE"`
+	Readings           []Reading        `gorm:"constraint:OnDelete:CASCADE"`
+	Passkeys           []Passkey        `gorm:"constraint:OnDelete:CASCADE"`
+	Reviews            []Review         `gorm:"constraint:OnDelete:CASCADE"`
+	Shelves            []Shelf          `gorm:"constraint:OnDelete:CASCADE"`
+	Queue              []QueuedDocument `gorm:"constraint:OnDelete:CASCADE"`
+	ReadingSessions    []ReadingSession `gorm:"constraint:OnDelete:CASCADE"`
+	ReadThroughs       []ReadThrough    `gorm:"constraint:OnDelete:CASCADE"`
 	LastRequest        time.Time
 	ShowFileName       bool   `gorm:"default:false; not null"`
 	PrivateProfile     int    `gorm:"default:0; not null"`
+	ShareActivity      bool   `gorm:"default:false; not null"`
 	PreferredEpubType  string `gorm:"default:'epub'; not null"`
 	DefaultAction      string `gorm:"default:'download'; not null"`
 	Language           string
 }
 
+// TruncateName shortens a name received from an external source to the 50 characters allowed.
+// Characters are counted instead of bytes, so multi-byte ones are not cut in half.
+func TruncateName(name string) string {
+	if utf8.RuneCountInString(name) <= 50 {
+		return name
+	}
+	return string([]rune(name)[:50])
+}
+
 // Validate checks all user's fields to ensure they are in the required format
 func (u User) Validate(minPasswordLength int) map[string]string {
 	errs := map[string]string{}
@@ -57,7 +76,7 @@ func (u User) Validate(minPasswordLength int) map[string]string {
 		errs["username"] = "Username cannot be empty"
 	}
 
-	if len(u.Username) > 20 {
+	if len(u.Username) > UsernameMaxLength {
 		errs["username"] = "Username cannot be longer than 20 characters"
 	}
 
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/svera/coreander/v4/internal/result"
 	"gorm.io/gorm"
 )
 
+var invalidUsernameChars = regexp.MustCompile(`[^a-z0-9_\-.]`)
+
 type UserRepository struct {
 	DB *gorm.DB
 }
@@ -120,6 +125,20 @@ func (u *UserRepository) Admins() int64 {
 	return totalRows
 }
 
+// PreferKepub returns how many users download EPUB files converted to KEPUB
+func (u *UserRepository) PreferKepub() int64 {
+	var totalRows int64
+	u.DB.Model(&User{}).Where("preferred_epub_type = ?", "kepub").Count(&totalRows)
+	return totalRows
+}
+
+// ActiveSince returns how many users made a request after the given time
+func (u *UserRepository) ActiveSince(since time.Time) int64 {
+	var totalRows int64
+	u.DB.Model(&User{}).Where("last_request > ?", since).Count(&totalRows)
+	return totalRows
+}
+
 func (u *UserRepository) Delete(uuid string) error {
 	var user User
 
@@ -130,6 +149,36 @@ func (u *UserRepository) Delete(uuid string) error {
 	return nil
 }
 
+// AvailableUsername derives a valid username from candidate, falling back to the local part
+// of email if candidate is empty, and appends a numeric suffix if it is already taken
+func (u *UserRepository) AvailableUsername(candidate, email string) (string, error) {
+	base := invalidUsernameChars.ReplaceAllString(strings.ToLower(candidate), "_")
+	if base == "" {
+		localPart, _, _ := strings.Cut(email, "@")
+		base = invalidUsernameChars.ReplaceAllString(strings.ToLower(localPart), "_")
+	}
+	if len(base) > UsernameMaxLength {
+		base = base[:UsernameMaxLength]
+	}
+
+	username := base
+	for i := 2; ; i++ {
+		existing, err := u.FindByUsername(username)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return username, nil
+		}
+		suffix := strconv.Itoa(i)
+		username = base
+		if len(username)+len(suffix) > UsernameMaxLength {
+			username = username[:UsernameMaxLength-len(suffix)]
+		}
+		username += suffix
+	}
+}
+
 func Hash(s string) string {
 	h := sha256.New()
 	h.Write([]byte(s))
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateName(t *testing.T) {
+	if got := TruncateName("Ana"); got != "Ana" {
+		t.Errorf("Expected short names to be kept, got '%s'", got)
+	}
+
+	got := TruncateName(strings.Repeat("ñ", 60))
+	if !utf8.ValidString(got) {
+		t.Errorf("Expected a valid UTF-8 name, got '%s'", got)
+	}
+	if count := utf8.RuneCountInString(got); count != 50 {
+		t.Errorf("Expected 50 characters, got %d", count)
+	}
+}
@@ -0,0 +1,209 @@
+package webserver_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/versioncheck"
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestNotifications(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	smtpMock := &infrastructure.SMTPMock{}
+	app := bootstrapApp(db, smtpMock, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+	notificationsRepository := &model.NotificationRepository{DB: db}
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	var admin, regular model.User
+	db.Where("username = ?", "admin").First(&admin)
+	db.Where("username = ?", "regular").First(&regular)
+
+	page := func(t *testing.T, cookie *http.Cookie, URL string) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	t.Run("Series can be followed to be notified about new documents", func(t *testing.T) {
+		response, err := postRequest(url.Values{}, regularCookie, app, "/series/non-existing-series/followers", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNotFound, t)
+
+		response, err = postRequest(url.Values{}, regularCookie, app, "/series/the-lord-of-the-rings/followers", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		followers, _ := notificationsRepository.SeriesFollowers("the-lord-of-the-rings")
+		if len(followers) != 1 || followers[0] != int(regular.ID) {
+			t.Errorf("Expected regular user to follow the series, got %v", followers)
+		}
+
+		doc := page(t, regularCookie, "/series/the-lord-of-the-rings")
+		if _, ok := doc.Find("#series-follow").Attr("hx-delete"); !ok {
+			t.Error("Expected series page to allow unfollowing the series")
+		}
+
+		response, err = deleteRequest(url.Values{}, regularCookie, app, "/series/the-lord-of-the-rings/followers", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		if notificationsRepository.FollowsSeries(int(regular.ID), "the-lord-of-the-rings") {
+			t.Error("Expected regular user to have stopped following the series")
+		}
+	})
+
+	t.Run("Accepted invitations notify who sent them", func(t *testing.T) {
+		db.Create(&model.Invitation{
+			Email:      "invited@example.com",
+			UUID:       "invited-uuid",
+			ValidUntil: time.Now().Add(time.Hour),
+			InviterID:  int(admin.ID),
+		})
+
+		_, err := postRequest(url.Values{
+			"invitation_uuid":  {"invited-uuid"},
+			"name":             {"Invited user"},
+			"username":         {"invited"},
+			"password":         {"password123"},
+			"confirm-password": {"password123"},
+		}, &http.Cookie{}, app, "/invite", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		if unread := notificationsRepository.Unread(int(admin.ID)); unread != 1 {
+			t.Fatalf("Expected admin to have 1 unread notification, got %d", unread)
+		}
+
+		doc := page(t, adminCookie, "/notifications")
+		if got, _ := doc.Find("#notifications li.notification.unread").Attr("data-type"); got != model.NotificationInvitationAccepted {
+			t.Errorf("Expected an unread invitation accepted notification, got '%s'", got)
+		}
+
+		doc = page(t, adminCookie, "/notifications/unread")
+		if got := doc.Find("#notifications-unread").Text(); got == "" {
+			t.Error("Expected unread notifications badge to be shown")
+		}
+	})
+
+	t.Run("Opening a notification marks it as read", func(t *testing.T) {
+		notifications, _ := notificationsRepository.Notifications(int(admin.ID), 1, model.ResultsPerPage)
+		notification := notifications.Hits()[0]
+
+		response, err := getRequest(regularCookie, app, fmt.Sprintf("/notifications/%d", notification.ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNotFound, t)
+
+		response, err = getRequest(adminCookie, app, fmt.Sprintf("/notifications/%d", notification.ID), t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusSeeOther, t)
+		if location := response.Header.Get("Location"); location != "/users/invited" {
+			t.Errorf("Expected to be redirected to the new user, got '%s'", location)
+		}
+
+		if unread := notificationsRepository.Unread(int(admin.ID)); unread != 0 {
+			t.Errorf("Expected admin to have no unread notifications, got %d", unread)
+		}
+	})
+
+	t.Run("All notifications can be marked as read", func(t *testing.T) {
+		notificationsRepository.Notify([]int{int(regular.ID)}, model.Notification{Type: model.NotificationNewInSeries, Subject: "The Lord of the Rings", Slug: testDocSlug, Title: "Test", Link: "/documents/" + testDocSlug}, false)
+		notificationsRepository.Notify([]int{int(regular.ID)}, model.Notification{Type: model.NotificationShared, Actor: "Admin", Slug: testDocSlug, Title: "Test", Link: "/documents/" + testDocSlug}, true)
+
+		response, err := postRequest(url.Values{}, regularCookie, app, "/notifications/read", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		if unread := notificationsRepository.Unread(int(regular.ID)); unread != 0 {
+			t.Errorf("Expected regular user to have no unread notifications, got %d", unread)
+		}
+		if unread := notificationsRepository.Unread(int(admin.ID)); unread != 0 {
+			t.Errorf("Expected admin notifications to be left untouched, got %d unread", unread)
+		}
+	})
+
+	t.Run("Users choose which notifications are emailed", func(t *testing.T) {
+		if !notificationsRepository.WantsEmail(int(regular.ID), model.NotificationShared) {
+			t.Error("Expected shares to be emailed by default")
+		}
+		if notificationsRepository.WantsEmail(int(regular.ID), model.NotificationNewInSeries) {
+			t.Error("Expected new documents in series not to be emailed by default")
+		}
+
+		response, err := putRequest(url.Values{"email-" + model.NotificationNewInSeries: {"on"}}, regularCookie, app, "/notifications/preferences", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		if notificationsRepository.WantsEmail(int(regular.ID), model.NotificationShared) {
+			t.Error("Expected shares not to be emailed anymore")
+		}
+		if !notificationsRepository.WantsEmail(int(regular.ID), model.NotificationNewInSeries) {
+			t.Error("Expected new documents in series to be emailed")
+		}
+	})
+
+	t.Run("Notifier emails pending notifications and tells admins about new versions", func(t *testing.T) {
+		checker := versioncheck.NewWithFetcher("v1.0.0", func() (string, error) {
+			return "v9.0.0", nil
+		})
+		checker.Refresh()
+		notifier := webserver.NewNotifier(notificationsRepository, smtpMock, checker, "localhost", app)
+
+		notificationsRepository.Notify([]int{int(regular.ID)}, model.Notification{Type: model.NotificationNewInSeries, Subject: "The Lord of the Rings", Slug: testDocSlug, Title: "Test", Link: "/documents/" + testDocSlug}, false)
+
+		smtpMock.Wg.Add(1)
+		notifier.Deliver()
+		smtpMock.Wg.Wait()
+
+		pending, _ := notificationsRepository.PendingEmails()
+		if len(pending) != 0 {
+			t.Errorf("Expected no pending emails, got %d", len(pending))
+		}
+
+		notifier.Deliver()
+		var count int64
+		db.Model(&model.Notification{}).Where("user_id = ? AND type = ?", admin.ID, model.NotificationNewVersion).Count(&count)
+		if count != 1 {
+			t.Errorf("Expected admin to be notified once about the new version, got %d notifications", count)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package webserver
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/svera/coreander/v4/internal/versioncheck"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// NotificationDeliveryInterval is how often pending notifications are delivered
+const NotificationDeliveryInterval = time.Minute
+
+type notificationsDeliveryRepository interface {
+	NotifyAdmins(notification model.Notification)
+	PendingEmails() ([]model.Notification, error)
+	MarkEmailed(notificationID uint) error
+}
+
+// Notifier periodically notifies administrators about new versions and emails the notifications
+// users chose to receive by email, so actions generating notifications do not wait for the mail server.
+type Notifier struct {
+	notificationsRepository notificationsDeliveryRepository
+	sender                  Sender
+	versionChecker          *versioncheck.Checker
+	composer                *mailer.Composer
+	fqdn                    string
+}
+
+// NewNotifier creates a notifier which renders emails using the views of the passed app
+func NewNotifier(notificationsRepository notificationsDeliveryRepository, sender Sender, versionChecker *versioncheck.Checker, fqdn string, app *fiber.App) *Notifier {
+	if !strings.HasPrefix(fqdn, "http://") && !strings.HasPrefix(fqdn, "https://") {
+		fqdn = "http://" + fqdn
+	}
+	return &Notifier{
+		notificationsRepository: notificationsRepository,
+		sender:                  sender,
+		versionChecker:          versionChecker,
+		composer:                mailer.NewComposer(app.Config().Views, translator),
+		fqdn:                    fqdn,
+	}
+}
+
+// Start delivers notifications every NotificationDeliveryInterval until the process exits
+func (n *Notifier) Start() {
+	go n.run()
+}
+
+func (n *Notifier) run() {
+	ticker := time.NewTicker(NotificationDeliveryInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		n.Deliver()
+	}
+}
+
+// Deliver notifies administrators about a new version if there is one, and sends the pending notification emails.
+// Emails are left pending if email sending is not configured.
+func (n *Notifier) Deliver() {
+	if n.versionChecker != nil {
+		if latest, outdated := n.versionChecker.Outdated(); outdated {
+			n.notificationsRepository.NotifyAdmins(model.Notification{
+				Type:    model.NotificationNewVersion,
+				Subject: latest,
+				Link:    versioncheck.ReleasesPageURL,
+			})
+		}
+	}
+
+	if _, ok := n.sender.(*infrastructure.NoEmail); ok {
+		return
+	}
+
+	pending, err := n.notificationsRepository.PendingEmails()
+	if err != nil {
+		return
+	}
+
+	for _, notification := range pending {
+		link := notification.Link
+		if strings.HasPrefix(link, "/") {
+			link = n.fqdn + link
+		}
+
+		lang := n.composer.Language(notification.User.Language)
+		subject, values := notificationSubject(notification)
+		message, err := n.composer.Compose("notification", lang, fiber.Map{
+			"Notification": notification,
+			"Link":         link,
+		}, subject, values...)
+		if err != nil {
+			log.Printf("error rendering notification email: %s\n", err)
+			continue
+		}
+
+		if err := n.sender.Send(notification.User.Email, message); err != nil {
+			log.Printf("error sending notification to %s: %s\n", notification.User.Email, err)
+			continue
+		}
+		n.notificationsRepository.MarkEmailed(notification.ID)
+	}
+}
+
+// notificationSubject returns the translation key and values of the subject of the email sent for a notification,
+// which is the same text shown in the notification center
+func notificationSubject(notification model.Notification) (string, []any) {
+	switch notification.Type {
+	case model.NotificationShared:
+		return "%s shared \"%s\"", []any{notification.Actor, notification.Title}
+	case model.NotificationInvitationAccepted:
+		return "%s accepted your invitation", []any{notification.Actor}
+	case model.NotificationNewInSeries:
+		return "New document in the collection \"%s\"", []any{notification.Subject}
+	case model.NotificationMentioned:
+		return "%s mentioned you in the discussion of \"%s\"", []any{notification.Actor, notification.Title}
+	case model.NotificationNewVersion:
+		return "Coreander %s is available", []any{notification.Subject}
+	}
+	return "Notifications", nil
+}
@@ -0,0 +1,31 @@
+package webserver
+
+import (
+	"io/fs"
+	"log"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// serviceWorker serves the script that keeps the application working offline. It is served from the root,
+// as service workers can only control pages under the path they are served from, and revalidated on every
+// visit so browsers pick up new versions as soon as they are deployed.
+func serviceWorker(c fiber.Ctx) error {
+	script, err := fs.ReadFile(jsFS, "service-worker.js")
+	if err != nil {
+		log.Printf("error reading service worker: %s\n", err)
+		return fiber.ErrInternalServerError
+	}
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("Service-Worker-Allowed", "/")
+	c.Type("js", "utf-8")
+	return c.Send(script)
+}
+
+// offline renders the page shown by the service worker when a page which is not available offline is requested
+// without a connection. It lists the documents pinned for offline reading.
+func offline(c fiber.Ctx) error {
+	return c.Render("offline", fiber.Map{
+		"Title": "You are offline",
+	}, "layout")
+}
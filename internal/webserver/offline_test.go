@@ -0,0 +1,159 @@
+package webserver_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestOffline(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	appFs := loadFilesInMemoryFs([]string{
+		"testdata/library/metadata.epub",
+		"testdata/library/quijote.epub",
+	})
+	webserverConfig := webserver.Config{
+		SessionTimeout: 24 * time.Hour,
+		LibraryPath:    "testdata/library",
+		WordsPerMinute: 250,
+		RequireAuth:    true,
+	}
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, appFs, webserverConfig)
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Service worker is served from the root and revalidated", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/service-worker.js", nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Cache-Control"); got != "no-cache" {
+			t.Errorf("Expected service worker not to be cached, got Cache-Control '%s'", got)
+		}
+		if got := resp.Header.Get("Content-Type"); !strings.Contains(got, "javascript") {
+			t.Errorf("Expected a JavaScript content type, got '%s'", got)
+		}
+	})
+
+	t.Run("Offline page is available without logging in", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/offline", nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), `id="offline-documents"`) {
+			t.Error("Expected the offline page to include the list of offline documents")
+		}
+	})
+
+	t.Run("Pages tell the service worker which user is logged in", func(t *testing.T) {
+		for _, path := range []string{"/documents", "/documents/" + testDocSlug + "/read?offline=true"} {
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			req.AddCookie(adminCookie)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if !strings.Contains(string(body), `<meta name="user-id" content="1">`) {
+				t.Errorf("Expected %s to include the logged in user", path)
+			}
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "/offline", nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if strings.Contains(string(body), `name="user-id"`) {
+			t.Error("Expected pages without a logged in user not to include one")
+		}
+	})
+
+	t.Run("Downloading the reader for offline use does not mark the document as opened", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/documents/"+testDocSlug+"/read?offline=true", nil)
+		req.AddCookie(adminCookie)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+
+		var count int64
+		db.Model(&model.Reading{}).Where("slug = ?", testDocSlug).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected no reading to be recorded, got %d", count)
+		}
+	})
+
+	t.Run("Positions saved while offline don't overwrite later ones", func(t *testing.T) {
+		putPosition := func(body string) {
+			req, _ := http.NewRequest(http.MethodPut, "/documents/"+testDocSlug+"/position", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.AddCookie(adminCookie)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				t.Fatalf("PUT status %d, want 204", resp.StatusCode)
+			}
+		}
+		getPosition := func() string {
+			req, _ := http.NewRequest(http.MethodGet, "/documents/"+testDocSlug+"/position", nil)
+			req.AddCookie(adminCookie)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var out struct {
+				Position string `json:"position"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatal(err)
+			}
+			return out.Position
+		}
+
+		putPosition(`{"position":"epubcfi(/6/2!/8)","percentage":50}`)
+		earlier := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		putPosition(`{"position":"epubcfi(/6/2!/4)","percentage":20,"updated":"` + earlier + `"}`)
+		if got := getPosition(); got != "epubcfi(/6/2!/8)" {
+			t.Errorf("Expected the later position to be kept, got '%s'", got)
+		}
+
+		later := time.Now().Add(time.Second).UTC().Format(time.RFC3339)
+		putPosition(`{"position":"epubcfi(/6/2!/12)","percentage":70,"updated":"` + later + `"}`)
+		if got := getPosition(); got != "epubcfi(/6/2!/12)" {
+			t.Errorf("Expected the offline position to be stored, got '%s'", got)
+		}
+	})
+}
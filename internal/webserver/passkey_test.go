@@ -0,0 +1,341 @@
+package webserver_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/gofiber/fiber/v3"
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+const passkeyOrigin = "http://localhost"
+
+// virtualAuthenticator emulates a platform authenticator holding a single ES256 passkey,
+// which uses "none" attestation
+type virtualAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	counter      uint32
+}
+
+func newVirtualAuthenticator(t *testing.T) *virtualAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	credentialID := make([]byte, 16)
+	rand.Read(credentialID)
+	return &virtualAuthenticator{key: key, credentialID: credentialID}
+}
+
+type ceremonyOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		RPID string `json:"rpId"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+func (a *virtualAuthenticator) authenticatorData(rpID string, attestedCredential []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	// User present and user verified flags
+	flags := byte(0x01 | 0x04)
+	if attestedCredential != nil {
+		flags |= 0x40
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.counter)
+	return append(data, attestedCredential...)
+}
+
+func clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    passkeyOrigin,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	return data
+}
+
+func (a *virtualAuthenticator) create(t *testing.T, options ceremonyOptions) []byte {
+	t.Helper()
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(options.PublicKey.User.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	a.userHandle = userHandle
+
+	publicKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // Key type: EC2
+		3:  -7, // Algorithm: ES256
+		-1: 1,  // Curve: P-256
+		-2: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	attestedCredential := make([]byte, 16) // Zeroed AAGUID
+	attestedCredential = binary.BigEndian.AppendUint16(attestedCredential, uint16(len(a.credentialID)))
+	attestedCredential = append(attestedCredential, a.credentialID...)
+	attestedCredential = append(attestedCredential, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authenticatorData(options.PublicKey.RP.ID, attestedCredential),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	return a.credential(t, map[string]any{
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData(t, "webauthn.create", options.PublicKey.Challenge)),
+	})
+}
+
+func (a *virtualAuthenticator) get(t *testing.T, options ceremonyOptions) []byte {
+	t.Helper()
+
+	a.counter++
+	authenticatorData := a.authenticatorData(options.PublicKey.RPID, nil)
+	clientDataJSON := clientData(t, "webauthn.get", options.PublicKey.Challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	return a.credential(t, map[string]any{
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authenticatorData),
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+		"userHandle":        base64.RawURLEncoding.EncodeToString(a.userHandle),
+	})
+}
+
+func (a *virtualAuthenticator) credential(t *testing.T, response map[string]any) []byte {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{
+		"id":       base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	return body
+}
+
+func jsonRequest(t *testing.T, app *fiber.App, method, URL string, body []byte, cookies ...*http.Cookie) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	for _, cookie := range cookies {
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+	}
+
+	response, err := app.Test(req)
+	if response == nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	return response
+}
+
+// beginCeremony requests the options for a ceremony, returning them along with the cookie
+// that has to be sent back when finishing it
+func beginCeremony(t *testing.T, app *fiber.App, URL string, cookie *http.Cookie) (ceremonyOptions, *http.Cookie) {
+	t.Helper()
+
+	var options ceremonyOptions
+	response := jsonRequest(t, app, http.MethodPost, URL, nil, cookie)
+	mustReturnStatus(response, http.StatusOK, t)
+	if err := json.NewDecoder(response.Body).Decode(&options); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	return options, responseCookie(response, "webauthn-ceremony")
+}
+
+func responseCookie(response *http.Response, name string) *http.Cookie {
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestPasskeys(t *testing.T) {
+	reset := func(t *testing.T) (*fiber.App, *http.Cookie, *virtualAuthenticator) {
+		t.Helper()
+
+		db := infrastructure.Connect(":memory:", 250)
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), defaultTestConfig())
+
+		adminCookie, err := login(app, "admin@example.com", "admin", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		authenticator := newVirtualAuthenticator(t)
+		options, ceremonyCookie := beginCeremony(t, app, "/users/admin/passkeys/options", adminCookie)
+		response := jsonRequest(t, app, http.MethodPost, "/users/admin/passkeys?name=Laptop", authenticator.create(t, options), adminCookie, ceremonyCookie)
+		mustReturnStatus(response, http.StatusCreated, t)
+
+		return app, adminCookie, authenticator
+	}
+
+	signIn := func(t *testing.T, app *fiber.App, authenticator *virtualAuthenticator) *http.Response {
+		t.Helper()
+
+		options, ceremonyCookie := beginCeremony(t, app, "/sessions/passkey/options", nil)
+		return jsonRequest(t, app, http.MethodPost, "/sessions/passkey", authenticator.get(t, options), ceremonyCookie)
+	}
+
+	t.Run("Registered passkeys are listed in the user's page", func(t *testing.T) {
+		app, adminCookie, _ := reset(t)
+
+		response, err := getRequest(adminCookie, app, "/users/admin/passkeys", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		body, _ := io.ReadAll(response.Body)
+		if !strings.Contains(string(body), "Laptop") {
+			t.Error("Expected registered passkey to be listed")
+		}
+	})
+
+	t.Run("Long passkey names are cut by characters", func(t *testing.T) {
+		app, adminCookie, _ := reset(t)
+
+		authenticator := newVirtualAuthenticator(t)
+		options, ceremonyCookie := beginCeremony(t, app, "/users/admin/passkeys/options", adminCookie)
+		name := url.QueryEscape(strings.Repeat("ñ", 60))
+		response := jsonRequest(t, app, http.MethodPost, "/users/admin/passkeys?name="+name, authenticator.create(t, options), adminCookie, ceremonyCookie)
+		mustReturnStatus(response, http.StatusCreated, t)
+
+		response, err := getRequest(adminCookie, app, "/users/admin/passkeys", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		body, _ := io.ReadAll(response.Body)
+		if !utf8.Valid(body) {
+			t.Error("Expected passkey name not to be cut in the middle of a character")
+		}
+		if !strings.Contains(string(body), strings.Repeat("ñ", 50)) || strings.Contains(string(body), strings.Repeat("ñ", 51)) {
+			t.Error("Expected passkey name to be cut to 50 characters")
+		}
+	})
+
+	t.Run("Users can sign in with a registered passkey", func(t *testing.T) {
+		app, _, authenticator := reset(t)
+
+		response := signIn(t, app, authenticator)
+		mustReturnStatus(response, http.StatusOK, t)
+		sessionCookie := responseCookie(response, "session")
+		if sessionCookie == nil {
+			t.Fatal("Expected session cookie to be set")
+		}
+
+		response, err := getRequest(sessionCookie, app, "/users", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+	})
+
+	t.Run("Replayed assertions are rejected", func(t *testing.T) {
+		app, _, authenticator := reset(t)
+
+		options, ceremonyCookie := beginCeremony(t, app, "/sessions/passkey/options", nil)
+		assertion := authenticator.get(t, options)
+		mustReturnStatus(jsonRequest(t, app, http.MethodPost, "/sessions/passkey", assertion, ceremonyCookie), http.StatusOK, t)
+		mustReturnStatus(jsonRequest(t, app, http.MethodPost, "/sessions/passkey", assertion, ceremonyCookie), http.StatusUnauthorized, t)
+	})
+
+	t.Run("Unknown passkeys cannot be used to sign in", func(t *testing.T) {
+		app, _, authenticator := reset(t)
+
+		authenticator.credentialID = []byte("unknown-credential")
+		mustReturnStatus(signIn(t, app, authenticator), http.StatusUnauthorized, t)
+	})
+
+	t.Run("Removed passkeys cannot be used to sign in", func(t *testing.T) {
+		app, adminCookie, authenticator := reset(t)
+
+		response, err := deleteRequest(nil, adminCookie, app, "/users/admin/passkeys/1", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		mustReturnStatus(signIn(t, app, authenticator), http.StatusUnauthorized, t)
+	})
+
+	t.Run("Users cannot manage other users' passkeys", func(t *testing.T) {
+		app, adminCookie, _ := reset(t)
+		addRegularUser(t, app, adminCookie)
+
+		regularCookie, err := login(app, "regular@example.com", "regular", t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		response, err := getRequest(regularCookie, app, "/users/admin/passkeys", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		response, err = deleteRequest(nil, regularCookie, app, "/users/admin/passkeys/1", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+
+		// Not even admins can register passkeys on behalf of other users
+		response = jsonRequest(t, app, http.MethodPost, "/users/regular/passkeys/options", nil, adminCookie)
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+}
@@ -0,0 +1,100 @@
+package webserver_test
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestProxyAuthentication(t *testing.T) {
+	proxyRequest := func(URL, username, email string) *http.Request {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, URL, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		req.Header.Set("Accept-Language", "en")
+		req.Header.Set("Remote-User", username)
+		req.Header.Set("Remote-Email", email)
+		return req
+	}
+
+	proxyConfig := func(cidr string) webserver.Config {
+		t.Helper()
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		webserverConfig := defaultTestConfig()
+		webserverConfig.RequireAuth = true
+		webserverConfig.ProxyAuth = webserver.ProxyAuth{
+			Enabled:        true,
+			TrustedProxies: []*net.IPNet{network},
+			UserHeader:     "Remote-User",
+			EmailHeader:    "Remote-Email",
+			NameHeader:     "Remote-Name",
+		}
+		return webserverConfig
+	}
+
+	t.Run("Headers from a trusted proxy create a new regular user", func(t *testing.T) {
+		db := infrastructure.Connect(":memory:", 250)
+		// Requests made through app.Test come from 0.0.0.0
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), proxyConfig("0.0.0.0/32"))
+
+		response, err := app.Test(proxyRequest("/", "John Doe", "john@example.com"))
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		user := fetchUserByEmail(t, db, "john@example.com")
+		if user.Username != "john_doe" {
+			t.Errorf("Expected username john_doe, got %s", user.Username)
+		}
+		if user.Role != model.RoleRegular {
+			t.Errorf("Expected regular role, got %d", user.Role)
+		}
+
+		response, err = app.Test(proxyRequest("/users", "John Doe", "john@example.com"))
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+
+	t.Run("Headers from a trusted proxy match an existing user by email", func(t *testing.T) {
+		db := infrastructure.Connect(":memory:", 250)
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), proxyConfig("0.0.0.0/32"))
+
+		response, err := app.Test(proxyRequest("/users", "admin", "admin@example.com"))
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+
+		var total int64
+		db.Model(&model.User{}).Count(&total)
+		if total != 1 {
+			t.Errorf("Expected 1 user, got %d", total)
+		}
+	})
+
+	t.Run("Headers from an untrusted source are ignored", func(t *testing.T) {
+		db := infrastructure.Connect(":memory:", 250)
+		app := bootstrapApp(db, &infrastructure.NoEmail{}, afero.NewMemMapFs(), proxyConfig("10.0.0.0/8"))
+
+		response, err := app.Test(proxyRequest("/", "admin", "admin@example.com"))
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnForbiddenAndShowLogin(response, t)
+	})
+}
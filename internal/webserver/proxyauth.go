@@ -0,0 +1,123 @@
+package webserver
+
+import (
+	"log"
+	"net"
+	"net/mail"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+// ProxyAuth holds the configuration for the reverse-proxy header authentication mode,
+// in which an authenticating proxy such as Authelia or oauth2-proxy identifies users
+// through request headers
+type ProxyAuth struct {
+	Enabled        bool
+	TrustedProxies []*net.IPNet
+	UserHeader     string
+	EmailHeader    string
+	NameHeader     string
+}
+
+// trustedProxies returns the trusted networks in the format expected by Fiber
+func (p ProxyAuth) trustedProxies() []string {
+	proxies := make([]string, len(p.TrustedProxies))
+	for i, network := range p.TrustedProxies {
+		proxies[i] = network.String()
+	}
+	return proxies
+}
+
+// ProxyAuthenticator resolves sessions from the headers injected by a trusted reverse proxy,
+// creating the matching user on first access
+type ProxyAuthenticator struct {
+	config          ProxyAuth
+	usersRepository *model.UserRepository
+	wordsPerMinute  float64
+}
+
+// NewProxyAuthenticator returns a ProxyAuthenticator, or nil if proxy authentication is disabled
+func NewProxyAuthenticator(cfg ProxyAuth, usersRepository *model.UserRepository, wordsPerMinute float64) *ProxyAuthenticator {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &ProxyAuthenticator{
+		config:          cfg,
+		usersRepository: usersRepository,
+		wordsPerMinute:  wordsPerMinute,
+	}
+}
+
+// Session returns the session of the user identified by the proxy headers. The second returned value
+// is false if the request does not come from a trusted proxy or does not carry the identity headers,
+// in which case the regular session cookie flow must be used.
+func (p *ProxyAuthenticator) Session(c fiber.Ctx) (model.Session, bool, error) {
+	if p == nil || !p.trusted(c.RequestCtx().RemoteIP()) {
+		return model.Session{}, false, nil
+	}
+
+	email := strings.TrimSpace(c.Get(p.config.EmailHeader))
+	if email == "" {
+		return model.Session{}, false, nil
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return model.Session{}, false, nil
+	}
+
+	user, err := p.usersRepository.FindByEmail(email)
+	if err != nil {
+		return model.Session{}, false, err
+	}
+	if user == nil {
+		if user, err = p.createUser(c, email); err != nil {
+			return model.Session{}, false, err
+		}
+	}
+
+	return model.Session{User: *user}, true, nil
+}
+
+func (p *ProxyAuthenticator) trusted(ip net.IP) bool {
+	for _, network := range p.config.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ProxyAuthenticator) createUser(c fiber.Ctx, email string) (*model.User, error) {
+	remoteUser := strings.TrimSpace(c.Get(p.config.UserHeader))
+	name := strings.TrimSpace(c.Get(p.config.NameHeader))
+	if name == "" {
+		name = remoteUser
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+	name = model.TruncateName(name)
+
+	username, err := p.usersRepository.AvailableUsername(remoteUser, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Uuid:              uuid.NewString(),
+		Name:              name,
+		Username:          username,
+		Email:             email,
+		Role:              model.RoleRegular,
+		WordsPerMinute:    p.wordsPerMinute,
+		PreferredEpubType: "epub",
+		DefaultAction:     "download",
+	}
+	if err := p.usersRepository.Create(user); err != nil {
+		return nil, err
+	}
+	log.Printf("Created user %s from reverse proxy headers\n", username)
+	return user, nil
+}
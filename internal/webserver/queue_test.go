@@ -0,0 +1,157 @@
+package webserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+func TestReadingQueue(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	request := func(t *testing.T, method string, data url.Values, cookie *http.Cookie, URL string, expectedStatus int) {
+		t.Helper()
+
+		var response *http.Response
+		switch method {
+		case http.MethodPost:
+			response, err = postRequest(data, cookie, app, URL, t)
+		case http.MethodPut:
+			response, err = putRequest(data, cookie, app, URL, t)
+		case http.MethodDelete:
+			response, err = deleteRequest(data, cookie, app, URL, t)
+		}
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	queue := func(t *testing.T, cookie *http.Cookie) []string {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, "/queue", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+
+		slugs := []string{}
+		doc.Find("#queue-documents li[data-slug]").Each(func(_ int, s *goquery.Selection) {
+			slugs = append(slugs, s.AttrOr("data-slug", ""))
+		})
+		return slugs
+	}
+
+	upNext := func(t *testing.T, cookie *http.Cookie, slug string, expectedStatus int) map[string]any {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, "/documents/"+slug+"/up-next", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+		next := map[string]any{}
+		if expectedStatus == http.StatusOK {
+			if err := json.NewDecoder(response.Body).Decode(&next); err != nil {
+				t.Fatalf("Unexpected error: %v", err.Error())
+			}
+		}
+		return next
+	}
+
+	t.Run("Documents can be queued and reordered", func(t *testing.T) {
+		request(t, http.MethodPost, nil, adminCookie, "/queue/"+testDocSlug, http.StatusNoContent)
+		request(t, http.MethodPost, nil, adminCookie, "/queue/john-doe-test-epub", http.StatusNoContent)
+		// Queueing a document twice keeps it where it was
+		request(t, http.MethodPost, nil, adminCookie, "/queue/"+testDocSlug, http.StatusNoContent)
+		request(t, http.MethodPost, nil, adminCookie, "/queue/john-doe-non-existing-document", http.StatusNotFound)
+
+		if got := queue(t, adminCookie); fmt.Sprint(got) != fmt.Sprint([]string{testDocSlug, "john-doe-test-epub"}) {
+			t.Errorf("Expected documents in the order they were queued, got %v", got)
+		}
+
+		request(t, http.MethodPut, url.Values{"position": {"0"}}, adminCookie, "/queue/john-doe-test-epub", http.StatusNoContent)
+		request(t, http.MethodPut, url.Values{"position": {"first"}}, adminCookie, "/queue/john-doe-test-epub", http.StatusBadRequest)
+		if got := queue(t, adminCookie); fmt.Sprint(got) != fmt.Sprint([]string{"john-doe-test-epub", testDocSlug}) {
+			t.Errorf("Expected moved document to go first, got %v", got)
+		}
+	})
+
+	t.Run("Queues are private", func(t *testing.T) {
+		if got := queue(t, regularCookie); len(got) != 0 {
+			t.Errorf("Expected regular user's queue to be empty, got %v", got)
+		}
+
+		response, err := getRequest(&http.Cookie{}, app, "/queue", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusForbidden, t)
+	})
+
+	t.Run("The first document in the queue is up next", func(t *testing.T) {
+		if next := upNext(t, adminCookie, testDocSlug, http.StatusOK); next["slug"] != "john-doe-test-epub" || next["reason"] != "queue" {
+			t.Errorf("Expected first queued document to be up next, got %v", next)
+		}
+		// The document being read is skipped
+		if next := upNext(t, adminCookie, "john-doe-test-epub", http.StatusOK); next["slug"] != testDocSlug {
+			t.Errorf("Expected second queued document to be up next, got %v", next)
+		}
+		upNext(t, adminCookie, "john-doe-non-existing-document", http.StatusNotFound)
+	})
+
+	t.Run("Completing a document takes it out of the queue", func(t *testing.T) {
+		request(t, http.MethodPost, nil, adminCookie, "/documents/john-doe-test-epub/complete", http.StatusNoContent)
+
+		if got := queue(t, adminCookie); fmt.Sprint(got) != fmt.Sprint([]string{testDocSlug}) {
+			t.Errorf("Expected completed document to be removed from the queue, got %v", got)
+		}
+
+		// Marking it as incomplete again does not put it back
+		request(t, http.MethodPost, nil, adminCookie, "/documents/john-doe-test-epub/complete", http.StatusNoContent)
+		if got := queue(t, adminCookie); len(got) != 1 {
+			t.Errorf("Expected queue to keep 1 document, got %v", got)
+		}
+	})
+
+	t.Run("Nothing is up next with an empty queue and no series to follow", func(t *testing.T) {
+		request(t, http.MethodDelete, nil, adminCookie, "/queue/"+testDocSlug, http.StatusNoContent)
+
+		if got := queue(t, adminCookie); len(got) != 0 {
+			t.Errorf("Expected queue to be empty, got %v", got)
+		}
+		upNext(t, adminCookie, testDocSlug, http.StatusNoContent)
+	})
+
+	t.Run("Removing a document from the library removes it from queues", func(t *testing.T) {
+		request(t, http.MethodPost, nil, regularCookie, "/queue/"+testDocSlug, http.StatusNoContent)
+		request(t, http.MethodDelete, nil, adminCookie, "/documents/"+testDocSlug, http.StatusOK)
+
+		var count int64
+		db.Table("queued_documents").Where("slug = ?", testDocSlug).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected document to be removed from queues, got %d", count)
+		}
+	})
+}
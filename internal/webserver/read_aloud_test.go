@@ -0,0 +1,43 @@
+package webserver_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+func TestReadAloud(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	appFs := loadFilesInMemoryFs([]string{
+		"testdata/library/metadata.epub",
+		"testdata/library/quijote.epub",
+	})
+	webserverConfig := webserver.Config{
+		SessionTimeout: 24 * time.Hour,
+		LibraryPath:    "testdata/library",
+		WordsPerMinute: 250,
+	}
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, appFs, webserverConfig)
+
+	req, _ := http.NewRequest(http.MethodGet, "/documents/"+testDocSlug+"/read", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `id="read-aloud-button"`) {
+		t.Error("Expected the reader to include the read aloud button")
+	}
+	if !strings.Contains(string(body), `id="language" value="`) {
+		t.Error("Expected the reader to include the document language, used to choose the voice")
+	}
+}
@@ -0,0 +1,134 @@
+package webserver_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/svera/coreander/v4/internal/webserver"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+type readerPreferencesResponse struct {
+	FontFamily string  `json:"font_family"`
+	FontSize   int     `json:"font_size"`
+	LineHeight float64 `json:"line_height"`
+	Margin     int     `json:"margin"`
+	Justify    bool    `json:"justify"`
+	Theme      string  `json:"theme"`
+	Flow       string  `json:"flow"`
+	Columns    int     `json:"columns"`
+	Updated    string  `json:"updated"`
+}
+
+func TestReaderPreferences(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	appFs := loadFilesInMemoryFs([]string{
+		"testdata/library/metadata.epub",
+		"testdata/library/quijote.epub",
+	})
+	webserverConfig := webserver.Config{
+		SessionTimeout: 24 * time.Hour,
+		LibraryPath:    "testdata/library",
+		WordsPerMinute: 250,
+	}
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, appFs, webserverConfig)
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getPreferences := func(t *testing.T) readerPreferencesResponse {
+		req, _ := http.NewRequest(http.MethodGet, "/reader-preferences", nil)
+		req.AddCookie(adminCookie)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET status %d, want 200", resp.StatusCode)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		var out readerPreferencesResponse
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatalf("json: %v body=%s", err, string(raw))
+		}
+		return out
+	}
+
+	putPreferences := func(t *testing.T, body string) int {
+		req, _ := http.NewRequest(http.MethodPut, "/reader-preferences", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(adminCookie)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Defaults are returned until preferences are saved", func(t *testing.T) {
+		out := getPreferences(t)
+		if out.Updated != "" || out.FontFamily != "serif" || out.FontSize != 100 || !out.Justify || out.Flow != "paginated" || out.Columns != 2 {
+			t.Errorf("Unexpected default preferences %+v", out)
+		}
+	})
+
+	t.Run("Saved preferences are returned", func(t *testing.T) {
+		body := `{"font_family":"publisher","font_size":120,"line_height":1.6,"margin":3,"justify":false,"theme":"dark","flow":"scrolled","columns":1}`
+		if status := putPreferences(t, body); status != http.StatusNoContent {
+			t.Fatalf("PUT status %d, want 204", status)
+		}
+
+		out := getPreferences(t)
+		expected := readerPreferencesResponse{FontFamily: "publisher", FontSize: 120, LineHeight: 1.6, Margin: 3, Justify: false, Theme: "dark", Flow: "scrolled", Columns: 1, Updated: out.Updated}
+		if out != expected {
+			t.Errorf("Expected %+v, got %+v", expected, out)
+		}
+		if out.Updated == "" {
+			t.Error("Expected the update time to be returned")
+		}
+	})
+
+	t.Run("Invalid preferences are rejected", func(t *testing.T) {
+		body := `{"font_family":"comic-sans","font_size":500,"line_height":1.4,"margin":7,"justify":true,"theme":"auto","flow":"paginated","columns":2}`
+		if status := putPreferences(t, body); status != http.StatusBadRequest {
+			t.Fatalf("PUT status %d, want 400", status)
+		}
+		if out := getPreferences(t); out.FontFamily != "publisher" {
+			t.Errorf("Expected previous preferences to be kept, got %+v", out)
+		}
+	})
+
+	t.Run("Reader includes the saved preferences", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/documents/"+testDocSlug+"/read", nil)
+		req.AddCookie(adminCookie)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(raw), `id="reader-preferences"`) || !strings.Contains(string(raw), `"font_family":"publisher"`) {
+			t.Errorf("Expected reader to include the saved preferences")
+		}
+	})
+
+	t.Run("Anonymous users cannot access preferences", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/reader-preferences", nil)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("GET status %d, want 403", resp.StatusCode)
+		}
+	})
+}
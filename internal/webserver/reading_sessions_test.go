@@ -0,0 +1,107 @@
+package webserver_test
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/metadata"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestReadingSessions(t *testing.T) {
+	// Reading speed can only be measured for documents with a word count
+	catalog := libraryCatalog()
+	quijote := catalog[filepath.Join(testLibraryDir, "quijote.epub")]
+	quijote.Words = 100000
+	catalog[filepath.Join(testLibraryDir, "quijote.epub")] = quijote
+	readers := map[string]metadata.Reader{".epub": catalogReader{byPath: catalog}, ".pdf": catalogReader{byPath: catalog}}
+
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig(), readers)
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	addReadingSession := func(t *testing.T, cookie *http.Cookie, startedAt time.Time, duration time.Duration, startPercentage, endPercentage int) *http.Response {
+		t.Helper()
+
+		body := fmt.Sprintf(
+			`{"started_at":"%s","ended_at":"%s","start_percentage":%d,"end_percentage":%d}`,
+			startedAt.Format(time.RFC3339), startedAt.Add(duration).Format(time.RFC3339), startPercentage, endPercentage,
+		)
+		req, _ := http.NewRequest(http.MethodPost, "/documents/"+testDocSlug+"/reading-sessions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		response, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return response
+	}
+
+	page := func(t *testing.T, URL string) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(adminCookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	t.Run("Only logged in users can report reading sessions", func(t *testing.T) {
+		mustReturnStatus(addReadingSession(t, nil, time.Now().Add(-time.Hour), 10*time.Minute, 0, 5), http.StatusForbidden, t)
+	})
+
+	t.Run("Invalid reading sessions are rejected", func(t *testing.T) {
+		mustReturnStatus(addReadingSession(t, adminCookie, time.Now(), -time.Minute, 0, 5), http.StatusBadRequest, t)
+		mustReturnStatus(addReadingSession(t, adminCookie, time.Now().Add(-24*time.Hour), model.ReadingSessionMaxDuration+time.Hour, 0, 5), http.StatusBadRequest, t)
+	})
+
+	t.Run("No reading speed is measured without enough reading time", func(t *testing.T) {
+		if page(t, "/users/admin").Find("#measured-words-per-minute").Length() != 0 {
+			t.Error("Expected no measured reading speed to be shown")
+		}
+	})
+
+	t.Run("Time spent reading is shown in the document page", func(t *testing.T) {
+		mustReturnStatus(addReadingSession(t, adminCookie, time.Now().Add(-2*time.Hour), 25*time.Minute, 0, 6), http.StatusNoContent, t)
+		mustReturnStatus(addReadingSession(t, adminCookie, time.Now().Add(-time.Hour), 15*time.Minute, 6, 12), http.StatusNoContent, t)
+
+		var count int64
+		db.Model(&model.ReadingSession{}).Count(&count)
+		if count != 2 {
+			t.Errorf("Expected 2 reading sessions to be stored, got %d", count)
+		}
+
+		if got := page(t, "/documents/"+testDocSlug).Find("#time-spent").Text(); got != "0h 40m" {
+			t.Errorf("Expected time spent to be '0h 40m', got '%s'", got)
+		}
+	})
+
+	t.Run("Measured reading speed is offered in the profile and used in the reader", func(t *testing.T) {
+		// 12000 words in 40 minutes
+		if got := page(t, "/users/admin").Find("#measured-words-per-minute button").AttrOr("data-words-per-minute", ""); got != "300" {
+			t.Errorf("Expected measured reading speed of 300 words per minute, got '%s'", got)
+		}
+
+		if got := page(t, "/documents/"+testDocSlug+"/read").Find("#words-per-minute").AttrOr("value", ""); got != "300" {
+			t.Errorf("Expected reader to use the measured reading speed, got '%s'", got)
+		}
+	})
+}
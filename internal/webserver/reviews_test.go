@@ -0,0 +1,144 @@
+package webserver_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestReviews(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	review := func(t *testing.T, cookie *http.Cookie, slug, rating, text string, expectedStatus int) {
+		t.Helper()
+
+		response, err := putRequest(url.Values{"rating": {rating}, "text": {text}}, cookie, app, "/documents/"+slug+"/review", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	page := func(t *testing.T, cookie *http.Cookie, URL string) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusOK, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	t.Run("Invalid reviews are rejected", func(t *testing.T) {
+		review(t, adminCookie, testDocSlug, "0", "", http.StatusBadRequest)
+		review(t, adminCookie, testDocSlug, "6", "", http.StatusBadRequest)
+		review(t, adminCookie, testDocSlug, "3", strings.Repeat("a", model.ReviewTextMaxLength+1), http.StatusBadRequest)
+		review(t, adminCookie, "john-doe-non-existing-document", "3", "", http.StatusNotFound)
+	})
+
+	t.Run("Reviews are shown to other users and averaged", func(t *testing.T) {
+		review(t, adminCookie, testDocSlug, "1", "Not for me", http.StatusNoContent)
+		// Saving again replaces the previous review
+		review(t, adminCookie, testDocSlug, "2", "Too long", http.StatusNoContent)
+		review(t, regularCookie, testDocSlug, "5", "A classic", http.StatusNoContent)
+
+		var count int64
+		db.Model(&model.Review{}).Count(&count)
+		if count != 2 {
+			t.Errorf("Expected 2 reviews to be stored, got %d", count)
+		}
+
+		doc := page(t, regularCookie, "/documents/"+testDocSlug)
+		if got := doc.Find("#reviews .review-text").Text(); got != "Too long" {
+			t.Errorf("Expected other users' reviews to be listed, got '%s'", got)
+		}
+		if got := doc.Find("#review-form textarea").Text(); got != "A classic" {
+			t.Errorf("Expected own review to fill the form, got '%s'", got)
+		}
+		if got := doc.Find(".average-rating").Text(); got != "3.5" {
+			t.Errorf("Expected average rating to be 3.5, got '%s'", got)
+		}
+	})
+
+	t.Run("Reviews of users with a private profile are hidden", func(t *testing.T) {
+		setPrivateProfile(t, db, "regular@example.com", true)
+		defer setPrivateProfile(t, db, "regular@example.com", false)
+
+		if got := page(t, &http.Cookie{}, "/documents/"+testDocSlug).Find("#reviews .review").Length(); got != 1 {
+			t.Errorf("Expected only the public review to be shown, got %d", got)
+		}
+		if got := page(t, adminCookie, "/documents/"+testDocSlug).Find("#reviews .review-text").Text(); got != "A classic" {
+			t.Errorf("Expected administrators to see private reviews, got '%s'", got)
+		}
+	})
+
+	t.Run("Search results can be sorted by rating", func(t *testing.T) {
+		review(t, adminCookie, "john-doe-test-epub", "5", "", http.StatusNoContent)
+
+		doc := page(t, &http.Cookie{}, "/documents?sort-by=rating-higher-first")
+		items := doc.Find("#list .list-group-item")
+		if got := items.First().Find("h2 a").AttrOr("href", ""); got != "/documents/john-doe-test-epub" {
+			t.Errorf("Expected best rated document first, got '%s'", got)
+		}
+		if got := items.Eq(1).Find("h2 a").AttrOr("href", ""); got != "/documents/"+testDocSlug {
+			t.Errorf("Expected second best rated document next, got '%s'", got)
+		}
+		if got := items.First().Find(".average-rating").Text(); got != "5.0" {
+			t.Errorf("Expected average rating to be shown in search results, got '%s'", got)
+		}
+
+		unsorted := page(t, &http.Cookie{}, "/documents").Find("#list .list-group-item").Length()
+		if items.Length() != unsorted {
+			t.Errorf("Expected unrated documents to follow rated ones, got %d results instead of %d", items.Length(), unsorted)
+		}
+		if got := items.Eq(2).Find(".average-rating").Length(); got != 0 {
+			t.Errorf("Expected unrated documents after rated ones")
+		}
+
+		doc = page(t, &http.Cookie{}, "/documents?sort-by=rating-higher-first&search=john+doe")
+		items = doc.Find("#list .list-group-item")
+		if got := items.First().Find("h2 a").AttrOr("href", ""); got != "/documents/john-doe-test-epub" {
+			t.Errorf("Expected rated documents to be filtered by the search, got '%s'", got)
+		}
+	})
+
+	t.Run("Users can list and delete their reviews", func(t *testing.T) {
+		if got := page(t, adminCookie, "/reviews").Find("#my-reviews li").Length(); got != 2 {
+			t.Errorf("Expected 2 reviews in the list, got %d", got)
+		}
+
+		response, err := deleteRequest(url.Values{}, adminCookie, app, "/documents/"+testDocSlug+"/review", t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, http.StatusNoContent, t)
+
+		if got := page(t, adminCookie, "/reviews").Find("#my-reviews li").Length(); got != 1 {
+			t.Errorf("Expected 1 review in the list, got %d", got)
+		}
+		if got := page(t, regularCookie, "/reviews").Find("#my-reviews li").Length(); got != 1 {
+			t.Errorf("Expected other users' reviews to be kept, got %d", got)
+		}
+	})
+}
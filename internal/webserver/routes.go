@@ -13,9 +13,11 @@ import (
 func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Sender, translator i18n.Translator, cfg Config, idx ProgressInfo, usersRepository *model.UserRepository) {
 	// Middlewares
 	var (
-		allowIfNotLoggedIn          = AllowIfNotLoggedIn(jwtSecret)
-		alwaysRequireAuthentication = AlwaysRequireAuthentication(jwtSecret, sender, translator, usersRepository, cfg.VersionChecker)
-		configurableAuthentication  = ConfigurableAuthentication(jwtSecret, sender, translator, cfg.RequireAuth, usersRepository, cfg.VersionChecker)
+		proxyAuthenticator          = NewProxyAuthenticator(cfg.ProxyAuth, usersRepository, cfg.WordsPerMinute)
+		allowIfNotLoggedIn          = AllowIfNotLoggedIn(jwtSecret, proxyAuthenticator)
+		alwaysRequireAuthentication = AlwaysRequireAuthentication(jwtSecret, sender, translator, usersRepository, cfg.VersionChecker, proxyAuthenticator)
+		configurableAuthentication  = ConfigurableAuthentication(jwtSecret, sender, translator, cfg.RequireAuth, usersRepository, cfg.VersionChecker, proxyAuthenticator)
+		auditRepository             = controllers.auditRepository
 	)
 
 	staticCacheControl := fmt.Sprintf("public, max-age=%d, immutable", cfg.ClientStaticCacheTTL)
@@ -48,6 +50,8 @@ func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Se
 		FS: imagesFS,
 	}))
 
+	app.Get("/service-worker.js", serviceWorker)
+
 	app.Use(func(c fiber.Ctx) error {
 		c.Locals("Version", c.App().Config().AppName)
 		c.Locals("SupportedLanguages", supportedLanguages)
@@ -67,12 +71,17 @@ func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Se
 	// Set email sending configuration (must be early so it's available in all routes)
 	app.Use(SetEmailSendingConfigured(sender))
 
+	// The offline page is cached by the service worker, so it must be available without logging in
+	app.Get("/offline", offline)
+
 	app.Get("/sessions/new", allowIfNotLoggedIn, controllers.Auth.Login)
-	app.Post("/sessions", allowIfNotLoggedIn, controllers.Auth.SignIn)
+	app.Post("/sessions", allowIfNotLoggedIn, AuditLogin(auditRepository, "password"), controllers.Auth.SignIn)
 	app.Get("/recover", allowIfNotLoggedIn, controllers.Auth.Recover)
 	app.Post("/recover", allowIfNotLoggedIn, controllers.Auth.Request)
 	app.Get("/reset-password", allowIfNotLoggedIn, controllers.Auth.EditPassword)
 	app.Post("/reset-password", allowIfNotLoggedIn, controllers.Auth.UpdatePassword)
+	app.Post("/sessions/passkey/options", allowIfNotLoggedIn, controllers.Passkeys.LoginOptions)
+	app.Post("/sessions/passkey", allowIfNotLoggedIn, AuditLogin(auditRepository, "passkey"), controllers.Passkeys.Login)
 	app.Delete("/sessions", alwaysRequireAuthentication, controllers.Auth.SignOut)
 
 	// Public routes for invitation acceptance (must be before usersGroup)
@@ -83,18 +92,66 @@ func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Se
 
 	usersGroup.Get("/", RequireAdmin, controllers.Users.List)
 	usersGroup.Get("/new", RequireAdmin, controllers.Users.New)
-	usersGroup.Post("/", RequireAdmin, controllers.Users.Create)
-	usersGroup.Post("/invite", RequireAdmin, controllers.Users.SendInvite)
+	usersGroup.Post("/", RequireAdmin, Audit(auditRepository, model.AuditUserCreate, auditUserCreate), controllers.Users.Create)
+	usersGroup.Post("/invite", RequireAdmin, Audit(auditRepository, model.AuditUserInvite, auditFormValue("email")), controllers.Users.SendInvite)
 	usersGroup.Get("/share-recipients", controllers.Users.ShareRecipients)
 	app.Get("/completed", alwaysRequireAuthentication, controllers.Completed.Completed)
+	app.Get("/stats", alwaysRequireAuthentication, controllers.Stats.Show)
+	app.Get("/history", alwaysRequireAuthentication, controllers.History.List)
+	app.Put("/history/:id", alwaysRequireAuthentication, controllers.History.Rate)
+	app.Get("/reviews", alwaysRequireAuthentication, controllers.Reviews.List)
+	app.Get("/shelves", alwaysRequireAuthentication, controllers.Shelves.List)
+	app.Post("/shelves", alwaysRequireAuthentication, controllers.Shelves.Create)
+	app.Get("/shelves/options", alwaysRequireAuthentication, controllers.Shelves.Options)
+	app.Put("/shelves/:id", alwaysRequireAuthentication, controllers.Shelves.Update)
+	app.Delete("/shelves/:id", alwaysRequireAuthentication, controllers.Shelves.Delete)
+	app.Post("/shelves/:id/documents/:slug", alwaysRequireAuthentication, controllers.Shelves.AddDocument)
+	app.Put("/shelves/:id/documents/:slug", alwaysRequireAuthentication, controllers.Shelves.MoveDocument)
+	app.Delete("/shelves/:id/documents/:slug", alwaysRequireAuthentication, controllers.Shelves.RemoveDocument)
+	app.Post("/shelves/:id/members", alwaysRequireAuthentication, controllers.Shelves.Share)
+	app.Delete("/shelves/:id/members/:userID", alwaysRequireAuthentication, controllers.Shelves.Unshare)
+	app.Get("/queue", alwaysRequireAuthentication, controllers.Queue.List)
+	app.Get("/feed", alwaysRequireAuthentication, controllers.Activity.Feed)
+	app.Delete("/comments/:id", alwaysRequireAuthentication, controllers.Comments.Delete)
+	app.Post("/comments/:id/hidden", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditCommentHide, auditParam("id")), controllers.Comments.Hide)
+	app.Delete("/comments/:id/hidden", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditCommentUnhide, auditParam("id")), controllers.Comments.Unhide)
+	app.Get("/notifications", alwaysRequireAuthentication, controllers.Notifications.List)
+	app.Get("/notifications/unread", alwaysRequireAuthentication, controllers.Notifications.Unread)
+	app.Post("/notifications/read", alwaysRequireAuthentication, controllers.Notifications.ReadAll)
+	app.Put("/notifications/preferences", alwaysRequireAuthentication, controllers.Notifications.UpdatePreferences)
+	app.Get("/notifications/:id", alwaysRequireAuthentication, controllers.Notifications.Open)
+	app.Post("/notifications/:id/read", alwaysRequireAuthentication, controllers.Notifications.Read)
+	app.Post("/queue/:slug", alwaysRequireAuthentication, controllers.Queue.Add)
+	app.Put("/queue/:slug", alwaysRequireAuthentication, controllers.Queue.Move)
+	app.Delete("/queue/:slug", alwaysRequireAuthentication, controllers.Queue.Remove)
+	usersGroup.Get("/:username/passkeys", controllers.Passkeys.List)
+	usersGroup.Post("/:username/passkeys/options", controllers.Passkeys.RegistrationOptions)
+	usersGroup.Post("/:username/passkeys", controllers.Passkeys.Register)
+	usersGroup.Delete("/:username/passkeys/:id", controllers.Passkeys.Delete)
+	usersGroup.Get("/:username/profile", controllers.Activity.Profile)
+	usersGroup.Post("/:username/followers", controllers.Activity.Follow)
+	usersGroup.Delete("/:username/followers", controllers.Activity.Unfollow)
+	usersGroup.Get("/:username/goals", controllers.Goals.List)
+	usersGroup.Post("/:username/goals", controllers.Goals.Save)
+	usersGroup.Delete("/:username/goals/:id", controllers.Goals.Delete)
+	usersGroup.Get("/:username/devices", controllers.Devices.List)
+	usersGroup.Post("/:username/devices", controllers.Devices.Save)
+	usersGroup.Delete("/:username/devices/:id", controllers.Devices.Delete)
 	usersGroup.Get("/:username", controllers.Users.Edit)
-	usersGroup.Put("/:username", controllers.Users.Update)
-	usersGroup.Delete("/:username", controllers.Users.Delete)
+	usersGroup.Put("/:username", Audit(auditRepository, model.AuditUserUpdate, auditUserUpdate), controllers.Users.Update)
+	usersGroup.Delete("/:username", Audit(auditRepository, model.AuditUserDelete, auditParam("username")), controllers.Users.Delete)
 
 	docsGroup := app.Group("/documents")
 	app.Get("/upload", alwaysRequireAuthentication, RequireAdmin, controllers.Documents.UploadForm)
-	docsGroup.Post("/", alwaysRequireAuthentication, RequireAdmin, controllers.Documents.Upload)
-	docsGroup.Delete("/:slug", alwaysRequireAuthentication, RequireAdmin, controllers.Documents.Delete)
+	docsGroup.Post("/", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditDocumentUpload, auditUploadedFile), controllers.Documents.Upload)
+	docsGroup.Delete("/:slug", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditDocumentDelete, auditParam("slug")), controllers.Documents.Delete)
+
+	app.Get("/dashboard", alwaysRequireAuthentication, RequireAdmin, controllers.Dashboard.Show)
+	app.Get("/audit", alwaysRequireAuthentication, RequireAdmin, controllers.Audit.List)
+	app.Get("/emails", alwaysRequireAuthentication, RequireAdmin, controllers.Emails.List)
+	app.Get("/emails/preview", alwaysRequireAuthentication, RequireAdmin, controllers.Emails.Preview)
+	app.Post("/emails/test", alwaysRequireAuthentication, RequireAdmin, controllers.Emails.SendTest)
+	app.Post("/emails/:id/retry", alwaysRequireAuthentication, RequireAdmin, controllers.Emails.Retry)
 
 	// Authentication requirement is configurable for all routes below this middleware
 	app.Use(configurableAuthentication)
@@ -107,14 +164,26 @@ func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Se
 	highlightsGroup.Post("/:slug", controllers.Highlights.Create)
 	highlightsGroup.Delete("/:slug", controllers.Highlights.Delete)
 
+	app.Get("/reader-preferences", alwaysRequireAuthentication, controllers.Documents.ReaderPreferences)
+	app.Put("/reader-preferences", alwaysRequireAuthentication, controllers.Documents.UpdateReaderPreferences)
+
 	docsGroup.Get("/:slug/cover", controllers.Documents.Cover)
 	docsGroup.Get("/:slug/read", controllers.Documents.Reader)
 	docsGroup.Get("/:slug/position", alwaysRequireAuthentication, controllers.Documents.GetPosition)
 	docsGroup.Put("/:slug/position", alwaysRequireAuthentication, controllers.Documents.UpdatePosition)
+	docsGroup.Post("/:slug/reading-sessions", alwaysRequireAuthentication, controllers.Documents.AddReadingSession)
 	docsGroup.Post("/:slug/complete", alwaysRequireAuthentication, controllers.Completed.ToggleComplete)
 	docsGroup.Put("/:slug/complete", alwaysRequireAuthentication, controllers.Completed.ToggleComplete)
+	docsGroup.Post("/:slug/reread", alwaysRequireAuthentication, controllers.Completed.Reread)
+	docsGroup.Put("/:slug/review", alwaysRequireAuthentication, controllers.Reviews.Save)
+	docsGroup.Delete("/:slug/review", alwaysRequireAuthentication, controllers.Reviews.Delete)
+	docsGroup.Get("/:slug/comments", controllers.Comments.List)
+	docsGroup.Post("/:slug/comments", alwaysRequireAuthentication, controllers.Comments.Create)
+	docsGroup.Get("/:slug/shelves", alwaysRequireAuthentication, controllers.Shelves.Choices)
+	docsGroup.Get("/:slug/up-next", alwaysRequireAuthentication, controllers.Queue.UpNext)
 	docsGroup.Get("/:slug/download", controllers.Documents.Download)
 	docsGroup.Post("/:slug/send", alwaysRequireAuthentication, controllers.Documents.Send)
+	docsGroup.Get("/:slug/devices", alwaysRequireAuthentication, controllers.Devices.Choices)
 	docsGroup.Post("/:slug/share", alwaysRequireAuthentication, controllers.Documents.Share)
 	docsGroup.Get("/:slug", controllers.Documents.Detail)
 	docsGroup.Get("/", controllers.Documents.Search)
@@ -124,10 +193,17 @@ func routes(app *fiber.App, controllers Controllers, jwtSecret []byte, sender Se
 	app.Get("/authors/:slug.:extension<regex(jpg)$/i>", controllers.Authors.Image)
 	app.Get("/authors/:slug", controllers.Authors.Documents)
 	app.Get("/authors/:slug/summary", controllers.Authors.Summary)
-	app.Put("/authors/:slug", controllers.Authors.Update, alwaysRequireAuthentication, RequireAdmin)
-	app.Post("/authors/:slug/image", alwaysRequireAuthentication, RequireAdmin, controllers.Authors.UploadImage)
+	app.Put("/authors/:slug", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditAuthorUpdate, auditParam("slug")), controllers.Authors.Update)
+	app.Post("/authors/:slug/image", alwaysRequireAuthentication, RequireAdmin, Audit(auditRepository, model.AuditAuthorImage, auditParam("slug")), controllers.Authors.UploadImage)
 
 	app.Get("/series/:slug", controllers.Series.Documents)
+	app.Post("/series/:slug/followers", alwaysRequireAuthentication, controllers.Series.Follow)
+	app.Delete("/series/:slug/followers", alwaysRequireAuthentication, controllers.Series.Unfollow)
+
+	// Public shelves can be seen by anyone who can access the library
+	app.Get("/shelves/:id", controllers.Shelves.Show)
+
+	app.Get("/year-in-review/:username/:year", controllers.Stats.YearInReview)
 
 	app.Get("/resume-reading", alwaysRequireAuthentication, controllers.Home.ResumeReading)
 	app.Get("/", controllers.Home.Index)
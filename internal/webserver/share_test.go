@@ -110,6 +110,48 @@ func TestShareCommentIsTruncated(t *testing.T) {
 	}
 }
 
+func TestShareEmailIsWrittenInRecipientLanguage(t *testing.T) {
+	webserverConfig := webserver.Config{
+		ShareCommentMaxSize: 280,
+		ShareMaxRecipients:  10,
+		LibraryPath:         "testdata/library",
+		WordsPerMinute:      250,
+		SessionTimeout:      24 * time.Hour,
+		RecoveryTimeout:     2 * time.Hour,
+		MinPasswordLength:   5,
+	}
+	db, app, adminCookie, smtpMock := setupShareTestWithSMTP(t, webserverConfig)
+	createUser(t, app, adminCookie, userFixture{
+		name:     "Regular user",
+		username: "regular",
+		email:    "regular@example.com",
+		password: "regular",
+	})
+	db.Model(&model.User{}).Where("email = ?", "regular@example.com").Update("language", "es")
+
+	shareData := url.Values{
+		"recipients": {"regular"},
+		"comment":    {"Worth a read"},
+	}
+	smtpMock.Wg.Add(1)
+	response, err := postRequest(shareData, adminCookie, app, "/documents/john-doe-test-epub/share", t)
+	if response == nil || err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	mustReturnStatus(response, http.StatusOK, t)
+	smtpMock.Wg.Wait()
+
+	if !strings.Contains(smtpMock.LastBody, `lang="es"`) || !strings.Contains(smtpMock.LastBody, "Ver tus destacados") {
+		t.Errorf("Expected email to be written in spanish, got '%s'", smtpMock.LastBody)
+	}
+	if !strings.Contains(smtpMock.LastText, "compartió") || !strings.Contains(smtpMock.LastText, "> Worth a read") {
+		t.Errorf("Expected a plain text version of the email, got '%s'", smtpMock.LastText)
+	}
+	if (len(smtpMock.LastInline) > 0) != strings.Contains(smtpMock.LastBody, "cid:cover") {
+		t.Error("Expected the cover to be embedded only when it is shown in the email")
+	}
+}
+
 func TestShareFailsWhenSenderIsPrivate(t *testing.T) {
 	webserverConfig := webserver.Config{
 		ShareMaxRecipients: 10,
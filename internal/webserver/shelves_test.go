@@ -0,0 +1,184 @@
+package webserver_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/model"
+)
+
+func TestShelves(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	request := func(t *testing.T, method string, data url.Values, cookie *http.Cookie, URL string, expectedStatus int) {
+		t.Helper()
+
+		var response *http.Response
+		switch method {
+		case http.MethodPost:
+			response, err = postRequest(data, cookie, app, URL, t)
+		case http.MethodPut:
+			response, err = putRequest(data, cookie, app, URL, t)
+		case http.MethodDelete:
+			response, err = deleteRequest(data, cookie, app, URL, t)
+		}
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+	}
+
+	page := func(t *testing.T, cookie *http.Cookie, URL string, expectedStatus int) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	shelfDocuments := func(t *testing.T, cookie *http.Cookie, URL string) []string {
+		t.Helper()
+
+		slugs := []string{}
+		page(t, cookie, URL, http.StatusOK).Find("#shelf-documents li[id^='shelf-document-']").Each(func(_ int, s *goquery.Selection) {
+			slugs = append(slugs, s.AttrOr("id", "")[len("shelf-document-"):])
+		})
+		return slugs
+	}
+
+	var shelf model.Shelf
+	var shelfURL string
+
+	t.Run("Shelves can be created", func(t *testing.T) {
+		request(t, http.MethodPost, url.Values{"name": {"Book club 2026"}}, adminCookie, "/shelves", http.StatusOK)
+		request(t, http.MethodPost, url.Values{"name": {"book club 2026"}}, adminCookie, "/shelves", http.StatusBadRequest)
+		request(t, http.MethodPost, url.Values{"name": {" "}}, adminCookie, "/shelves", http.StatusBadRequest)
+
+		db.Where("name = ?", "Book club 2026").First(&shelf)
+		if shelf.ID == 0 {
+			t.Fatal("Expected shelf to be created")
+		}
+		shelfURL = fmt.Sprintf("/shelves/%d", shelf.ID)
+
+		if got := page(t, adminCookie, "/shelves", http.StatusOK).Find("#own-shelves li").Length(); got != 1 {
+			t.Errorf("Expected 1 shelf in the list, got %d", got)
+		}
+	})
+
+	t.Run("Documents can be added to shelves and reordered", func(t *testing.T) {
+		request(t, http.MethodPost, nil, adminCookie, shelfURL+"/documents/"+testDocSlug, http.StatusNoContent)
+		request(t, http.MethodPost, nil, adminCookie, shelfURL+"/documents/john-doe-test-epub", http.StatusNoContent)
+		// Adding a document twice keeps it where it was
+		request(t, http.MethodPost, nil, adminCookie, shelfURL+"/documents/"+testDocSlug, http.StatusNoContent)
+		request(t, http.MethodPost, nil, adminCookie, shelfURL+"/documents/john-doe-non-existing-document", http.StatusNotFound)
+
+		if got := shelfDocuments(t, adminCookie, shelfURL); fmt.Sprint(got) != fmt.Sprint([]string{testDocSlug, "john-doe-test-epub"}) {
+			t.Errorf("Expected documents in the order they were added, got %v", got)
+		}
+
+		request(t, http.MethodPut, url.Values{"position": {"0"}}, adminCookie, shelfURL+"/documents/john-doe-test-epub", http.StatusNoContent)
+		if got := shelfDocuments(t, adminCookie, shelfURL); fmt.Sprint(got) != fmt.Sprint([]string{"john-doe-test-epub", testDocSlug}) {
+			t.Errorf("Expected moved document to go first, got %v", got)
+		}
+
+		if got := page(t, adminCookie, "/documents/"+testDocSlug+"/shelves", http.StatusOK).Find("#shelf-choices button[hx-delete]").Length(); got != 1 {
+			t.Errorf("Expected document to be shown as placed in the shelf, got %d", got)
+		}
+	})
+
+	t.Run("Search results can be filtered by shelf", func(t *testing.T) {
+		if got := page(t, adminCookie, "/documents?shelf="+fmt.Sprint(shelf.ID), http.StatusOK).Find("#list .list-group-item").Length(); got != 2 {
+			t.Errorf("Expected 2 results, got %d", got)
+		}
+
+		request(t, http.MethodDelete, nil, adminCookie, shelfURL+"/documents/john-doe-test-epub", http.StatusNoContent)
+		if got := page(t, adminCookie, "/documents?shelf="+fmt.Sprint(shelf.ID), http.StatusOK).Find("#list .list-group-item").Length(); got != 1 {
+			t.Errorf("Expected 1 result, got %d", got)
+		}
+	})
+
+	t.Run("Private shelves can only be seen and modified by their owners", func(t *testing.T) {
+		page(t, regularCookie, shelfURL, http.StatusNotFound)
+		page(t, regularCookie, "/documents?shelf="+fmt.Sprint(shelf.ID), http.StatusNotFound)
+		request(t, http.MethodPost, nil, regularCookie, shelfURL+"/documents/john-doe-test-epub", http.StatusNotFound)
+	})
+
+	t.Run("Shelves can be shared with other users", func(t *testing.T) {
+		request(t, http.MethodPost, url.Values{"username": {"regular"}}, adminCookie, shelfURL+"/members", http.StatusNoContent)
+		request(t, http.MethodPost, url.Values{"username": {"nobody"}}, adminCookie, shelfURL+"/members", http.StatusBadRequest)
+
+		if got := shelfDocuments(t, regularCookie, shelfURL); len(got) != 1 {
+			t.Errorf("Expected shared shelf to be visible, got %v", got)
+		}
+		if got := page(t, regularCookie, "/shelves", http.StatusOK).Find("#shared-shelves li").Length(); got != 1 {
+			t.Errorf("Expected 1 shelf shared with the user, got %d", got)
+		}
+		// Members can see shelves, but not modify them
+		request(t, http.MethodPost, nil, regularCookie, shelfURL+"/documents/john-doe-test-epub", http.StatusForbidden)
+
+		var regular model.User
+		db.Where("username = ?", "regular").First(&regular)
+		request(t, http.MethodDelete, nil, adminCookie, fmt.Sprintf("%s/members/%d", shelfURL, regular.ID), http.StatusNoContent)
+		page(t, regularCookie, shelfURL, http.StatusNotFound)
+	})
+
+	t.Run("Public shelves can be seen by anyone", func(t *testing.T) {
+		request(t, http.MethodPut, url.Values{"name": {"Classics"}, "public": {"on"}}, adminCookie, shelfURL, http.StatusNoContent)
+
+		doc := page(t, &http.Cookie{}, shelfURL, http.StatusOK)
+		if got := doc.Find("h1").Text(); got == "" || doc.Find("#shelf-settings").Length() != 0 {
+			t.Error("Expected public shelf to be shown without settings")
+		}
+		if got := page(t, regularCookie, "/shelves", http.StatusOK).Find("#public-shelves li").Length(); got != 1 {
+			t.Errorf("Expected 1 public shelf, got %d", got)
+		}
+	})
+
+	t.Run("Public shelves of users with a private profile are hidden", func(t *testing.T) {
+		setPrivateProfile(t, db, "admin@example.com", true)
+		defer setPrivateProfile(t, db, "admin@example.com", false)
+
+		page(t, &http.Cookie{}, shelfURL, http.StatusNotFound)
+		page(t, regularCookie, shelfURL, http.StatusNotFound)
+		if got := page(t, regularCookie, "/shelves", http.StatusOK).Find("#public-shelves li").Length(); got != 0 {
+			t.Errorf("Expected no public shelves, got %d", got)
+		}
+		shelfDocuments(t, adminCookie, shelfURL)
+	})
+
+	t.Run("Deleting a shelf keeps its documents", func(t *testing.T) {
+		request(t, http.MethodDelete, nil, regularCookie, shelfURL, http.StatusForbidden)
+		request(t, http.MethodDelete, nil, adminCookie, shelfURL, http.StatusNoContent)
+
+		page(t, adminCookie, shelfURL, http.StatusNotFound)
+		page(t, adminCookie, "/documents/"+testDocSlug, http.StatusOK)
+
+		var count int64
+		db.Model(&model.ShelfDocument{}).Count(&count)
+		if count != 0 {
+			t.Errorf("Expected shelf documents to be removed along the shelf, got %d", count)
+		}
+	})
+}
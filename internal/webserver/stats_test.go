@@ -0,0 +1,102 @@
+package webserver_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+)
+
+func TestReadingStats(t *testing.T) {
+	db := infrastructure.Connect(":memory:", 250)
+	app := bootstrapApp(db, &infrastructure.NoEmail{}, loadDirInMemoryFs(testLibraryDir), defaultTestConfig())
+
+	adminCookie, err := login(app, "admin@example.com", "admin", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	addRegularUser(t, app, adminCookie)
+	regularCookie, err := login(app, "regular@example.com", "regular", t)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	response, err := postRequest(nil, regularCookie, app, "/documents/"+testDocSlug+"/complete", t)
+	if response == nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	req, _ := http.NewRequest(http.MethodPut, "/documents/"+testDocSlug+"/complete", strings.NewReader(`{"completed_on":"2024-03-15"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(regularCookie)
+	if response, err = app.Test(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	mustReturnStatus(response, http.StatusNoContent, t)
+
+	statsPage := func(t *testing.T, cookie *http.Cookie, URL string, expectedStatus int) *goquery.Document {
+		t.Helper()
+
+		response, err := getRequest(cookie, app, URL, t)
+		if response == nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		mustReturnStatus(response, expectedStatus, t)
+		doc, err := goquery.NewDocumentFromReader(response.Body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err.Error())
+		}
+		return doc
+	}
+
+	t.Run("Users see the statistics of the documents they completed in a year", func(t *testing.T) {
+		doc := statsPage(t, regularCookie, "/stats?year=2024", http.StatusOK)
+
+		if got := doc.Find("#stats-documents").Text(); got != "1" {
+			t.Errorf("Expected 1 completed document, got '%s'", got)
+		}
+		if got := doc.Find("#stats-authors li").Text(); !strings.Contains(got, "Cervantes") {
+			t.Errorf("Expected Cervantes to be the top author, got '%s'", got)
+		}
+		if got := doc.Find("#stats-periods li").Length(); got != 12 {
+			t.Errorf("Expected 12 months, got %d", got)
+		}
+		if got := doc.Find("#stats-longest-streak").Text(); got != "1" {
+			t.Errorf("Expected longest streak to be 1 month, got '%s'", got)
+		}
+		if doc.Find("#year option[value='2024']").AttrOr("selected", "none") == "none" {
+			t.Error("Expected 2024 to be selected")
+		}
+	})
+
+	t.Run("Statistics only include the requested year", func(t *testing.T) {
+		doc := statsPage(t, regularCookie, "/stats?year=2023", http.StatusOK)
+		if doc.Find("#stats-documents").Length() != 0 {
+			t.Error("Expected no statistics for a year without completions")
+		}
+
+		doc = statsPage(t, adminCookie, "/stats?year=2024", http.StatusOK)
+		if doc.Find("#stats-documents").Length() != 0 {
+			t.Error("Expected no statistics for a user without completions")
+		}
+
+		statsPage(t, regularCookie, "/stats?year=last", http.StatusBadRequest)
+	})
+
+	t.Run("Year in review is public unless the profile is private", func(t *testing.T) {
+		doc := statsPage(t, &http.Cookie{}, "/year-in-review/regular/2024", http.StatusOK)
+		if got := doc.Find("#stats-completed a").AttrOr("href", ""); got != "/documents/"+testDocSlug {
+			t.Errorf("Expected completed document to be shown, got '%s'", got)
+		}
+
+		statsPage(t, &http.Cookie{}, "/year-in-review/nonexistent/2024", http.StatusNotFound)
+
+		setPrivateProfile(t, db, "regular@example.com", true)
+		defer setPrivateProfile(t, db, "regular@example.com", false)
+
+		statsPage(t, &http.Cookie{}, "/year-in-review/regular/2024", http.StatusNotFound)
+		statsPage(t, regularCookie, "/year-in-review/regular/2024", http.StatusOK)
+		statsPage(t, adminCookie, "/year-in-review/regular/2024", http.StatusOK)
+	})
+}
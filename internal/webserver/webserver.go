@@ -16,6 +16,7 @@ import (
 	"github.com/svera/coreander/v4/internal/index"
 	"github.com/svera/coreander/v4/internal/versioncheck"
 	"github.com/svera/coreander/v4/internal/webserver/infrastructure"
+	"github.com/svera/coreander/v4/internal/webserver/mailer"
 	"github.com/svera/coreander/v4/internal/webserver/model"
 	"golang.org/x/exp/slices"
 )
@@ -57,12 +58,17 @@ type Config struct {
 	IllustratedMinAmount       int
 	InviteEmailListMaxLength   int
 	InviteMaxRecipients        int
+	ConversionCacheMaxSize     int
+	ConversionWorkers          int
+	KepubPregenerate           bool
+	ProxyAuth                  ProxyAuth
+	LDAP                       *infrastructure.LDAP
 	VersionChecker             *versioncheck.Checker
 }
 
 type Sender interface {
-	Send(address, subject, body string) error
-	SendBCC(addresses []string, subject, body string) error
+	Send(address string, message mailer.Message) error
+	SendBCC(addresses []string, message mailer.Message) error
 	SendDocument(address, subject string, file []byte, fileName string) error
 	From() string
 }
@@ -127,6 +133,12 @@ func New(cfg Config, controllers Controllers, sender Sender, idx ProgressInfo, u
 		BodyLimit:                    cfg.UploadDocumentMaxSize * 1024 * 1024,
 		DisablePreParseMultipartForm: true,
 		StreamRequestBody:            true,
+
+		// Behind a trusted reverse proxy, c.IP() returns the client address forwarded by it
+		TrustProxy:         len(cfg.ProxyAuth.TrustedProxies) > 0,
+		TrustProxyConfig:   fiber.TrustProxyConfig{Proxies: cfg.ProxyAuth.trustedProxies()},
+		ProxyHeader:        fiber.HeaderXForwardedFor,
+		EnableIPValidation: true,
 	})
 
 	app.Use(
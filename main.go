@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"runtime"
 	"strings"
@@ -98,13 +99,10 @@ func main() {
 	go startIndex(idx, input.BatchSize, input.LibPath, resolvedIndexWorkers)
 
 	sender = &infrastructure.NoEmail{}
-	if input.SmtpServer != "" && input.SmtpUser != "" && input.SmtpPassword != "" {
-		sender = &infrastructure.SMTP{
-			Server:   input.SmtpServer,
-			Port:     input.SmtpPort,
-			User:     input.SmtpUser,
-			Password: input.SmtpPassword,
-		}
+	if transport := mailTransport(); transport != nil {
+		mailQueue := webserver.NewMailQueue(&model.EmailRepository{DB: db}, transport, input.MailWorkers, input.MailMaxAttempts)
+		mailQueue.Start()
+		sender = mailQueue
 	}
 
 	webserverConfig := webserver.Config{
@@ -130,6 +128,9 @@ func main() {
 		IllustratedMinAmount:       input.IllustratedMinAmount,
 		InviteEmailListMaxLength:   input.InviteEmailListMaxLength,
 		InviteMaxRecipients:        input.InviteMaxRecipients,
+		ConversionCacheMaxSize:     input.ConversionCacheMaxSize,
+		ConversionWorkers:          input.ConversionWorkers,
+		KepubPregenerate:           input.KepubPregenerate,
 	}
 
 	webserverConfig.SessionTimeout, err = time.ParseDuration(fmt.Sprintf("%fh", input.SessionTimeout))
@@ -147,6 +148,32 @@ func main() {
 		log.Fatal(fmt.Errorf("wrong value for invitation timeout"))
 	}
 
+	if input.ProxyAuth {
+		webserverConfig.ProxyAuth, err = proxyAuthConfig(input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Reverse proxy authentication enabled, trusting %s\n", strings.Join(input.ProxyAuthTrustedCIDRs, ", "))
+	}
+
+	if input.LDAPURL != "" {
+		webserverConfig.LDAP = &infrastructure.LDAP{
+			URL:               input.LDAPURL,
+			StartTLS:          input.LDAPStartTLS,
+			BindDN:            input.LDAPBindDN,
+			BindPassword:      input.LDAPBindPassword,
+			BaseDN:            input.LDAPBaseDN,
+			UserFilter:        input.LDAPUserFilter,
+			UsernameAttribute: input.LDAPUsernameAttribute,
+			NameAttribute:     input.LDAPNameAttribute,
+			EmailAttribute:    input.LDAPEmailAttribute,
+			GroupAttribute:    input.LDAPGroupAttribute,
+			AdminGroup:        input.LDAPAdminGroup,
+			UserGroup:         input.LDAPUserGroup,
+		}
+		log.Printf("LDAP authentication enabled against %s\n", input.LDAPURL)
+	}
+
 	if webserverConfig.CacheDir == "" {
 		webserverConfig.CacheDir = homeDir + "/.coreander/cache"
 		if _, err := os.Stat(webserverConfig.CacheDir); os.IsNotExist(err) {
@@ -166,6 +193,8 @@ func main() {
 	controllers := webserver.SetupControllers(webserverConfig, db, metadataReaders, idx, sender, appFs, dataSource)
 	usersRepository := &model.UserRepository{DB: db}
 	app := webserver.New(webserverConfig, controllers, sender, idx, usersRepository)
+	webserver.NewGoalReminder(&model.GoalRepository{DB: db, Idx: idx}, sender, app).Start()
+	webserver.NewNotifier(&model.NotificationRepository{DB: db}, sender, versionChecker, webserverConfig.FQDN, app).Start()
 	if strings.ToLower(input.FQDN) == "localhost" {
 		fmt.Printf("Warning: using \"localhost\" as FQDN. Links using this FQDN won't be accessible outside this system.\n")
 	}
@@ -173,6 +202,26 @@ func main() {
 	log.Fatal(app.Listen(fmt.Sprintf(":%d", input.Port), fiber.ListenConfig{DisableStartupMessage: true}))
 }
 
+func proxyAuthConfig(input CLIInput) (webserver.ProxyAuth, error) {
+	proxyAuth := webserver.ProxyAuth{
+		Enabled:     true,
+		UserHeader:  input.ProxyAuthUserHeader,
+		EmailHeader: input.ProxyAuthEmailHeader,
+		NameHeader:  input.ProxyAuthNameHeader,
+	}
+	if len(input.ProxyAuthTrustedCIDRs) == 0 {
+		return proxyAuth, fmt.Errorf("proxy authentication requires at least one trusted proxy CIDR")
+	}
+	for _, cidr := range input.ProxyAuthTrustedCIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return proxyAuth, fmt.Errorf("wrong value for trusted proxy CIDR %q", cidr)
+		}
+		proxyAuth.TrustedProxies = append(proxyAuth.TrustedProxies, network)
+	}
+	return proxyAuth, nil
+}
+
 func startIndex(idx *index.BleveIndexer, batchSize int, libPath string, indexWorkers int) {
 	start := time.Now().Unix()
 	log.Printf("Indexing documents at %s, this can take a while depending on the size of your library.", libPath)
@@ -260,3 +309,35 @@ func getIndexes(fs afero.Fs, illustratedMinSize float64) (bleve.Index, bleve.Ind
 
 	return documentsIndex, authorsIndex, needsReindex
 }
+
+// mailTransport returns the sender chosen to deliver emails, or nil if it is not fully configured,
+// in which case features relying on email are disabled
+func mailTransport() webserver.Sender {
+	switch input.MailTransport {
+	case "sendmail":
+		if input.MailFrom != "" {
+			return &infrastructure.Sendmail{Path: input.SendmailPath, Address: input.MailFrom}
+		}
+	case "spool":
+		if input.MailFrom != "" && input.MailSpoolDir != "" {
+			return &infrastructure.Spool{Dir: input.MailSpoolDir, Address: input.MailFrom}
+		}
+	case "http":
+		if input.MailFrom != "" && input.MailAPIURL != "" {
+			return &infrastructure.HTTPMail{URL: input.MailAPIURL, Key: input.MailAPIKey, Address: input.MailFrom}
+		}
+	default:
+		if input.SmtpServer != "" && input.SmtpUser != "" && input.SmtpPassword != "" {
+			return &infrastructure.SMTP{
+				Server:   input.SmtpServer,
+				Port:     input.SmtpPort,
+				User:     input.SmtpUser,
+				Password: input.SmtpPassword,
+				Address:  input.MailFrom,
+			}
+		}
+		return nil
+	}
+	log.Printf("Warning: mail transport %s is not fully configured, email sending is disabled\n", input.MailTransport)
+	return nil
+}